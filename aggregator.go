@@ -0,0 +1,106 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Aggregator combines two neighboring bins, where a is the left and b the
+// right bin. The value and payload of the returned bin are used for the
+// merged bin, all other fields are ignored.
+type Aggregator interface {
+  Merge(a, b Bin) Bin
+}
+
+// Payload that is modified in place by an aggregator, which must be cloned
+// before it is kept in the undo log (see WithUndo)
+type Cloner interface {
+  Clone() interface{}
+}
+
+func cloneData(data interface{}) interface{} {
+  if c, ok := data.(Cloner); ok {
+    return c.Clone()
+  }
+  return data
+}
+
+// Merge bins with an aggregator instead of the sum function, which is
+// ignored and may be nil. This allows to combine arbitrary payloads stored
+// in Bin.Data.
+func WithAggregator(a Aggregator) Option {
+  return func(c *config) {
+    c.aggregator = a
+  }
+}
+
+// Merge value and payload of bin into dst, where left indicates that dst is
+// the left neighbor of bin
+func (binning *Binning) absorb(dst, bin *Bin, left bool) {
+  if a := binning.config.aggregator; a != nil {
+    var r Bin
+    if left {
+      r = a.Merge(*dst, *bin)
+    } else {
+      r = a.Merge(*bin, *dst)
+    }
+    dst.Y    = r.Y
+    dst.Data = r.Data
+  } else {
+    dst.Y = binning.Sum(*dst, *bin)
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Summary statistics of the values merged into a bin
+type BinStats struct {
+  Count float64
+  Sum   float64
+  Min   float64
+  Max   float64
+}
+
+// Mean of all values
+func (s BinStats) Mean() float64 {
+  return s.Sum/s.Count
+}
+
+// Aggregator that keeps BinStats as payload. Bins without payload are
+// treated as a single observation of their value Y. The value of a merged
+// bin is the sum of all values.
+type StatsAggregator struct{}
+
+func (StatsAggregator) stats(bin Bin) BinStats {
+  if s, ok := bin.Data.(BinStats); ok {
+    return s
+  }
+  return BinStats{Count: 1, Sum: bin.Y, Min: bin.Y, Max: bin.Y}
+}
+
+func (a StatsAggregator) Merge(l, r Bin) Bin {
+  s := a.stats(l)
+  t := a.stats(r)
+  s.Count += t.Count
+  s.Sum   += t.Sum
+  s.Min    = math.Min(s.Min, t.Min)
+  s.Max    = math.Max(s.Max, t.Max)
+  return Bin{Y: s.Sum, Data: s}
+}
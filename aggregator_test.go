@@ -0,0 +1,72 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestAggregator1(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4, 5}
+  y := []float64{4, 1, 7, 2, 3}
+
+  for _, options := range [][]Option{{}, {WithCircular()}} {
+    binning, err := New(x, y, nil, BinLessY, append(options, WithAggregator(StatsAggregator{}))...)
+    if err != nil {
+      t.Error(err); return
+    }
+    binning.FilterBins(1)
+
+    s, ok := binning.First.Data.(BinStats)
+    if !ok {
+      t.Error("test failed"); return
+    }
+    if s.Count != 5 || s.Sum != 17 || s.Min != 1 || s.Max != 7 || binning.First.Y != 17 {
+      t.Error("test failed")
+    }
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+type orderAggregator struct{}
+
+func (orderAggregator) Merge(a, b Bin) Bin {
+  if a.Lower > b.Lower {
+    panic("bins are not ordered")
+  }
+  return Bin{Y: a.Y + b.Y}
+}
+
+func TestAggregator2(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4, 5, 5}
+  y := []float64{4, 1, 7, 2, 3, 1}
+
+  binning, err := New(x, y, nil, BinLessY, WithAggregator(orderAggregator{}), WithDuplicatePolicy(CoalesceDuplicates))
+  if err != nil {
+    t.Error(err); return
+  }
+  binning.FilterBins(2)
+
+  if r := binning.AppendValues(nil); len(r) != 2 || r[0]+r[1] != 18 {
+    t.Error("test failed")
+  }
+}
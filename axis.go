@@ -0,0 +1,128 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "sort"
+import "time"
+
+/* -------------------------------------------------------------------------- */
+
+// Coordinate is a boundary on an arbitrary ordered axis, such as genome
+// positions or time stamps, that cannot be represented exactly as float64.
+// Sub returns the signed distance between two coordinates, which is used for
+// computing bin sizes.
+type Coordinate interface {
+  Less(b Coordinate) bool
+  Sub (b Coordinate) float64
+}
+
+// Coordinate on an integer axis, e.g. genome positions
+type Int64Coordinate int64
+
+func (a Int64Coordinate) Less(b Coordinate) bool {
+  return a < b.(Int64Coordinate)
+}
+
+func (a Int64Coordinate) Sub(b Coordinate) float64 {
+  return float64(a - b.(Int64Coordinate))
+}
+
+// Coordinate on a time axis, distances are measured in nanoseconds
+type TimeCoordinate time.Time
+
+func (a TimeCoordinate) Less(b Coordinate) bool {
+  return time.Time(a).Before(time.Time(b.(TimeCoordinate)))
+}
+
+func (a TimeCoordinate) Sub(b Coordinate) float64 {
+  return float64(time.Time(a).Sub(time.Time(b.(TimeCoordinate))))
+}
+
+/* -------------------------------------------------------------------------- */
+
+// AxisBinning is a binning with boundaries on an arbitrary ordered axis. The
+// underlying binning stores boundaries as distances to the first boundary,
+// while the original coordinates are kept for converting boundaries back
+// without loss of precision. The boundaries of the underlying binning must
+// not be changed with Reset or AddSample.
+type AxisBinning struct {
+  Binning     *Binning
+  coordinates []Coordinate
+  offsets     []float64
+}
+
+// Create a new binning with sorted boundaries x, see New
+func NewAxisBinning(x []Coordinate, y []float64, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*AxisBinning, error) {
+  if len(x) < 2 {
+    return nil, ErrTooFewBoundaries
+  }
+  offsets := make([]float64, len(x))
+  for i := 1; i < len(x); i++ {
+    if !x[i-1].Less(x[i]) {
+      return nil, fmt.Errorf("%w: coordinate `%v'", ErrUnsorted, x[i])
+    }
+    offsets[i] = x[i].Sub(x[0])
+  }
+  binning, err := New(offsets, y, sum, less, options...)
+  if err != nil {
+    return nil, err
+  }
+  r := AxisBinning{}
+  r.Binning     = binning
+  r.coordinates = append([]Coordinate{}, x...)
+  r.offsets     = offsets
+  return &r, nil
+}
+
+// Map an offset of the underlying binning back to the original coordinate
+func (obj *AxisBinning) coordinate(offset float64) Coordinate {
+  i := sort.SearchFloat64s(obj.offsets, offset)
+  if i == len(obj.offsets) {
+    i--
+  }
+  return obj.coordinates[i]
+}
+
+// Lower boundary of bin
+func (obj *AxisBinning) Lower(bin *Bin) Coordinate {
+  return obj.coordinate(bin.Lower)
+}
+
+// Upper boundary of bin
+func (obj *AxisBinning) Upper(bin *Bin) Coordinate {
+  return obj.coordinate(bin.Upper)
+}
+
+// Returns the boundaries of all bins
+func (obj *AxisBinning) Boundaries() []Coordinate {
+  r := []Coordinate{}
+  for at := obj.Binning.First; at != nil; at = obj.Binning.Next(at) {
+    r = append(r, obj.Lower(at))
+  }
+  if obj.Binning.Last != nil {
+    r = append(r, obj.Upper(obj.Binning.Last))
+  }
+  return r
+}
+
+// Find the bin containing x, see Binning.FindBin
+func (obj *AxisBinning) FindBin(x Coordinate) *Bin {
+  return obj.Binning.FindBin(x.Sub(obj.coordinates[0]))
+}
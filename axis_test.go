@@ -0,0 +1,63 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "time"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestAxis1(t *testing.T) {
+
+  x := []Coordinate{}
+  for _, v := range []int64{1<<60, 1<<60+1, 1<<60+3, 1<<60+10, 1<<60+11} {
+    x = append(x, Int64Coordinate(v))
+  }
+  binning, err := NewAxisBinning(x, nil, BinSum, BinLessSize)
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  binning.Binning.FilterBins(2)
+
+  r := binning.Boundaries()
+  if len(r) != 3 || r[0] != x[0] || r[1] != x[2] || r[2] != x[4] {
+    t.Error("test failed")
+  }
+  if bin := binning.FindBin(Int64Coordinate(1<<60+10)); binning.Lower(bin) != x[2] {
+    t.Error("test failed")
+  }
+  if _, err := NewAxisBinning([]Coordinate{x[1], x[0]}, nil, BinSum, BinLessSize); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestAxis2(t *testing.T) {
+
+  t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+  x  := []Coordinate{TimeCoordinate(t0), TimeCoordinate(t0.Add(time.Second)), TimeCoordinate(t0.Add(time.Hour))}
+  y  := []float64{1, 2}
+
+  binning, _ := NewAxisBinning(x, y, BinSum, BinLessSize)
+  if bin := binning.FindBin(TimeCoordinate(t0.Add(time.Minute))); bin == nil || bin.Y != 2 || bin.Size() != float64(time.Hour-time.Second) {
+    t.Error("test failed")
+  }
+  if !time.Time(binning.Upper(binning.Binning.Last).(TimeCoordinate)).Equal(t0.Add(time.Hour)) {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,264 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Partition bins into at most n contiguous groups with approximately equal
+// values. Groups are closed when their value is closest to the target,
+// which is the remaining value divided by the number of remaining groups.
+// Returns the indices of the first bin of each group.
+func balancedGroups(bins []Bin, n int) []int {
+  remaining := 0.0
+  for _, bin := range bins {
+    remaining += bin.Y
+  }
+  r := []int{0}
+  v := 0.0
+  for i, bin := range bins {
+    k      := n - len(r) + 1
+    target := remaining/float64(k)
+    if i > r[len(r)-1] && k > 1 && v + bin.Y > target && target - v <= v + bin.Y - target {
+      // close group before this bin
+      r          = append(r, i)
+      remaining -= v
+      v          = 0
+      k--
+      target     = remaining/float64(k)
+    }
+    v += bin.Y
+    if k > 1 && v >= target && i+1 < len(bins) {
+      // close group after this bin
+      r          = append(r, i+1)
+      remaining -= v
+      v          = 0
+    }
+  }
+  return r
+}
+
+// Partition bins into at most n groups, where single bins that exceed the
+// tolerance t of the target value, e.g. heavily tied samples, form groups
+// of their own. The remaining groups are distributed among the segments
+// between such bins proportionally to their values. Returns the indices of
+// the first bin of each group and the groups that are single bins.
+func balancedTies(bins []Bin, n int, t float64) ([]int, map[int]bool, error) {
+  fixed := make([]bool, len(bins))
+  m     := 0
+  for changed := true; changed; {
+    changed = false
+    total := 0.0
+    for i := range bins {
+      if !fixed[i] {
+        total += bins[i].Y
+      }
+    }
+    for i := range bins {
+      if !fixed[i] && n - m > 1 && bins[i].Y > (1+t)*total/float64(n - m) {
+        fixed[i] = true
+        changed  = true
+        m++
+      }
+    }
+  }
+  // segments [from, to) of bins between fixed bins
+  type segment struct {
+    from, to int
+    value    float64
+    groups   int
+  }
+  segments := []segment{}
+  for i := 0; i < len(bins); i++ {
+    if fixed[i] {
+      continue
+    }
+    s := segment{from: i}
+    for ; i < len(bins) && !fixed[i]; i++ {
+      s.value += bins[i].Y
+    }
+    s.to     = i
+    segments = append(segments, s)
+  }
+  if len(segments) > n - m {
+    return nil, nil, fmt.Errorf("%w: too many tied bins for %d bins", ErrInfeasible, n)
+  }
+  // allocate groups proportionally to values with the D'Hondt method,
+  // where each segment has at least one group
+  k := n - m
+  for i := range segments {
+    segments[i].groups = 1
+    k--
+  }
+  for ; k > 0; k-- {
+    j := -1
+    for i, s := range segments {
+      if j < 0 || s.value*float64(segments[j].groups+1) > segments[j].value*float64(s.groups+1) {
+        j = i
+      }
+    }
+    if j < 0 {
+      break
+    }
+    segments[j].groups++
+  }
+  r      := []int{}
+  single := make(map[int]bool)
+  j      := 0
+  for i := 0; i < len(bins); {
+    if fixed[i] {
+      single[len(r)] = true
+      r = append(r, i)
+      i++
+      continue
+    }
+    s := segments[j]; j++
+    for _, k := range balancedGroups(bins[s.from:s.to], s.groups) {
+      r = append(r, s.from + k)
+    }
+    i = s.to
+  }
+  return r, single, nil
+}
+
+// Partition bins into n groups with equal values, where bins are split if
+// no partition within tolerance t exists. Values within bins are assumed
+// to be uniformly distributed (see Split). Returns the new bins and the
+// indices of the first bin of each group.
+func balancedSplit(bins []Bin, n int, t float64) ([]Bin, []int) {
+  remaining := 0.0
+  for _, bin := range bins {
+    remaining += bin.Y
+  }
+  r     := []Bin{}
+  start := []int{0}
+  v     := 0.0
+  close := func() {
+    start      = append(start, len(r))
+    remaining -= v
+    v          = 0
+  }
+  for i := 0; i < len(bins); {
+    bin    := bins[i]
+    k      := n - len(start) + 1
+    target := remaining/float64(k)
+    switch {
+    case k == 1 || v + bin.Y < target:
+      r = append(r, bin); v += bin.Y; i++
+    case v + bin.Y <= (1+t)*target:
+      r = append(r, bin); v += bin.Y; i++
+      if i < len(bins) {
+        close()
+      }
+    case v > 0 && v >= (1-t)*target:
+      close()
+    default:
+      // split bin such that the group has the target value
+      x := bin.Lower + (target - v)/bin.Y*bin.Size()
+      if !(bin.Lower < x && x < bin.Upper) {
+        r = append(r, bin); v += bin.Y; i++
+        if i < len(bins) {
+          close()
+        }
+        continue
+      }
+      // same as Split, where the left bin keeps the payload
+      left   := bin
+      left.Upper = x
+      left.Y     = target - v
+      bins[i]    = Bin{Lower: x, Upper: bin.Upper, Y: bin.Y - left.Y}
+      r = append(r, left); v += left.Y
+      close()
+    }
+  }
+  return r, start
+}
+
+// Check that all groups except single groups are within tolerance t of
+// their average value
+func balancedCheck(bins []Bin, starts []int, single map[int]bool, t float64) error {
+  v := make([]float64, len(starts))
+  for k := range starts {
+    j := len(bins)
+    if k+1 < len(starts) {
+      j = starts[k+1]
+    }
+    for i := starts[k]; i < j; i++ {
+      v[k] += bins[i].Y
+    }
+  }
+  total, m := 0.0, 0
+  for k := range v {
+    if !single[k] {
+      total += v[k]; m++
+    }
+  }
+  target := total/float64(m)
+  for k := range v {
+    if !single[k] && math.Abs(v[k] - target) > t*target {
+      return fmt.Errorf("%w: bin %d has value %v, expected %v within a tolerance of %v", ErrInfeasible, k, v[k], target, t)
+    }
+  }
+  return nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Merge bins until at most n bins with approximately equal values remain,
+// where each bin must be within the relative tolerance t of the average
+// value (e.g. t = 0.05 for +/- 5%). Single bins that exceed the tolerance,
+// e.g. heavily tied samples of a binning created with FromSamples, are kept
+// as bins of their own and excluded from the average. If split is true,
+// bins are instead split where necessary to meet the tolerance, which
+// assumes that values are uniformly distributed within bins (see Split).
+// Bins are merged with the sum function or aggregator of the binning. If
+// the tolerance cannot be met, ErrInfeasible is returned and the binning is
+// left unchanged. Otherwise the binning is reset (see Reset) and all
+// pointers to bins become invalid.
+func (binning *Binning) FilterBinsBalanced(n int, t float64, split bool) error {
+  if n < 2 {
+    return fmt.Errorf("%w: number of bins must be at least two", ErrInvalidArgument)
+  }
+  if !(t >= 0) || math.IsInf(t, 0) {
+    return fmt.Errorf("%w: tolerance `%f'", ErrInvalidArgument, t)
+  }
+  if binning.config.circular {
+    return fmt.Errorf("%w: balanced binning of circular binnings is not supported", ErrInvalidArgument)
+  }
+  bins := []Bin{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    bins = append(bins, *at)
+  }
+  var starts []int
+  var single map[int]bool
+  if split {
+    bins, starts = balancedSplit(bins, n, t)
+  } else {
+    var err error
+    if starts, single, err = balancedTies(bins, n, t); err != nil {
+      return err
+    }
+  }
+  if err := balancedCheck(bins, starts, single, t); err != nil {
+    return err
+  }
+  return binning.resetGroups(bins, starts)
+}
@@ -0,0 +1,90 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "errors"
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBalance1(t *testing.T) {
+
+  data := []float64{}
+  for i := 1; i <= 20; i++ {
+    data = append(data, float64(i))
+  }
+  // heavily tied sample
+  for i := 1; i < 20; i++ {
+    data = append(data, 10)
+  }
+  binning, _ := FromSamples(data, BinLessY)
+  if err := binning.FilterBinsBalanced(5, 0.2, false); err != nil {
+    t.Error(err); return
+  }
+  if v := binning.AppendValues(nil); len(v) != 5 || v[2] != 20 || v[0] != 4 || v[1] != 5 || v[3] != 5 || v[4] != 5 {
+    t.Error("test failed")
+  }
+  if err := binning.Validate(); err != nil {
+    t.Error(err)
+  }
+}
+
+func TestBalance2(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4, 5}
+  y := []float64{1, 1, 8, 1, 1}
+
+  binning, _ := New(x, y, BinSum, BinLessY)
+  if err := binning.FilterBinsBalanced(4, 0.1, true); err != nil {
+    t.Error(err); return
+  }
+  for _, v := range binning.AppendValues(nil) {
+    if math.Abs(v - 3) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+  // the tolerance cannot be met without splitting
+  binning, _ = New(x, y, BinSum, BinLessY)
+  if err := binning.FilterBinsBalanced(4, 0.1, false); !errors.Is(err, ErrInfeasible) {
+    t.Error("test failed")
+  }
+  if len(binning.Bins) != 5 {
+    t.Error("test failed")
+  }
+}
+
+func TestBalance3(t *testing.T) {
+
+  binning, err := NewBuilder().
+    Boundaries([]float64{0, 1, 2, 3, 4, 5, 6, 7, 8}).
+    Values([]float64{1, 2, 1, 2, 1, 2, 1, 2}).
+    Strategy(BinSum, BinLessY).
+    Constraints(Constraints{MaxBins: 4, Tolerance: 0.01}).
+    Build()
+  if err != nil {
+    t.Error(err); return
+  }
+  if v := binning.AppendValues(nil); len(v) != 4 || v[0] != 3 || v[3] != 3 {
+    t.Error("test failed")
+  }
+  if _, err := NewBuilder().Constraints(Constraints{Tolerance: 0.1}).Build(); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,195 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// A single trade with time stamp (e.g. in seconds), price and volume
+type Tick struct {
+  Time   float64
+  Price  float64
+  Volume float64
+}
+
+// Measure that determines where bars are closed
+type BarKind int
+
+const (
+  // bars of fixed duration
+  TimeBars BarKind = iota
+  // bars are closed when the traded volume reaches the threshold
+  VolumeBars
+  // bars are closed when the traded value (price times volume) reaches
+  // the threshold
+  DollarBars
+)
+
+func (k BarKind) String() string {
+  switch k {
+  case TimeBars:   return "time"
+  case VolumeBars: return "volume"
+  case DollarBars: return "dollar"
+  }
+  return fmt.Sprintf("BarKind(%d)", int(k))
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Payload of a bar (see Bin.Data)
+type OHLC struct {
+  Open   float64
+  High   float64
+  Low    float64
+  Close  float64
+  Volume float64
+  // traded value, i.e. sum of price times volume
+  Dollar float64
+  Ticks  int
+}
+
+// Volume weighted average price
+func (bar OHLC) VWAP() float64 {
+  return bar.Dollar/bar.Volume
+}
+
+// Aggregator of bars with OHLC payloads. Bins without payload are empty
+// bars, e.g. time bars without trades. The value of a merged bar is the sum
+// of both values.
+type OHLCAggregator struct{}
+
+func (OHLCAggregator) Merge(a, b Bin) Bin {
+  r := Bin{Y: a.Y + b.Y}
+  s, ok1 := a.Data.(OHLC)
+  t, ok2 := b.Data.(OHLC)
+  switch {
+  case !ok1 && !ok2:
+  case !ok1:
+    r.Data = t
+  case !ok2:
+    r.Data = s
+  default:
+    s.High    = math.Max(s.High, t.High)
+    s.Low     = math.Min(s.Low,  t.Low)
+    s.Close   = t.Close
+    s.Volume += t.Volume
+    s.Dollar += t.Dollar
+    s.Ticks  += t.Ticks
+    r.Data    = s
+  }
+  return r
+}
+
+func (bar *OHLC) add(tick Tick) {
+  if bar.Ticks == 0 {
+    bar.Open, bar.High, bar.Low = tick.Price, tick.Price, tick.Price
+  }
+  bar.High    = math.Max(bar.High, tick.Price)
+  bar.Low     = math.Min(bar.Low,  tick.Price)
+  bar.Close   = tick.Price
+  bar.Volume += tick.Volume
+  bar.Dollar += tick.Price*tick.Volume
+  bar.Ticks++
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Aggregate ticks sorted by time into bars with OHLC payloads. Time bars
+// have a fixed duration given by threshold and start at a multiple of the
+// threshold, their values are the number of ticks. Volume and dollar bars
+// are closed as soon as the accumulated measure reaches the threshold,
+// where ticks with identical time stamps are never split, and their values
+// are the accumulated measure. Each bar extends to the first tick of the
+// next bar, the last bar ends right after the last tick. If all ticks fall
+// into a single bar, an empty bar is appended. Bars are merged
+// with OHLCAggregator, e.g. bars with the smallest values are merged first
+// by FilterBins if less is BinLessY.
+func NewBars(ticks []Tick, kind BarKind, threshold float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  if len(ticks) == 0 {
+    return nil, fmt.Errorf("%w: no ticks given", ErrInvalidArgument)
+  }
+  if !(threshold > 0) || math.IsInf(threshold, 0) {
+    return nil, fmt.Errorf("%w: threshold `%f'", ErrInvalidArgument, threshold)
+  }
+  for i := 1; i < len(ticks); i++ {
+    if ticks[i].Time < ticks[i-1].Time {
+      return nil, fmt.Errorf("%w: tick at time `%f'", ErrUnsorted, ticks[i].Time)
+    }
+  }
+  x    := []float64{}
+  bars := []OHLC{}
+  y    := []float64{}
+  switch kind {
+  case TimeBars:
+    t0 := math.Floor(ticks[0].Time/threshold)*threshold
+    for _, tick := range ticks {
+      i := int(math.Floor((tick.Time - t0)/threshold))
+      for len(bars) <= i {
+        x    = append(x, t0 + float64(len(bars))*threshold)
+        bars = append(bars, OHLC{})
+        y    = append(y, 0)
+      }
+      bars[i].add(tick)
+      y[i]++
+    }
+    x = append(x, t0 + float64(len(bars))*threshold)
+  case VolumeBars, DollarBars:
+    m := 0.0
+    for i, tick := range ticks {
+      if len(bars) == 0 || m >= threshold && tick.Time > ticks[i-1].Time {
+        x    = append(x, tick.Time)
+        bars = append(bars, OHLC{})
+        y    = append(y, 0)
+        m    = 0
+      }
+      v := tick.Volume
+      if kind == DollarBars {
+        v *= tick.Price
+      }
+      bars[len(bars)-1].add(tick)
+      y[len(y)-1] += v
+      m += v
+    }
+    x = append(x, math.Nextafter(ticks[len(ticks)-1].Time, math.Inf(1)))
+  default:
+    return nil, fmt.Errorf("%w: bar kind `%v'", ErrInvalidArgument, kind)
+  }
+  if len(bars) < 2 {
+    // all ticks fall into a single bar
+    if upper := x[len(x)-1]; kind == TimeBars {
+      x = append(x, upper + threshold)
+    } else {
+      x = append(x, math.Nextafter(upper, math.Inf(1)))
+    }
+    bars = append(bars, OHLC{})
+    y    = append(y, 0)
+  }
+  binning, err := New(x, y, nil, less, append(append([]Option{}, options...), WithAggregator(OHLCAggregator{}))...)
+  if err != nil {
+    return nil, err
+  }
+  for i := range binning.Bins {
+    if bars[i].Ticks > 0 {
+      binning.Bins[i].Data = bars[i]
+    }
+  }
+  return binning, nil
+}
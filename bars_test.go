@@ -0,0 +1,115 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBars1(t *testing.T) {
+
+  ticks := []Tick{
+    {0.5, 10, 1}, {1.0, 12, 2}, {1.0, 11, 1}, {2.5, 9, 3}, {4.2, 13, 1}, {4.9, 14, 2} }
+
+  binning, err := NewBars(ticks, VolumeBars, 3, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  // ticks with identical time stamps are not split
+  if binning.AppendValues(nil)[0] != 4 || len(binning.Bins) != 3 {
+    t.Error("test failed"); return
+  }
+  bar := binning.First.Data.(OHLC)
+  if bar.Open != 10 || bar.High != 12 || bar.Low != 10 || bar.Close != 11 || bar.Ticks != 3 {
+    t.Error("test failed")
+  }
+  binning.FilterBins(1)
+  bar = binning.First.Data.(OHLC)
+  if bar.Open != 10 || bar.High != 14 || bar.Low != 9 || bar.Close != 14 || bar.Volume != 10 || bar.Ticks != 6 {
+    t.Error("test failed")
+  }
+  if binning.First.Y != 10 {
+    t.Error("test failed")
+  }
+}
+
+func TestBars2(t *testing.T) {
+
+  ticks := []Tick{
+    {0.5, 10, 1}, {1.0, 12, 2}, {1.0, 11, 1}, {2.5, 9, 3}, {4.2, 13, 1}, {4.9, 14, 2} }
+
+  binning, err := NewBars(ticks, TimeBars, 2, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if x := binning.AppendBoundaries(nil); len(x) != 4 || x[0] != 0 || x[3] != 6 {
+    t.Error("test failed")
+  }
+  if bar := binning.Last.Data.(OHLC); bar.Open != 13 || bar.Close != 14 {
+    t.Error("test failed")
+  }
+  binning, err = NewBars(ticks, DollarBars, 30, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if bar := binning.First.Data.(OHLC); bar.Dollar != 45 || bar.VWAP() != 11.25 {
+    t.Error("test failed")
+  }
+}
+
+func TestBars3(t *testing.T) {
+
+  ticks := []Tick{
+    {0.5, 10, 1}, {1.0, 12, 2}, {1.5, 11, 1} }
+
+  // all ticks fall into a single bar
+  binning, err := NewBars(ticks, TimeBars, 2, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if x := binning.AppendBoundaries(nil); len(x) != 3 || x[0] != 0 || x[1] != 2 || x[2] != 4 {
+    t.Error("test failed")
+  }
+  if v := binning.AppendValues(nil); v[0] != 3 || v[1] != 0 || binning.Last.Data != nil {
+    t.Error("test failed")
+  }
+  binning, err = NewBars(ticks, VolumeBars, 100, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if v := binning.AppendValues(nil); len(v) != 2 || v[0] != 4 || v[1] != 0 {
+    t.Error("test failed")
+  }
+}
+
+func TestBars4(t *testing.T) {
+
+  ticks := []Tick{
+    {0.5, 10, 1}, {1.0, 12, 2}, {2.5, 11, 1} }
+
+  // options of the caller are not modified
+  options := make([]Option, 1, 4)
+  options[0] = WithEpsilon(0)
+  if _, err := NewBars(ticks, TimeBars, 1, BinLessY, options...); err != nil {
+    t.Error(err); return
+  }
+  if options[0:2][1] != nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,172 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// Binning2D is a binning of a rectangular grid, where adjacent columns or
+// rows are merged. The X and Y binnings contain the marginals of all columns
+// and rows, i.e. the value of a column is the sum (given by Sum) of all its
+// cells. In each step, the smallest column or row (given by Less on the
+// marginal bins) is merged with one of its neighbors.
+type Binning2D struct {
+  // marginal binnings of columns and rows
+  X     *Binning
+  Y     *Binning
+  // Z[i][j] is the value of the cell in column i and row j
+  Z   [][]float64
+  Sum   func(Bin, Bin) float64
+  Less  func(Bin, Bin) bool
+}
+
+// Create a new two-dimensional binning with sorted column boundaries x, row
+// boundaries y and cell values z, where z has len(x)-1 columns of length
+// len(y)-1. Options are passed to the marginal binnings.
+func New2D(x, y []float64, z [][]float64, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*Binning2D, error) {
+  if len(x) < 2 || len(y) < 2 {
+    return nil, ErrTooFewBoundaries
+  }
+  if len(z) != len(x)-1 {
+    return nil, fmt.Errorf("%w: number of columns", ErrLengthMismatch)
+  }
+  for i := 1; i < len(x); i++ {
+    if !(x[i-1] < x[i]) {
+      return nil, fmt.Errorf("%w: column boundary `%f'", ErrUnsorted, x[i])
+    }
+  }
+  for j := 1; j < len(y); j++ {
+    if !(y[j-1] < y[j]) {
+      return nil, fmt.Errorf("%w: row boundary `%f'", ErrUnsorted, y[j])
+    }
+  }
+  r := Binning2D{Sum: sum, Less: less}
+  r.Z = make([][]float64, len(z))
+  for i := range z {
+    if len(z[i]) != len(y)-1 {
+      return nil, fmt.Errorf("%w: number of rows in column `%d'", ErrLengthMismatch, i)
+    }
+    r.Z[i] = append([]float64{}, z[i]...)
+  }
+  // compute marginals
+  mx := make([]float64, len(x)-1)
+  my := make([]float64, len(y)-1)
+  for i := range r.Z {
+    for j := range r.Z[i] {
+      if j == 0 {
+        mx[i] = r.Z[i][j]
+      } else {
+        mx[i] = sum(Bin{Y: mx[i]}, Bin{Y: r.Z[i][j]})
+      }
+      if i == 0 {
+        my[j] = r.Z[i][j]
+      } else {
+        my[j] = sum(Bin{Y: my[j]}, Bin{Y: r.Z[i][j]})
+      }
+    }
+  }
+  if binning, err := New(x, mx, sum, less, options...); err != nil {
+    return nil, err
+  } else {
+    r.X = binning
+  }
+  if binning, err := New(y, my, sum, less, options...); err != nil {
+    return nil, err
+  } else {
+    r.Y = binning
+  }
+  return &r, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Merge columns and rows until at most nx columns and ny rows remain
+func (obj *Binning2D) FilterBins(nx, ny int) error {
+  if nx < 1 || ny < 1 {
+    return fmt.Errorf("%w: number of columns and rows must be positive", ErrInvalidArgument)
+  }
+  mx := len(obj.X.Bins) - obj.X.deleted
+  my := len(obj.Y.Bins) - obj.Y.deleted
+  for mx > nx || my > ny {
+    binning := obj.X
+    if mx <= nx || (my > ny && obj.Less(*obj.Y.Smallest, *obj.X.Smallest)) {
+      binning = obj.Y
+    }
+    bin := binning.Smallest
+    r, err := binning.Delete(bin)
+    if err != nil {
+      return err
+    }
+    // merge cells of the deleted column or row
+    if binning == obj.X {
+      for j := range obj.Z[r.index] {
+        obj.Z[r.index][j] = obj.Sum(Bin{Y: obj.Z[r.index][j]}, Bin{Y: obj.Z[bin.index][j]})
+      }
+      mx--
+    } else {
+      for i := range obj.Z {
+        obj.Z[i][r.index] = obj.Sum(Bin{Y: obj.Z[i][r.index]}, Bin{Y: obj.Z[i][bin.index]})
+      }
+      my--
+    }
+  }
+  obj.compact()
+  return nil
+}
+
+// Remove cells of deleted columns and rows. Compact keeps the order of bins
+// in the backing slices, hence cells are removed in the same way.
+func (obj *Binning2D) compact() {
+  z := make([][]float64, 0, len(obj.X.Bins)-obj.X.deleted)
+  for i := range obj.X.Bins {
+    if obj.X.Bins[i].Deleted {
+      continue
+    }
+    column := obj.Z[i][0:0]
+    for j := range obj.Y.Bins {
+      if !obj.Y.Bins[j].Deleted {
+        column = append(column, obj.Z[i][j])
+      }
+    }
+    z = append(z, column)
+  }
+  obj.Z = z
+  obj.X.Compact()
+  obj.Y.Compact()
+}
+
+// Returns the value of the cell in column i and row j together with the
+// column and row bins
+func (obj *Binning2D) Cell(i, j int) (*Bin, *Bin, float64) {
+  return &obj.X.Bins[i], &obj.Y.Bins[j], obj.Z[i][j]
+}
+
+// Find the cell containing (x, y), see Binning.FindBin. Negative indices are
+// returned if the position is outside the grid.
+func (obj *Binning2D) FindCell(x, y float64) (int, int) {
+  i, j := -1, -1
+  if bin := obj.X.FindBin(x); bin != nil {
+    i = int(bin.index)
+  }
+  if bin := obj.Y.FindBin(y); bin != nil {
+    j = int(bin.index)
+  }
+  return i, j
+}
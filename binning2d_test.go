@@ -0,0 +1,75 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBinning2D1(t *testing.T) {
+
+  x := []float64{0, 1, 2, 4, 8}
+  y := []float64{0, 3, 4, 10}
+  z := [][]float64{
+    {1, 2, 3},
+    {4, 5, 6},
+    {7, 8, 9},
+    {1, 1, 1} }
+
+  binning, err := New2D(x, y, z, BinSum, BinLessSize)
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  if binning.X.Bins[1].Y != 15 || binning.Y.Bins[2].Y != 19 {
+    t.Error("test failed")
+  }
+  if err := binning.FilterBins(2, 2); err != nil {
+    t.Error("test failed")
+  }
+  if len(binning.X.Bins) != 2 || len(binning.Y.Bins) != 2 || len(binning.Z) != 2 || len(binning.Z[0]) != 2 {
+    t.Error("test failed")
+  }
+  total := 0.0
+  for i := range binning.Z {
+    for j := range binning.Z[i] {
+      total += binning.Z[i][j]
+    }
+  }
+  if total != 48 {
+    t.Error("test failed")
+  }
+  // columns [0,4) and [4,8), rows [0,4) and [4,10)
+  if binning.X.Bins[0].Upper != 4 || binning.Y.Bins[0].Upper != 4 {
+    t.Error("test failed")
+  }
+  if i, j := binning.FindCell(5, 5); i != 1 || j != 1 || binning.Z[i][j] != 1 {
+    t.Error("test failed")
+  }
+}
+
+func TestBinning2D2(t *testing.T) {
+
+  if _, err := New2D([]float64{0, 1}, []float64{0, 1}, [][]float64{{1, 2}}, BinSum, BinLessSize); err == nil {
+    t.Error("test failed")
+  }
+  if _, err := New2D([]float64{0, 1}, []float64{1, 0}, [][]float64{{1}}, BinSum, BinLessSize); err == nil {
+    t.Error("test failed")
+  }
+}
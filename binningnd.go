@@ -0,0 +1,245 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// BinningND is the product of several one-dimensional binnings with a shared
+// tensor of cell values. Each axis is a binning of the marginals along this
+// axis, i.e. the value of a bin is the sum (given by Sum) of all cells in the
+// corresponding slice of the tensor. Merging two bins of an axis merges the
+// corresponding slices.
+type BinningND struct {
+  Axes  []*Binning
+  // cell values in row-major order, i.e. the index of the last
+  // axis varies fastest
+  Z     []float64
+  Sum     func(Bin, Bin) float64
+  Less    func(Bin, Bin) bool
+  // number of bins (including deleted bins) and strides of each axis
+  shape   []int
+  strides []int
+}
+
+// Create a new binning with sorted boundaries x[k] for axis k and cell
+// values z in row-major order. Options are passed to the binnings of all
+// axes.
+func NewND(x [][]float64, z []float64, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*BinningND, error) {
+  if len(x) == 0 {
+    return nil, fmt.Errorf("%w: no axes given", ErrInvalidArgument)
+  }
+  r := BinningND{Sum: sum, Less: less}
+  r.shape   = make([]int, len(x))
+  r.strides = make([]int, len(x))
+  n := 1
+  for k := len(x)-1; k >= 0; k-- {
+    if len(x[k]) < 2 {
+      return nil, ErrTooFewBoundaries
+    }
+    for i := 1; i < len(x[k]); i++ {
+      if !(x[k][i-1] < x[k][i]) {
+        return nil, fmt.Errorf("%w: boundary `%f' of axis `%d'", ErrUnsorted, x[k][i], k)
+      }
+    }
+    r.shape  [k] = len(x[k])-1
+    r.strides[k] = n
+    n *= r.shape[k]
+  }
+  if len(z) != n {
+    return nil, fmt.Errorf("%w: number of cells", ErrLengthMismatch)
+  }
+  r.Z = append([]float64{}, z...)
+  for k := range x {
+    binning, err := New(x[k], r.marginal(k), sum, less, options...)
+    if err != nil {
+      return nil, err
+    }
+    r.Axes = append(r.Axes, binning)
+  }
+  return &r, nil
+}
+
+// Compute marginal values along axis k
+func (obj *BinningND) marginal(k int) []float64 {
+  y    := make([]float64, obj.shape[k])
+  seen := make([]bool,    obj.shape[k])
+  for i, v := range obj.Z {
+    j := (i / obj.strides[k]) % obj.shape[k]
+    if seen[j] {
+      y[j] = obj.Sum(Bin{Y: y[j]}, Bin{Y: v})
+    } else {
+      y[j] = v; seen[j] = true
+    }
+  }
+  return y
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Number of bins of each axis
+func (obj *BinningND) Shape() []int {
+  r := make([]int, len(obj.Axes))
+  for k, axis := range obj.Axes {
+    r[k] = len(axis.Bins) - axis.deleted
+  }
+  return r
+}
+
+// Returns the value of the cell with bin indices i
+func (obj *BinningND) At(i ...int) float64 {
+  j := 0
+  for k := range i {
+    j += i[k]*obj.strides[k]
+  }
+  return obj.Z[j]
+}
+
+// Find the cell containing position x. Nil is returned if x is outside the
+// range of the binning.
+func (obj *BinningND) FindCell(x ...float64) []int {
+  if len(x) != len(obj.Axes) {
+    return nil
+  }
+  r := make([]int, len(x))
+  for k, axis := range obj.Axes {
+    bin := axis.FindBin(x[k])
+    if bin == nil {
+      return nil
+    }
+    r[k] = int(bin.index)
+  }
+  return r
+}
+
+// Returns a copy of the marginal binning along axis k
+func (obj *BinningND) Marginal(k int) (*Binning, error) {
+  if k < 0 || k >= len(obj.Axes) {
+    return nil, fmt.Errorf("%w: axis `%d'", ErrOutOfRange, k)
+  }
+  axis := obj.Axes[k]
+  return New(axis.AppendBoundaries(nil), axis.AppendValues(nil), axis.Sum, axis.lessFunc, axis.options...)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Merge bins of axis k until at most n bins remain
+func (obj *BinningND) FilterAxis(k, n int) error {
+  if k < 0 || k >= len(obj.Axes) {
+    return fmt.Errorf("%w: axis `%d'", ErrOutOfRange, k)
+  }
+  m := make([]int, len(obj.Axes))
+  for j := range m {
+    m[j] = len(obj.Z)
+  }
+  m[k] = n
+  return obj.FilterBins(m)
+}
+
+// Merge bins until at most n[k] bins remain for each axis k. In each step,
+// the smallest bin (given by Less) among all axes with more than n[k] bins
+// is merged.
+func (obj *BinningND) FilterBins(n []int) error {
+  if len(n) != len(obj.Axes) {
+    return fmt.Errorf("%w: number of axes", ErrLengthMismatch)
+  }
+  m := obj.Shape()
+  for k := range n {
+    if n[k] < 1 {
+      return fmt.Errorf("%w: number of bins must be positive", ErrInvalidArgument)
+    }
+  }
+  for {
+    k := -1
+    for j := range m {
+      if m[j] > n[j] && (k == -1 || obj.Less(*obj.Axes[j].Smallest, *obj.Axes[k].Smallest)) {
+        k = j
+      }
+    }
+    if k == -1 {
+      break
+    }
+    bin := obj.Axes[k].Smallest
+    r, err := obj.Axes[k].Delete(bin)
+    if err != nil {
+      return err
+    }
+    obj.mergeSlices(k, int(r.index), int(bin.index))
+    m[k]--
+  }
+  obj.compact()
+  return nil
+}
+
+// Merge slice j of axis k into slice i
+func (obj *BinningND) mergeSlices(k, i, j int) {
+  stride := obj.strides[k]
+  block  := stride*obj.shape[k]
+  for base := 0; base < len(obj.Z); base += block {
+    for l := 0; l < stride; l++ {
+      a := base + i*stride + l
+      b := base + j*stride + l
+      obj.Z[a] = obj.Sum(Bin{Y: obj.Z[a]}, Bin{Y: obj.Z[b]})
+    }
+  }
+}
+
+// Remove cells of deleted bins and compact all axes
+func (obj *BinningND) compact() {
+  // active bins of each axis
+  active := make([][]int, len(obj.Axes))
+  shape  := make([]int,   len(obj.Axes))
+  for k, axis := range obj.Axes {
+    for i := range axis.Bins {
+      if !axis.Bins[i].Deleted {
+        active[k] = append(active[k], i)
+      }
+    }
+    shape[k] = len(active[k])
+  }
+  n := 1
+  strides := make([]int, len(shape))
+  for k := len(shape)-1; k >= 0; k-- {
+    strides[k] = n
+    n *= shape[k]
+  }
+  z := make([]float64, n)
+  c := make([]int, len(shape))
+  for i := range z {
+    j := 0
+    for k := range c {
+      j += active[k][c[k]]*obj.strides[k]
+    }
+    z[i] = obj.Z[j]
+    // increment multi-index
+    for k := len(c)-1; k >= 0; k-- {
+      if c[k]++; c[k] < shape[k] {
+        break
+      }
+      c[k] = 0
+    }
+  }
+  obj.Z       = z
+  obj.shape   = shape
+  obj.strides = strides
+  for _, axis := range obj.Axes {
+    axis.Compact()
+  }
+}
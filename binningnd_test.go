@@ -0,0 +1,96 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBinningND1(t *testing.T) {
+
+  x := [][]float64{
+    {0, 1, 2, 4},
+    {0, 1, 3},
+    {0, 2, 3, 4, 8} }
+  z := make([]float64, 3*2*4)
+  for i := range z {
+    z[i] = float64(i)
+  }
+  binning, err := NewND(x, z, BinSum, BinLessSize)
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  // cell (1, 0, 2) has index 1*8 + 0*4 + 2
+  if binning.At(1, 0, 2) != 10 {
+    t.Error("test failed")
+  }
+  // marginal of first axis: sum of i*8 + 0..7
+  if binning.Axes[0].Bins[1].Y != 8*8+28 {
+    t.Error("test failed")
+  }
+  if err := binning.FilterAxis(2, 2); err != nil {
+    t.Error("test failed")
+  }
+  if s := binning.Shape(); s[0] != 3 || s[1] != 2 || s[2] != 2 || len(binning.Z) != 12 {
+    t.Error("test failed")
+  }
+  total := 0.0
+  for _, v := range binning.Z {
+    total += v
+  }
+  if total != 276 {
+    t.Error("test failed")
+  }
+  // axis 2 is now [0,4), [4,8)
+  if binning.At(0, 0, 0) != 0+1+2 || binning.At(2, 1, 1) != 23 {
+    t.Error("test failed")
+  }
+  if c := binning.FindCell(3, 2, 5); len(c) != 3 || c[0] != 2 || c[1] != 1 || c[2] != 1 {
+    t.Error("test failed")
+  }
+  if c := binning.FindCell(3, 2, 9); c != nil {
+    t.Error("test failed")
+  }
+  marginal, _ := binning.Marginal(2)
+  if len(marginal.Bins) != 2 || marginal.Bins[1].Y != 3+7+11+15+19+23 {
+    t.Error("test failed")
+  }
+}
+
+func TestBinningND2(t *testing.T) {
+
+  x := [][]float64{{0, 1, 2, 4, 8}, {0, 3, 4, 10}}
+  z := []float64{
+    1, 2, 3,
+    4, 5, 6,
+    7, 8, 9,
+    1, 1, 1 }
+  b1, _ := NewND(x, z, BinSum, BinLessSize)
+  b2, _ := New2D(x[0], x[1], [][]float64{z[0:3], z[3:6], z[6:9], z[9:12]}, BinSum, BinLessSize)
+  b1.FilterBins([]int{2, 2})
+  b2.FilterBins(2, 2)
+  for i := 0; i < 2; i++ {
+    for j := 0; j < 2; j++ {
+      if b1.At(i, j) != b2.Z[i][j] {
+        t.Error("test failed")
+      }
+    }
+  }
+}
@@ -0,0 +1,168 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "math/rand"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Stability of a boundary estimated by bootstrapping
+type BoundaryStability struct {
+  // boundary of the binning fitted to all samples
+  Boundary  float64
+  // fraction of bootstrap replicates containing exactly this boundary
+  Frequency float64
+  // mean and standard deviation of the nearest boundary in each replicate
+  Mean      float64
+  StdDev    float64
+}
+
+// Fit a binning to data with strategy and estimate the stability of its
+// boundaries from b bootstrap replicates of data. Replicates are drawn with
+// a random number generator initialized with seed.
+func Bootstrap(data []float64, strategy Strategy, b int, seed int64) ([]BoundaryStability, error) {
+  return BootstrapRand(data, strategy, b, rand.New(rand.NewSource(seed)))
+}
+
+// Same as Bootstrap, but replicates are drawn with rng, which must not be
+// used concurrently by other goroutines
+func BootstrapRand(data []float64, strategy Strategy, b int, rng *rand.Rand) ([]BoundaryStability, error) {
+  if b < 1 {
+    return nil, fmt.Errorf("%w: number of bootstrap replicates must be positive", ErrInvalidArgument)
+  }
+  if rng == nil {
+    return nil, fmt.Errorf("%w: no random number generator given", ErrInvalidArgument)
+  }
+  binning, err := strategy(data)
+  if err != nil {
+    return nil, err
+  }
+  x := binning.AppendBoundaries(nil)
+  r := make([]BoundaryStability, len(x))
+  for i := range x {
+    r[i].Boundary = x[i]
+  }
+  sample := make([]float64, len(data))
+  for k := 0; k < b; k++ {
+    for i := range sample {
+      sample[i] = data[rng.Intn(len(data))]
+    }
+    replicate, err := strategy(sample)
+    if err != nil {
+      return nil, fmt.Errorf("bootstrap replicate `%d': %w", k, err)
+    }
+    y := replicate.AppendBoundaries(nil)
+    for i := range r {
+      // find nearest boundary of the replicate
+      j := sort.SearchFloat64s(y, x[i])
+      if j == len(y) || (j > 0 && x[i]-y[j-1] < y[j]-x[i]) {
+        j--
+      }
+      if y[j] == x[i] {
+        r[i].Frequency++
+      }
+      // use StdDev for the sum of squares
+      r[i].Mean   += y[j]
+      r[i].StdDev += y[j]*y[j]
+    }
+  }
+  for i := range r {
+    r[i].Frequency /= float64(b)
+    r[i].Mean      /= float64(b)
+    r[i].StdDev     = math.Sqrt(math.Max(0, r[i].StdDev/float64(b) - r[i].Mean*r[i].Mean))
+  }
+  return r, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Fit a consensus binning to data, which keeps only boundaries found by
+// strategy in at least a fraction pi of b bootstrap replicates. The outer
+// boundaries of the binning fitted to all samples are always kept. The
+// binning fitted to all samples is reset to the consensus boundaries, where
+// the value of each bin is its number of samples. Replicates are drawn
+// with a random number generator initialized with seed.
+func StableBinning(data []float64, strategy Strategy, b int, pi float64, seed int64) (*Binning, error) {
+  return StableBinningRand(data, strategy, b, pi, rand.New(rand.NewSource(seed)))
+}
+
+// Same as StableBinning, but replicates are drawn with rng, which must not
+// be used concurrently by other goroutines
+func StableBinningRand(data []float64, strategy Strategy, b int, pi float64, rng *rand.Rand) (*Binning, error) {
+  if b < 1 {
+    return nil, fmt.Errorf("%w: number of bootstrap replicates must be positive", ErrInvalidArgument)
+  }
+  if !(pi > 0 && pi <= 1) {
+    return nil, fmt.Errorf("%w: fraction of replicates `%f'", ErrInvalidArgument, pi)
+  }
+  if rng == nil {
+    return nil, fmt.Errorf("%w: no random number generator given", ErrInvalidArgument)
+  }
+  binning, err := strategy(data)
+  if err != nil {
+    return nil, err
+  }
+  x := binning.AppendBoundaries(nil)
+  // number of replicates containing each boundary
+  count  := make(map[float64]int)
+  sample := make([]float64, len(data))
+  for k := 0; k < b; k++ {
+    for i := range sample {
+      sample[i] = data[rng.Intn(len(data))]
+    }
+    replicate, err := strategy(sample)
+    if err != nil {
+      return nil, fmt.Errorf("bootstrap replicate `%d': %w", k, err)
+    }
+    for _, v := range replicate.AppendBoundaries(nil) {
+      count[v]++
+    }
+  }
+  lo, hi := x[0], x[len(x)-1]
+  y := []float64{lo}
+  for v, c := range count {
+    if v > lo && v < hi && float64(c) >= pi*float64(b) {
+      y = append(y, v)
+    }
+  }
+  y = append(y, hi)
+  sort.Float64s(y)
+  // count samples in each bin
+  v := make([]float64, len(y)-1)
+  for _, s := range data {
+    if !isFinite(s) {
+      continue
+    }
+    // first boundary larger than s
+    i := sort.SearchFloat64s(y, s)
+    if i < len(y) && y[i] == s {
+      i++
+    }
+    if i > 0 && i < len(y) {
+      v[i-1]++
+    }
+  }
+  if err := binning.Reset(y, v); err != nil {
+    return nil, err
+  }
+  return binning, nil
+}
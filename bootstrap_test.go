@@ -0,0 +1,93 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math/rand"
+import   "reflect"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBootstrap1(t *testing.T) {
+
+  r    := rand.New(rand.NewSource(1))
+  data := []float64{}
+  // two well separated clusters
+  for i := 0; i < 200; i++ {
+    data = append(data, r.Float64())
+    data = append(data, 100 + r.Float64())
+  }
+  s, err := Bootstrap(data, EqualWidthStrategy(2), 20, 1)
+  if err != nil || len(s) != 3 {
+    t.Error("test failed"); return
+  }
+  // the boundary between both clusters is the smallest value of the
+  // second cluster, which varies between replicates
+  if s[1].Boundary < 100 || s[1].Mean < 100 || s[1].Mean > 101 || s[1].StdDev > 0.5 {
+    t.Error("test failed")
+  }
+  if s[1].Frequency <= 0 || s[1].Frequency > 1 {
+    t.Error("test failed")
+  }
+  if _, err := Bootstrap(data, EqualWidthStrategy(2), 0, 1); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestBootstrap2(t *testing.T) {
+
+  r    := rand.New(rand.NewSource(1))
+  data := make([]float64, 100)
+  for i := range data {
+    data[i] = r.NormFloat64()
+  }
+  s1, err1 := Bootstrap    (data, EqualCountStrategy(5), 10, 42)
+  s2, err2 := BootstrapRand(data, EqualCountStrategy(5), 10, rand.New(rand.NewSource(42)))
+  if err1 != nil || err2 != nil || !reflect.DeepEqual(s1, s2) {
+    t.Error("test failed")
+  }
+  if _, err := BootstrapRand(data, EqualCountStrategy(5), 10, nil); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestBootstrap3(t *testing.T) {
+
+  data := []float64{}
+  for i := 0; i < 50; i++ {
+    data = append(data, 1, 2, 3, 4)
+  }
+  // rare values create boundaries in few replicates
+  data = append(data, 1.5, 3.5)
+
+  binning, err := StableBinning(data, EqualCountStrategy(6), 50, 0.9, 1)
+  if err != nil {
+    t.Error(err); return
+  }
+  x := binning.AppendBoundaries(nil)
+  if len(x) != 5 || x[0] != 1 || x[1] != 2 || x[2] != 3 || x[3] != 4 {
+    t.Error("test failed")
+  }
+  if v := binning.AppendValues(nil); v[0] != 51 || v[3] != 50 {
+    t.Error("test failed")
+  }
+  if _, err := StableBinning(data, EqualCountStrategy(6), 50, 0, 1); err == nil {
+    t.Error("test failed")
+  }
+}
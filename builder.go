@@ -0,0 +1,213 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Constraints that must be satisfied by the binning returned by
+// Builder.Build. Zero values disable the respective constraint.
+type Constraints struct {
+  // maximum number of bins
+  MaxBins   int
+  // minimum value of each bin, bins with smaller values are merged with
+  // their neighbors
+  MinValue  float64
+  // relative tolerance of balanced bins with equal values, which requires
+  // MaxBins (see FilterBinsBalanced)
+  Tolerance float64
+  // split bins that exceed the tolerance (see FilterBinsBalanced)
+  Split     bool
+}
+
+// Builder configures a binning in stages, e.g.
+//   NewBuilder().Boundaries(x).Values(y).Strategy(BinSum, BinLessY).Build()
+// Each stage validates its arguments against the previous stages. The first
+// error is retained and returned by Build, all later stages are ignored.
+type Builder struct {
+  x           []float64
+  y           []float64
+  sum         func(Bin, Bin) float64
+  less        func(Bin, Bin) bool
+  constraints Constraints
+  options     []Option
+  err         error
+}
+
+func NewBuilder() *Builder {
+  return &Builder{}
+}
+
+func (obj *Builder) fail(err error) *Builder {
+  if obj.err == nil {
+    obj.err = err
+  }
+  return obj
+}
+
+// Set the boundaries of the initial bins
+func (obj *Builder) Boundaries(x []float64) *Builder {
+  if obj.err != nil {
+    return obj
+  }
+  if obj.x != nil {
+    return obj.fail(fmt.Errorf("%w: boundaries are already set", ErrInvalidArgument))
+  }
+  if len(x) < 3 {
+    return obj.fail(ErrTooFewBoundaries)
+  }
+  obj.x = x
+  return obj
+}
+
+// Set the values of the initial bins, which requires that boundaries are
+// set. A single value is used for all bins.
+func (obj *Builder) Values(y []float64) *Builder {
+  if obj.err != nil {
+    return obj
+  }
+  if obj.x == nil {
+    return obj.fail(fmt.Errorf("%w: values must be set after boundaries", ErrInvalidArgument))
+  }
+  if len(y) > 1 && len(y) != len(obj.x)-1 {
+    return obj.fail(ErrLengthMismatch)
+  }
+  obj.y = y
+  return obj
+}
+
+// Set the function for merging bin values and the less function, which
+// determines the order in which bins are merged. The sum function may be
+// nil if values are merged by an aggregator or computed lazily (see
+// WithAggregator and WithLazyValues).
+func (obj *Builder) Strategy(sum func(Bin, Bin) float64, less func(Bin, Bin) bool) *Builder {
+  if obj.err != nil {
+    return obj
+  }
+  if less == nil {
+    return obj.fail(fmt.Errorf("%w: less function is required", ErrInvalidArgument))
+  }
+  obj.sum  = sum
+  obj.less = less
+  return obj
+}
+
+// Set constraints of the resulting binning
+func (obj *Builder) Constraints(c Constraints) *Builder {
+  if obj.err != nil {
+    return obj
+  }
+  if c.MaxBins < 0 {
+    return obj.fail(fmt.Errorf("%w: maximum number of bins `%d'", ErrInvalidArgument, c.MaxBins))
+  }
+  if math.IsNaN(c.MinValue) || math.IsInf(c.MinValue, 0) {
+    return obj.fail(fmt.Errorf("%w: minimum value `%f'", ErrInvalidArgument, c.MinValue))
+  }
+  if !(c.Tolerance >= 0) || math.IsInf(c.Tolerance, 0) || (c.Tolerance > 0 && c.MaxBins == 0) {
+    return obj.fail(fmt.Errorf("%w: tolerance `%f'", ErrInvalidArgument, c.Tolerance))
+  }
+  obj.constraints = c
+  return obj
+}
+
+// Set options passed to New
+func (obj *Builder) Options(options ...Option) *Builder {
+  if obj.err != nil {
+    return obj
+  }
+  obj.options = append(obj.options, options...)
+  return obj
+}
+
+// Create the binning and merge bins until all constraints are satisfied.
+// An error is returned if any stage failed or if boundaries or strategy are
+// missing.
+func (obj *Builder) Build() (*Binning, error) {
+  if obj.err != nil {
+    return nil, obj.err
+  }
+  if obj.x == nil {
+    return nil, fmt.Errorf("%w: no boundaries given", ErrInvalidArgument)
+  }
+  if obj.less == nil {
+    return nil, fmt.Errorf("%w: no strategy given", ErrInvalidArgument)
+  }
+  if obj.sum == nil {
+    c := config{}
+    for _, option := range obj.options {
+      option(&c)
+    }
+    if c.aggregator == nil && c.yfunc == nil {
+      return nil, fmt.Errorf("%w: sum function is required", ErrInvalidArgument)
+    }
+  }
+  binning, err := New(obj.x, obj.y, obj.sum, obj.less, obj.options...)
+  if err != nil {
+    return nil, err
+  }
+  if err := binning.constrain(obj.constraints); err != nil {
+    return nil, err
+  }
+  return binning, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Merge bins until all constraints are satisfied
+func (binning *Binning) constrain(c Constraints) error {
+  if c.MaxBins > 0 && c.Tolerance > 0 {
+    if err := binning.FilterBinsBalanced(c.MaxBins, c.Tolerance, c.Split); err != nil {
+      return err
+    }
+  } else
+  if c.MaxBins > 0 {
+    if err := binning.FilterBins(c.MaxBins); err != nil {
+      return err
+    }
+  }
+  if c.MinValue != 0 {
+    if err := binning.filterMinValue(c.MinValue); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+// Merge the bin with the smallest value until all bins have a value of at
+// least v or a single bin remains
+func (binning *Binning) filterMinValue(v float64) error {
+  for binning.First != binning.Last {
+    bin := binning.First
+    for at := binning.First; at != nil; at = binning.Next(at) {
+      if at.Y < bin.Y {
+        bin = at
+      }
+    }
+    if bin.Y >= v {
+      break
+    }
+    if _, err := binning.Delete(bin); err != nil {
+      return err
+    }
+  }
+  binning.Compact()
+  return nil
+}
@@ -0,0 +1,86 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "errors"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBuilder1(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4, 5}
+  y := []float64{1, 5, 2, 8, 4}
+
+  binning, err := NewBuilder().Boundaries(x).Values(y).Strategy(BinSum, BinLessY).Constraints(Constraints{MaxBins: 4}).Build()
+  if err != nil || binning.String() != "[0.000000, 2.000000):6 [2.000000, 3.000000):2 [3.000000, 4.000000):8 [4.000000, 5.000000):4" {
+    t.Error("test failed")
+  }
+  binning, err = NewBuilder().Boundaries(x).Values(y).Strategy(BinSum, BinLessY).Constraints(Constraints{MinValue: 5}).Build()
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if at.Y < 5 {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestBuilder2(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3}
+
+  if _, err := NewBuilder().Values([]float64{1}).Boundaries(x).Strategy(BinSum, BinLessY).Build(); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+  if _, err := NewBuilder().Boundaries(x).Values([]float64{1, 2}).Strategy(BinSum, BinLessY).Build(); !errors.Is(err, ErrLengthMismatch) {
+    t.Error("test failed")
+  }
+  if _, err := NewBuilder().Boundaries(x[0:2]).Strategy(BinSum, BinLessY).Build(); !errors.Is(err, ErrTooFewBoundaries) {
+    t.Error("test failed")
+  }
+  if _, err := NewBuilder().Boundaries(x).Build(); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+  if _, err := NewBuilder().Boundaries(x).Strategy(BinSum, BinLessY).Constraints(Constraints{MaxBins: -1}).Build(); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+}
+
+func TestBuilder3(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3}
+  y := []float64{1, 2, 3}
+
+  // values are merged by the aggregator
+  binning, err := NewBuilder().Boundaries(x).Values(y).Strategy(nil, BinLessY).Options(WithAggregator(StatsAggregator{})).Constraints(Constraints{MaxBins: 2}).Build()
+  if err != nil {
+    t.Error(err); return
+  }
+  if v := binning.AppendValues(nil); len(v) != 2 || v[0] != 3 || v[1] != 3 {
+    t.Error("test failed")
+  }
+  if _, err := NewBuilder().Boundaries(x).Values(y).Strategy(nil, BinLessY).Build(); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+  if _, err := NewBuilder().Boundaries(x).Values(y).Strategy(BinSum, nil).Build(); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+}
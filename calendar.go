@@ -0,0 +1,145 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "sort"
+import "time"
+
+/* -------------------------------------------------------------------------- */
+
+// Calendar period used for generating bin boundaries
+type CalendarPeriod int
+
+const (
+  Hour CalendarPeriod = iota
+  Day
+  // weeks start on Monday
+  Week
+  Month
+  Quarter
+  Year
+)
+
+func (p CalendarPeriod) String() string {
+  switch p {
+  case Hour:    return "hour"
+  case Day:     return "day"
+  case Week:    return "week"
+  case Month:   return "month"
+  case Quarter: return "quarter"
+  case Year:    return "year"
+  }
+  return fmt.Sprintf("CalendarPeriod(%d)", int(p))
+}
+
+// Start of the period containing t in location loc
+func (p CalendarPeriod) start(t time.Time, loc *time.Location) time.Time {
+  t = t.In(loc)
+  y, m, d := t.Date()
+  switch p {
+  case Hour:
+    // hours are truncated in local time, which is correct
+    // also for time zones with fractional offsets
+    return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+  case Day:
+    return time.Date(y, m, d, 0, 0, 0, 0, loc)
+  case Week:
+    return time.Date(y, m, d - (int(t.Weekday())+6)%7, 0, 0, 0, 0, loc)
+  case Month:
+    return time.Date(y, m, 1, 0, 0, 0, 0, loc)
+  case Quarter:
+    return time.Date(y, m - (m-1)%3, 1, 0, 0, 0, 0, loc)
+  default:
+    return time.Date(y, 1, 1, 0, 0, 0, 0, loc)
+  }
+}
+
+// Start of the period following the period starting at t. Periods of a day
+// or longer are computed in local time, so that days have 23 or 25 hours at
+// daylight saving time transitions.
+func (p CalendarPeriod) next(t time.Time, loc *time.Location) time.Time {
+  y, m, d := t.Date()
+  switch p {
+  case Hour:
+    return t.Add(time.Hour)
+  case Day:
+    return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+  case Week:
+    return time.Date(y, m, d+7, 0, 0, 0, 0, loc)
+  case Month:
+    return time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+  case Quarter:
+    return time.Date(y, m+3, 1, 0, 0, 0, 0, loc)
+  default:
+    return time.Date(y+1, 1, 1, 0, 0, 0, 0, loc)
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Generate boundaries of all calendar periods in location loc that overlap
+// with [from, to]. The first boundary is the start of the period containing
+// from, the last boundary is the end of the period containing to.
+func CalendarBoundaries(from, to time.Time, p CalendarPeriod, loc *time.Location) ([]time.Time, error) {
+  if p < Hour || p > Year {
+    return nil, fmt.Errorf("%w: calendar period `%v'", ErrInvalidArgument, p)
+  }
+  if to.Before(from) {
+    return nil, fmt.Errorf("%w: end of time range is before its start", ErrInvalidArgument)
+  }
+  if loc == nil {
+    loc = time.UTC
+  }
+  r := []time.Time{p.start(from, loc)}
+  for !to.Before(r[len(r)-1]) {
+    r = append(r, p.next(r[len(r)-1], loc))
+  }
+  return r, nil
+}
+
+// Create a binning of calendar periods covering all events, where the value
+// of each bin is the number of events in this period. If all events fall
+// into a single period, an empty bin for the following period is added.
+func NewCalendarBinning(events []time.Time, p CalendarPeriod, loc *time.Location, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*TimeBinning, error) {
+  if len(events) == 0 {
+    return nil, fmt.Errorf("%w: no events given", ErrInvalidArgument)
+  }
+  if loc == nil {
+    loc = time.UTC
+  }
+  events = append([]time.Time{}, events...)
+  sort.Slice(events, func(i, j int) bool { return events[i].Before(events[j]) })
+
+  t, err := CalendarBoundaries(events[0], events[len(events)-1], p, loc)
+  if err != nil {
+    return nil, err
+  }
+  if len(t) < 3 {
+    t = append(t, p.next(t[len(t)-1], loc))
+  }
+  y := make([]float64, len(t)-1)
+  for i, j := 0, 0; i < len(events); i++ {
+    for !events[i].Before(t[j+1]) {
+      j++
+    }
+    y[j]++
+  }
+  return NewTimeBinning(t, y, sum, less, options...)
+}
@@ -0,0 +1,85 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "time"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestCalendar1(t *testing.T) {
+
+  loc, err := time.LoadLocation("Europe/Berlin")
+  if err != nil {
+    t.Skip("time zone database not available")
+  }
+  // daylight saving time starts on 2021-03-28
+  from := time.Date(2021, 3, 27, 15, 0, 0, 0, loc)
+  to   := time.Date(2021, 3, 29,  1, 0, 0, 0, loc)
+
+  r, err := CalendarBoundaries(from, to, Day, loc)
+  if err != nil || len(r) != 4 {
+    t.Error("test failed"); return
+  }
+  if r[2].Sub(r[1]) != 23*time.Hour || r[3].Sub(r[2]) != 24*time.Hour {
+    t.Error("test failed")
+  }
+  r, _ = CalendarBoundaries(from, from, Week, loc)
+  if len(r) != 2 || r[0].Weekday() != time.Monday || r[0].Day() != 22 {
+    t.Error("test failed")
+  }
+  r, _ = CalendarBoundaries(from, to, Hour, loc)
+  if len(r) != 35 {
+    t.Error("test failed")
+  }
+}
+
+func TestCalendar2(t *testing.T) {
+
+  r, _ := CalendarBoundaries(time.Date(2021, 2, 14, 0, 0, 0, 0, time.UTC), time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC), Quarter, nil)
+  if len(r) != 5 || r[0].Month() != time.January || r[4].Year() != 2022 {
+    t.Error("test failed")
+  }
+  events := []time.Time{
+    time.Date(2021, 5, 3, 0, 0, 0, 0, time.UTC),
+    time.Date(2021, 1, 9, 0, 0, 0, 0, time.UTC),
+    time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+    time.Date(2021, 3, 7, 0, 0, 0, 0, time.UTC) }
+  binning, err := NewCalendarBinning(events, Month, time.UTC, BinSum, BinLessY)
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  if y := binning.Binning.AppendValues(nil); len(y) != 5 || y[0] != 2 || y[1] != 0 || y[2] != 1 || y[4] != 1 {
+    t.Error("test failed")
+  }
+}
+
+func TestCalendar3(t *testing.T) {
+
+  events := []time.Time{
+    time.Date(2021, 5, 3, 0, 0, 0, 0, time.UTC),
+    time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC) }
+  binning, err := NewCalendarBinning(events, Month, nil, BinSum, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if y := binning.Binning.AppendValues(nil); len(y) != 2 || y[0] != 2 || y[1] != 0 {
+    t.Error("test failed")
+  }
+}
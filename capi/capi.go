@@ -0,0 +1,188 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Command capi exports smart binning as a C library. Build with
+//
+//   go build -buildmode=c-shared -o libsmartbinning.so ./capi
+//
+// which also generates the header libsmartbinning.h. Binnings are referenced
+// by positive handles. Functions return a negative value on failure, in
+// which case sb_last_error returns a description of the error. See
+// example.py for using the library from Python with ctypes.
+package main
+
+/* -------------------------------------------------------------------------- */
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import "sync"
+import "unsafe"
+
+import "github.com/pbenner/smartBinning"
+
+/* -------------------------------------------------------------------------- */
+
+const (
+  methodCount = 0
+  methodWidth = 1
+)
+
+var mutex     sync.Mutex
+var binnings  = make(map[C.longlong]*smartBinning.Binning)
+var next      = C.longlong(1)
+var lastError = C.CString("")
+
+func setError(err error) {
+  C.free(unsafe.Pointer(lastError))
+  lastError = C.CString(err.Error())
+}
+
+func toFloats(p *C.double, n C.int) []float64 {
+  if p == nil || n <= 0 {
+    return nil
+  }
+  return append([]float64{}, slice(p, n)...)
+}
+
+// View a C array as a slice
+func slice(p *C.double, n C.int) []float64 {
+  return (*[1 << 30]float64)(unsafe.Pointer(p))[:n:n]
+}
+
+func lessFunction(method C.int) func(smartBinning.Bin, smartBinning.Bin) bool {
+  if method == methodWidth {
+    return smartBinning.BinLessSize
+  }
+  return smartBinning.BinLessY
+}
+
+func register(binning *smartBinning.Binning, err error) C.longlong {
+  if err != nil {
+    setError(err)
+    return -1
+  }
+  handle := next
+  binnings[handle] = binning
+  next++
+  return handle
+}
+
+func lookup(handle C.longlong) *smartBinning.Binning {
+  binning, ok := binnings[handle]
+  if !ok {
+    setError(smartBinning.ErrInvalidArgument)
+  }
+  return binning
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Create a binning with nx boundaries x and values y (see New). Method 0
+// merges bins with the smallest value first, method 1 bins with the smallest
+// width.
+//export sb_new
+func sb_new(x *C.double, nx C.int, y *C.double, ny C.int, method C.int) C.longlong {
+  mutex.Lock()
+  defer mutex.Unlock()
+  return register(smartBinning.New(toFloats(x, nx), toFloats(y, ny), smartBinning.BinSum, lessFunction(method)))
+}
+
+// Create a binning from n raw samples (see FromSamples)
+//export sb_from_samples
+func sb_from_samples(data *C.double, n C.int, method C.int) C.longlong {
+  mutex.Lock()
+  defer mutex.Unlock()
+  return register(smartBinning.FromSamples(toFloats(data, n), lessFunction(method)))
+}
+
+// Merge bins until at most n bins remain
+//export sb_filter_bins
+func sb_filter_bins(handle C.longlong, n C.int) C.int {
+  mutex.Lock()
+  defer mutex.Unlock()
+  binning := lookup(handle)
+  if binning == nil {
+    return -1
+  }
+  if err := binning.FilterBins(int(n)); err != nil {
+    setError(err)
+    return -1
+  }
+  return 0
+}
+
+// Number of bins
+//export sb_len
+func sb_len(handle C.longlong) C.int {
+  mutex.Lock()
+  defer mutex.Unlock()
+  binning := lookup(handle)
+  if binning == nil {
+    return -1
+  }
+  n := 0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    n++
+  }
+  return C.int(n)
+}
+
+// Copy boundaries and values of at most n bins to the given arrays and
+// return the number of copied bins
+//export sb_export
+func sb_export(handle C.longlong, lower, upper, y *C.double, n C.int) C.int {
+  mutex.Lock()
+  defer mutex.Unlock()
+  binning := lookup(handle)
+  if binning == nil {
+    return -1
+  }
+  if n <= 0 {
+    return 0
+  }
+  l := slice(lower, n)
+  u := slice(upper, n)
+  v := slice(y,     n)
+  i := 0
+  for at := binning.First; at != nil && i < int(n); at = binning.Next(at) {
+    l[i], u[i], v[i] = at.Lower, at.Upper, at.Y
+    i++
+  }
+  return C.int(i)
+}
+
+// Release a binning
+//export sb_free
+func sb_free(handle C.longlong) {
+  mutex.Lock()
+  defer mutex.Unlock()
+  delete(binnings, handle)
+}
+
+// Description of the last error. The string is owned by the library and
+// valid until the next failing call.
+//export sb_last_error
+func sb_last_error() *C.char {
+  mutex.Lock()
+  defer mutex.Unlock()
+  return lastError
+}
+
+func main() {
+}
@@ -0,0 +1,62 @@
+# Copyright (C) 2016 Philipp Benner
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# This program is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+# GNU General Public License for more details.
+#
+# You should have received a copy of the GNU General Public License
+# along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+# Example of using libsmartbinning from Python, build the library with
+#
+#   go build -buildmode=c-shared -o libsmartbinning.so ./capi
+#
+# and run python3 capi/example.py [path to libsmartbinning.so]
+
+import ctypes
+import sys
+
+METHOD_COUNT = 0
+METHOD_WIDTH = 1
+
+lib = ctypes.CDLL(sys.argv[1] if len(sys.argv) > 1 else "./libsmartbinning.so")
+
+lib.sb_from_samples.argtypes = [ctypes.POINTER(ctypes.c_double), ctypes.c_int, ctypes.c_int]
+lib.sb_from_samples.restype  = ctypes.c_longlong
+lib.sb_filter_bins .argtypes = [ctypes.c_longlong, ctypes.c_int]
+lib.sb_filter_bins .restype  = ctypes.c_int
+lib.sb_len         .argtypes = [ctypes.c_longlong]
+lib.sb_len         .restype  = ctypes.c_int
+lib.sb_export      .argtypes = [ctypes.c_longlong] + 3*[ctypes.POINTER(ctypes.c_double)] + [ctypes.c_int]
+lib.sb_export      .restype  = ctypes.c_int
+lib.sb_free        .argtypes = [ctypes.c_longlong]
+lib.sb_last_error  .restype  = ctypes.c_char_p
+
+def check(r):
+    if r < 0:
+        raise RuntimeError(lib.sb_last_error().decode())
+    return r
+
+def smart_binning(samples, bins, method=METHOD_COUNT):
+    data   = (ctypes.c_double*len(samples))(*samples)
+    handle = check(lib.sb_from_samples(data, len(samples), method))
+    try:
+        check(lib.sb_filter_bins(handle, bins))
+        n     = check(lib.sb_len(handle))
+        lower = (ctypes.c_double*n)()
+        upper = (ctypes.c_double*n)()
+        y     = (ctypes.c_double*n)()
+        check(lib.sb_export(handle, lower, upper, y, n))
+        return list(zip(lower, upper, y))
+    finally:
+        lib.sb_free(handle)
+
+if __name__ == "__main__":
+    for lower, upper, y in smart_binning([1, 2, 2, 3, 10, 11], 2, METHOD_WIDTH):
+        print("[%g, %g): %g" % (lower, upper, y))
@@ -0,0 +1,169 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Order of categorical levels along the axis on which levels are grouped
+type LevelOrder int
+
+const (
+  // Order levels by the rate of positive targets
+  LevelsByRate LevelOrder = iota
+  // Order levels by their number of samples
+  LevelsByFrequency
+)
+
+// CategoricalDiscretizer groups the levels of a categorical variable, e.g.
+// for scorecards. Levels are sorted by target rate or frequency and placed
+// on an axis with one unit bin for each level, where the value of each bin
+// is its number of samples. Bins are merged with FilterBins, such that
+// groups consist of levels with similar rates or frequencies. The group of
+// each level is exported, so that a fitted discretizer can be stored, e.g.
+// as JSON.
+type CategoricalDiscretizer struct {
+  // maximum number of groups
+  Bins    int            `json:"-"`
+  Order   LevelOrder     `json:"-"`
+  options []Option
+  // group of each level
+  Groups  map[string]int `json:"groups"`
+  // number of samples and rate of positive targets of each group
+  Counts  []float64      `json:"counts"`
+  Rates   []float64      `json:"rates,omitempty"`
+}
+
+// Create a discretizer with at most n groups, options are passed to New
+func NewCategoricalDiscretizer(n int, order LevelOrder, options ...Option) *CategoricalDiscretizer {
+  return &CategoricalDiscretizer{Bins: n, Order: order, options: options}
+}
+
+// Number of groups
+func (obj *CategoricalDiscretizer) Len() int {
+  return len(obj.Counts)
+}
+
+// Learn groups from levels with binary target, which may be nil if levels
+// are ordered by frequency
+func (obj *CategoricalDiscretizer) Fit(levels []string, target []bool) error {
+  if obj.Bins < 1 {
+    return fmt.Errorf("%w: number of groups must be positive", ErrInvalidArgument)
+  }
+  if target == nil && obj.Order == LevelsByRate {
+    return fmt.Errorf("%w: ordering levels by rate requires a target", ErrInvalidArgument)
+  }
+  if target != nil && len(target) != len(levels) {
+    return ErrLengthMismatch
+  }
+  if len(levels) == 0 {
+    return fmt.Errorf("%w: no levels given", ErrInvalidArgument)
+  }
+  counts := make(map[string]GroupCounts)
+  names  := []string{}
+  for i, level := range levels {
+    c, ok := counts[level]
+    if !ok {
+      c = GroupCounts{}
+      counts[level] = c
+      names = append(names, level)
+    }
+    if target != nil && target[i] {
+      c[1]++
+    } else {
+      c[0]++
+    }
+  }
+  n := func(c GroupCounts) float64 {
+    return c[0] + c[1]
+  }
+  sort.Slice(names, func(i, j int) bool {
+    a, b := counts[names[i]], counts[names[j]]
+    switch {
+    case obj.Order == LevelsByRate && a[1]/n(a) != b[1]/n(b):
+      return a[1]/n(a) < b[1]/n(b)
+    case obj.Order == LevelsByFrequency && n(a) != n(b):
+      return n(a) < n(b)
+    }
+    return names[i] < names[j]
+  })
+  // one unit bin for each level
+  x := make([]float64, len(names)+1)
+  y := make([]float64, len(names))
+  for i := range names {
+    x[i+1] = float64(i+1)
+    y[i]   = n(counts[names[i]])
+  }
+  groups := []GroupCounts{}
+  if len(names) == 1 {
+    obj.Groups = map[string]int{names[0]: 0}
+    groups     = append(groups, counts[names[0]])
+  } else {
+    binning, err := New(x, y, nil, BinLessY, append(append([]Option{}, obj.options...), WithAggregator(GroupAggregator{}))...)
+    if err != nil {
+      return err
+    }
+    for i := range names {
+      binning.Bins[i].Data = counts[names[i]]
+    }
+    if err := binning.FilterBins(obj.Bins); err != nil {
+      return err
+    }
+    obj.Groups = make(map[string]int)
+    for at := binning.First; at != nil; at = binning.Next(at) {
+      for i := int(at.Lower); i < int(at.Upper); i++ {
+        obj.Groups[names[i]] = len(groups)
+      }
+      groups = append(groups, at.Data.(GroupCounts))
+    }
+  }
+  obj.Counts = make([]float64, len(groups))
+  obj.Rates  = nil
+  for i, c := range groups {
+    obj.Counts[i] = n(c)
+    if target != nil {
+      obj.Rates = append(obj.Rates, c[1]/n(c))
+    }
+  }
+  return nil
+}
+
+// Map levels to group indices. Levels that were not seen by Fit are mapped
+// to -1.
+func (obj *CategoricalDiscretizer) Transform(levels []string) []int {
+  r := make([]int, len(levels))
+  for i, level := range levels {
+    if j, ok := obj.Groups[level]; ok {
+      r[i] = j
+    } else {
+      r[i] = -1
+    }
+  }
+  return r
+}
+
+// Same as Fit followed by Transform
+func (obj *CategoricalDiscretizer) FitTransform(levels []string, target []bool) ([]int, error) {
+  if err := obj.Fit(levels, target); err != nil {
+    return nil, err
+  }
+  return obj.Transform(levels), nil
+}
@@ -0,0 +1,76 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestCategorical1(t *testing.T) {
+
+  levels := []string{"a", "a", "b", "b", "c", "c", "c", "d", "d", "d"}
+  target := []bool  {true, true, false, false, true, true, false, false, false, true}
+
+  d := NewCategoricalDiscretizer(2, LevelsByRate)
+  r, err := d.FitTransform(levels, target)
+  if err != nil {
+    t.Error(err); return
+  }
+  // rates: b = 0, d = 1/3, c = 2/3, a = 1
+  if d.Len() != 2 || r[2] != 0 || r[0] != 1 || d.Groups["b"] != d.Groups["d"] || d.Groups["a"] != d.Groups["c"] {
+    t.Error("test failed")
+  }
+  if d.Counts[0] != 5 || d.Rates[0] != 0.2 || d.Rates[1] != 0.8 {
+    t.Error("test failed")
+  }
+  if d.Transform([]string{"e"})[0] != -1 {
+    t.Error("test failed")
+  }
+}
+
+func TestCategorical2(t *testing.T) {
+
+  levels := []string{"a", "b", "b", "c", "c", "c", "d", "d", "d", "d"}
+
+  d := NewCategoricalDiscretizer(3, LevelsByFrequency)
+  if err := d.Fit(levels, nil); err != nil {
+    t.Error(err); return
+  }
+  if d.Len() != 3 || d.Groups["a"] != 0 || d.Groups["b"] != 0 || d.Groups["d"] != 2 || d.Rates != nil {
+    t.Error("test failed")
+  }
+  if err := NewCategoricalDiscretizer(3, LevelsByRate).Fit(levels, nil); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestCategorical3(t *testing.T) {
+
+  levels := []string{"a", "b", "b", "c", "c", "c"}
+
+  // options of the caller are not modified
+  options := make([]Option, 1, 4)
+  options[0] = WithEpsilon(0)
+  if err := NewCategoricalDiscretizer(2, LevelsByFrequency, options...).Fit(levels, nil); err != nil {
+    t.Error(err); return
+  }
+  if options[0:2][1] != nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,148 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "bufio"
+import "encoding/binary"
+import "fmt"
+import "io"
+import "io/ioutil"
+import "math"
+import "os"
+
+/* -------------------------------------------------------------------------- */
+
+// Parameters of BinChunked
+type ChunkConfig struct {
+  // number of input bins processed at once
+  ChunkSize int
+  // number of bins each chunk is reduced to before it is spilled
+  Budget    int
+  // directory of the spill file, the default directory for temporary
+  // files is used if empty
+  Dir       string
+}
+
+// Create a binning with at most n bins from a source with an arbitrary
+// number of bins, where upper is the upper boundary of the last bin. Input
+// bins are processed in chunks, which are reduced to a fixed budget of bins
+// and spilled to a temporary file. Afterwards all reduced chunks are
+// stitched together and merged until n bins remain, which requires memory
+// for the budget of all chunks. Bins of different chunks are only merged in
+// the final pass, hence the result may differ from filtering all bins at
+// once (see Streamer). Options are passed to New for each chunk and for the
+// final binning.
+func BinChunked(source BinSource, upper float64, n int, c ChunkConfig, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  if n < 1 {
+    return nil, fmt.Errorf("%w: number of bins must be positive", ErrInvalidArgument)
+  }
+  if c.ChunkSize < 2 || c.Budget < 1 || c.Budget > c.ChunkSize {
+    return nil, fmt.Errorf("%w: invalid chunk size `%d' or budget `%d'", ErrInvalidArgument, c.ChunkSize, c.Budget)
+  }
+  f, err := ioutil.TempFile(c.Dir, "smartBinning-")
+  if err != nil {
+    return nil, err
+  }
+  defer os.Remove(f.Name())
+  defer f.Close()
+
+  w := bufio.NewWriter(f)
+  x := make([]float64, 0, c.ChunkSize+2)
+  y := make([]float64, 0, c.ChunkSize+2)
+  m := 0
+  // reduce the first k bins of the current chunk, where u is the upper
+  // boundary of the last bin
+  spill := func(k int, u float64) error {
+    binning, err := New(append(x[0:k:k], u), y[0:k], sum, less, options...)
+    if err != nil {
+      return err
+    }
+    if err := binning.FilterBins(c.Budget); err != nil {
+      return err
+    }
+    for at := binning.First; at != nil; at = binning.Next(at) {
+      if err := binary.Write(w, binary.LittleEndian, [2]float64{at.Lower, at.Y}); err != nil {
+        return err
+      }
+      m++
+    }
+    x = x[0:copy(x, x[k:])]
+    y = y[0:copy(y, y[k:])]
+    return nil
+  }
+  last := math.Inf(-1)
+  for {
+    xi, yi, ok, err := source()
+    if err != nil {
+      return nil, err
+    }
+    if !ok {
+      break
+    }
+    if xi <= last {
+      return nil, fmt.Errorf("%w: `%f' follows `%f'", ErrUnsorted, xi, last)
+    }
+    last = xi
+    x = append(x, xi)
+    y = append(y, yi)
+    // spill a full chunk once the next chunk has at least two bins
+    if len(y) == c.ChunkSize+2 {
+      if err := spill(c.ChunkSize, x[c.ChunkSize]); err != nil {
+        return nil, err
+      }
+    }
+  }
+  if upper <= last {
+    return nil, fmt.Errorf("%w: upper boundary `%f'", ErrUnsorted, upper)
+  }
+  if m == 0 {
+    // all bins fit into a single chunk
+    binning, err := New(append(x, upper), y, sum, less, options...)
+    if err != nil {
+      return nil, err
+    }
+    return binning, binning.FilterBins(n)
+  }
+  if err := spill(len(y), upper); err != nil {
+    return nil, err
+  }
+  if err := w.Flush(); err != nil {
+    return nil, err
+  }
+  // stitch reduced chunks
+  if _, err := f.Seek(0, io.SeekStart); err != nil {
+    return nil, err
+  }
+  r  := bufio.NewReader(f)
+  x   = make([]float64, m+1)
+  y   = make([]float64, m)
+  for i := 0; i < m; i++ {
+    v := [2]float64{}
+    if err := binary.Read(r, binary.LittleEndian, &v); err != nil {
+      return nil, err
+    }
+    x[i], y[i] = v[0], v[1]
+  }
+  x[m] = upper
+  binning, err := New(x, y, sum, less, options...)
+  if err != nil {
+    return nil, err
+  }
+  return binning, binning.FilterBins(n)
+}
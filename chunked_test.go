@@ -0,0 +1,77 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "errors"
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestChunked1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 1001)
+  y := make([]float64, 1000)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(r.Intn(10))
+  }
+  b1, err := BinChunked(SliceSource(x[0:1000], y), x[1000], 10, ChunkConfig{ChunkSize: 1000, Budget: 1000}, BinSum, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  b2, _ := New(x, y, BinSum, BinLessY)
+  b2.FilterBins(10)
+  if b1.String() != b2.String() {
+    t.Error("test failed")
+  }
+  b3, err := BinChunked(SliceSource(x[0:1000], y), x[1000], 10, ChunkConfig{ChunkSize: 99, Budget: 20}, BinSum, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if len(b3.Bins) != 10 || b3.First.Lower != x[0] || b3.Last.Upper != x[1000] || b3.Aggregate(x[0], x[1000]) != b2.Aggregate(x[0], x[1000]) {
+    t.Error("test failed")
+  }
+  if _, err := BinChunked(SliceSource([]float64{2, 1}, []float64{1, 1}), 3, 1, ChunkConfig{ChunkSize: 2, Budget: 1}, BinSum, BinLessY); !errors.Is(err, ErrUnsorted) {
+    t.Error("test failed")
+  }
+}
+
+func TestChunked2(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+  y := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+  // input lengths that are not a multiple of the chunk size
+  for k := 2; k <= 10; k++ {
+    for _, c := range []ChunkConfig{{ChunkSize: 4, Budget: 2}, {ChunkSize: 3, Budget: 1}, {ChunkSize: 2, Budget: 1}} {
+      binning, err := BinChunked(SliceSource(x[0:k], y[0:k]), x[k], 1, c, BinSum, BinLessY)
+      if err != nil {
+        t.Error(err); return
+      }
+      if len(binning.Bins) != 1 || binning.First.Lower != x[0] || binning.Last.Upper != x[k] || binning.First.Y != float64(k*(k+1)/2) {
+        t.Error("test failed")
+      }
+    }
+  }
+  if _, err := BinChunked(SliceSource(x[0:1], y[0:1]), x[1], 1, ChunkConfig{ChunkSize: 4, Budget: 2}, BinSum, BinLessY); !errors.Is(err, ErrTooFewBoundaries) {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,81 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Bin boundaries are defined on a circular domain, such as angles or the time
+// of day, where the first and the last bin are neighbors. The length of the
+// domain is given by the distance between the first and the last boundary.
+// A bin that is merged across the end of the domain is represented either as
+// the last bin with an upper boundary beyond the domain or as the first bin
+// with a lower boundary below the domain. The neighbors used for tie-breaking
+// (see WithTieBreaking) do not wrap around.
+func WithCircular() Option {
+  return func(c *config) {
+    c.circular = true
+  }
+}
+
+// Returns true if the binning is defined on a circular domain
+func (binning *Binning) Circular() bool {
+  return binning.period > 0
+}
+
+// Map x to the current domain [First.Lower, First.Lower + period)
+func (binning *Binning) wrap(x float64) float64 {
+  lower := binning.First.Lower
+  if x >= lower && x < lower + binning.period {
+    return x
+  }
+  y := math.Mod(x - lower, binning.period)
+  if y < 0 {
+    y += binning.period
+  }
+  return lower + y
+}
+
+// Merge the first or last bin, which has already been removed from the
+// linked list, with one of its neighbors. The outer neighbor is the bin at
+// the opposite end of the domain.
+func (binning *Binning) mergeCircular(bin, prev, next *Bin) *Bin {
+  if prev == nil {
+    // first bin, the left neighbor is the last bin
+    if binning.mergeLeft(binning.Last, next) {
+      binning.absorb(binning.Last, bin, true)
+      binning.Last.Upper = bin.Upper + binning.period
+      return binning.Last
+    }
+    binning.absorb(next, bin, false)
+    next.Lower = bin.Lower
+    return next
+  } else {
+    // last bin, the right neighbor is the first bin
+    if binning.mergeLeft(prev, binning.First) {
+      binning.absorb(prev, bin, true)
+      prev.Upper = bin.Upper
+      return prev
+    }
+    binning.absorb(binning.First, bin, false)
+    binning.First.Lower = bin.Lower - binning.period
+    return binning.First
+  }
+}
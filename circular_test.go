@@ -0,0 +1,74 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestCircular1(t *testing.T) {
+
+  x := []float64{0, 10, 90, 180, 350, 360}
+  y := []float64{1, 2, 3, 4, 5}
+
+  binning, _ := New(x, y, BinSum, BinLessSize, WithCircular())
+  if !binning.Circular() {
+    t.Error("test failed")
+  }
+  // the first bin [0,10) is merged with the last bin [350,360)
+  r, err := binning.Delete(binning.First)
+  if err != nil || r.Lower != 350 || r.Upper != 370 || r.Y != 6 {
+    t.Error("test failed")
+  }
+  if bin := binning.FindBin(5); bin != r {
+    t.Error("test failed")
+  }
+  if bin := binning.FindBin(-5); bin != r {
+    t.Error("test failed")
+  }
+  if bin := binning.FindBin(375); bin == nil || bin.Lower != 10 {
+    t.Error("test failed")
+  }
+  binning.Update()
+  if bin := binning.FindBin(725); bin == nil || bin.Lower != 350 {
+    t.Error("test failed")
+  }
+  if bx := binning.AppendBoundaries(nil); len(bx) != 5 || bx[0] != 10 || bx[4] != 370 {
+    t.Error("test failed")
+  }
+}
+
+func TestCircular2(t *testing.T) {
+
+  x := []float64{0, 10, 20, 180, 340, 355, 360}
+  y := []float64{1, 2, 3, 4, 5, 6}
+
+  binning, _ := New(x, y, BinSum, BinLessSize, WithCircular())
+  binning.FilterBins(3)
+  // the last bins [340,355) and [355,360) are merged across the end of the
+  // domain with the first bins [0,10) and [10,20)
+  bx := binning.AppendBoundaries(nil)
+  if len(bx) != 4 || bx[0] != -20 || bx[1] != 20 || bx[3] != 340 {
+    t.Error("test failed")
+  }
+  if bin := binning.FindBin(350); bin == nil || bin.Y != 1+2+5+6 {
+    t.Error("test failed")
+  }
+}
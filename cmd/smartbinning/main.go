@@ -0,0 +1,222 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/* -------------------------------------------------------------------------- */
+
+import "bufio"
+import "encoding/csv"
+import "encoding/json"
+import "flag"
+import "fmt"
+import "io"
+import "os"
+import "strconv"
+import "strings"
+
+import "github.com/pbenner/smartBinning"
+
+/* -------------------------------------------------------------------------- */
+
+type options struct {
+  bins     int
+  method   string
+  minCount float64
+  input    string
+  format   string
+}
+
+type outputBin struct {
+  Lower float64 `json:"lower"`
+  Upper float64 `json:"upper"`
+  Y     float64 `json:"y"`
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Read one sample per line, empty lines and lines starting with # are
+// ignored
+func readSamples(reader io.Reader) ([]float64, error) {
+  r := []float64{}
+  scanner := bufio.NewScanner(reader)
+  for scanner.Scan() {
+    line := strings.TrimSpace(scanner.Text())
+    if line == "" || strings.HasPrefix(line, "#") {
+      continue
+    }
+    v, err := strconv.ParseFloat(strings.Split(line, ",")[0], 64)
+    if err != nil {
+      return nil, err
+    }
+    r = append(r, v)
+  }
+  return r, scanner.Err()
+}
+
+// Read lower,upper,y triples of contiguous bins
+func readBins(reader io.Reader) ([]float64, []float64, error) {
+  records := csv.NewReader(reader)
+  records.Comment = '#'
+  x := []float64{}
+  y := []float64{}
+  for {
+    record, err := records.Read()
+    if err == io.EOF {
+      break
+    }
+    if err != nil {
+      return nil, nil, err
+    }
+    if len(record) != 3 {
+      return nil, nil, fmt.Errorf("expected lower,upper,y triples")
+    }
+    v := [3]float64{}
+    for i := range record {
+      if v[i], err = strconv.ParseFloat(strings.TrimSpace(record[i]), 64); err != nil {
+        return nil, nil, err
+      }
+    }
+    if n := len(x); n > 0 && x[n-1] != v[0] {
+      return nil, nil, fmt.Errorf("bins are not contiguous at `%f'", v[0])
+    }
+    if n := len(x); n > 0 {
+      x = x[0:n-1]
+    }
+    x = append(x, v[0], v[1])
+    y = append(y, v[2])
+  }
+  return x, y, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+func newBinning(opts options, stdin io.Reader) (*smartBinning.Binning, error) {
+  less, err := smartBinning.LookupLess(opts.method)
+  if err != nil {
+    return nil, fmt.Errorf("invalid method `%s'", opts.method)
+  }
+  switch opts.input {
+  case "samples":
+    data, err := readSamples(stdin)
+    if err != nil {
+      return nil, err
+    }
+    return smartBinning.FromSamples(data, less)
+  case "bins":
+    x, y, err := readBins(stdin)
+    if err != nil {
+      return nil, err
+    }
+    return smartBinning.New(x, y, smartBinning.BinSum, less)
+  default:
+    return nil, fmt.Errorf("invalid input format `%s'", opts.input)
+  }
+}
+
+// Merge bins until at most opts.bins bins remain and all bins have a value
+// of at least opts.minCount
+func filterBins(binning *smartBinning.Binning, opts options) error {
+  if opts.bins > 0 {
+    if err := binning.FilterBins(opts.bins); err != nil {
+      return err
+    }
+  }
+  if opts.minCount > 0 {
+    for binning.First != binning.Last {
+      // find bin with the smallest value
+      bin := binning.First
+      for at := binning.First; at != nil; at = binning.Next(at) {
+        if at.Y < bin.Y {
+          bin = at
+        }
+      }
+      if bin.Y >= opts.minCount {
+        break
+      }
+      if _, err := binning.Delete(bin); err != nil {
+        return err
+      }
+    }
+    binning.Compact()
+  }
+  return nil
+}
+
+func writeBins(writer io.Writer, binning *smartBinning.Binning, format string) error {
+  bins := []outputBin{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    bins = append(bins, outputBin{at.Lower, at.Upper, at.Y})
+  }
+  switch format {
+  case "csv":
+    w := csv.NewWriter(writer)
+    w.Write([]string{"lower", "upper", "y"})
+    for _, bin := range bins {
+      w.Write([]string{
+        strconv.FormatFloat(bin.Lower, 'g', -1, 64),
+        strconv.FormatFloat(bin.Upper, 'g', -1, 64),
+        strconv.FormatFloat(bin.Y,     'g', -1, 64) })
+    }
+    w.Flush()
+    return w.Error()
+  case "json":
+    e := json.NewEncoder(writer)
+    e.SetIndent("", "  ")
+    return e.Encode(bins)
+  default:
+    return fmt.Errorf("invalid output format `%s'", format)
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+  opts  := options{}
+  flags := flag.NewFlagSet("smartbinning", flag.ContinueOnError)
+  flags.SetOutput(stderr)
+  flags.IntVar    (&opts.bins,     "bins",      10,        "maximum number of bins")
+  flags.StringVar (&opts.method,   "method",    "count",   "merge bins with the smallest count (count) or width (width) first, or use any less function: " + strings.Join(smartBinning.LessNames(), ", "))
+  flags.Float64Var(&opts.minCount, "min-count", 0,         "minimum value of each bin")
+  flags.StringVar (&opts.input,    "input",     "samples", "input format: one sample per line (samples) or lower,upper,y triples (bins)")
+  flags.StringVar (&opts.format,   "format",    "csv",     "output format: csv or json")
+  flags.Usage = func() {
+    fmt.Fprintf(stderr, "Usage: smartbinning [OPTION]...\n\n")
+    fmt.Fprintf(stderr, "Read samples or bins from stdin and merge bins adaptively.\n\n")
+    flags.PrintDefaults()
+  }
+  if err := flags.Parse(args); err != nil {
+    return 2
+  }
+  binning, err := newBinning(opts, stdin)
+  if err != nil {
+    fmt.Fprintf(stderr, "smartbinning: %v\n", err)
+    return 1
+  }
+  if err := filterBins(binning, opts); err != nil {
+    fmt.Fprintf(stderr, "smartbinning: %v\n", err)
+    return 1
+  }
+  if err := writeBins(stdout, binning, opts.format); err != nil {
+    fmt.Fprintf(stderr, "smartbinning: %v\n", err)
+    return 1
+  }
+  return 0
+}
+
+func main() {
+  os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
@@ -0,0 +1,68 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/* -------------------------------------------------------------------------- */
+
+import   "bytes"
+import   "encoding/json"
+import   "strings"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestRun1(t *testing.T) {
+
+  var stdout, stderr bytes.Buffer
+  stdin := strings.NewReader("1\n2\n2\n3\n10\n11\n")
+
+  if r := run([]string{"--bins", "2", "--method", "width"}, stdin, &stdout, &stderr); r != 0 {
+    t.Error("test failed"); return
+  }
+  lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+  if len(lines) != 3 || lines[0] != "lower,upper,y" || lines[1] != "1,3,3" {
+    t.Error("test failed")
+  }
+}
+
+func TestRun2(t *testing.T) {
+
+  var stdout, stderr bytes.Buffer
+  stdin := strings.NewReader("0,1,5\n1,2,1\n2,4,1\n4,5,6\n")
+
+  if r := run([]string{"--input", "bins", "--bins", "0", "--min-count", "2", "--format", "json"}, stdin, &stdout, &stderr); r != 0 {
+    t.Error("test failed"); return
+  }
+  bins := []outputBin{}
+  if err := json.Unmarshal(stdout.Bytes(), &bins); err != nil {
+    t.Error("test failed"); return
+  }
+  if len(bins) != 3 || bins[1].Lower != 1 || bins[1].Upper != 4 || bins[1].Y != 2 {
+    t.Error("test failed")
+  }
+}
+
+func TestRun3(t *testing.T) {
+
+  var stdout, stderr bytes.Buffer
+  if r := run([]string{"--input", "bins"}, strings.NewReader("0,1,5\n2,3,1\n"), &stdout, &stderr); r != 1 {
+    t.Error("test failed")
+  }
+  if r := run([]string{"--method", "foo"}, strings.NewReader("1\n2\n"), &stdout, &stderr); r != 1 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,71 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Interval [Lower, Upper]
+type Interval struct {
+  Lower float64
+  Upper float64
+}
+
+// Method for computing confidence intervals of counts
+type IntervalMethod int
+
+const (
+  // exact Poisson interval based on the chi-square distribution
+  Garwood IntervalMethod = iota
+  // normal approximation Y +/- z sqrt(Y), truncated at zero
+  NormalApproximation
+)
+
+// Compute confidence intervals with the given level (e.g. 0.95) for the
+// values of all bins, which are assumed to be Poisson distributed counts.
+// Intervals are returned in the order of the bins.
+func (binning *Binning) ConfidenceIntervals(level float64, method IntervalMethod) ([]Interval, error) {
+  if !(level > 0 && level < 1) {
+    return nil, fmt.Errorf("%w: confidence level `%f'", ErrInvalidArgument, level)
+  }
+  alpha := 1 - level
+  r     := []Interval{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    k := at.Y
+    if !(k >= 0) || math.IsInf(k, 1) {
+      return nil, fmt.Errorf("%w: bin value `%f' is not a count", ErrInvalidArgument, k)
+    }
+    switch method {
+    case Garwood:
+      i := Interval{0, gammaQuantile(1-alpha/2, k+1)}
+      if k > 0 {
+        i.Lower = gammaQuantile(alpha/2, k)
+      }
+      r = append(r, i)
+    case NormalApproximation:
+      z := normalQuantile(1-alpha/2)
+      r = append(r, Interval{math.Max(0, k - z*math.Sqrt(k)), k + z*math.Sqrt(k)})
+    default:
+      return nil, fmt.Errorf("%w: interval method `%d'", ErrInvalidArgument, method)
+    }
+  }
+  return r, nil
+}
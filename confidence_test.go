@@ -0,0 +1,48 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestConfidenceIntervals1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 3}, []float64{0, 1, 10}, BinSum, BinLessSize)
+
+  r, err := binning.ConfidenceIntervals(0.95, Garwood)
+  if err != nil || len(r) != 3 {
+    t.Error("test failed"); return
+  }
+  // reference values of the exact Poisson interval
+  ref := []Interval{{0, 3.688879}, {0.025318, 5.571643}, {4.795389, 18.390356}}
+  for i := range ref {
+    if math.Abs(r[i].Lower - ref[i].Lower) > 1e-5 || math.Abs(r[i].Upper - ref[i].Upper) > 1e-5 {
+      t.Error("test failed")
+    }
+  }
+  r, _ = binning.ConfidenceIntervals(0.95, NormalApproximation)
+  if r[0].Lower != 0 || r[0].Upper != 0 || math.Abs(r[2].Upper - (10 + 1.959964*math.Sqrt(10))) > 1e-5 {
+    t.Error("test failed")
+  }
+  if _, err := binning.ConfidenceIntervals(1.5, Garwood); err == nil {
+    t.Error("test failed")
+  }
+}
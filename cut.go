@@ -0,0 +1,54 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "strconv"
+
+/* -------------------------------------------------------------------------- */
+
+// Interval labels of all bins, e.g. "[0, 1.5)". Bins are closed on the left
+// and open on the right.
+func (binning *Binning) Labels() []string {
+  r := []string{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    r = append(r, "[" + strconv.FormatFloat(at.Lower, 'g', -1, 64) + ", " + strconv.FormatFloat(at.Upper, 'g', -1, 64) + ")")
+  }
+  return r
+}
+
+// Label each observation with the label of the bin containing it, similar to
+// cut in R or pandas. If labels is nil, the interval labels given by Labels
+// are used, otherwise labels must contain one label per bin. Observations
+// outside the range of the binning receive an empty label.
+func (binning *Binning) Cut(data []float64, labels []string) ([]string, error) {
+  if labels == nil {
+    labels = binning.Labels()
+  }
+  if n := len(binning.Bins) - binning.deleted; len(labels) != n {
+    return nil, fmt.Errorf("%w: expected `%d' labels", ErrLengthMismatch, n)
+  }
+  r := make([]string, len(data))
+  for i, x := range data {
+    if j := binning.index(x); j >= 0 {
+      r[i] = labels[j]
+    }
+  }
+  return r, nil
+}
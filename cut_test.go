@@ -0,0 +1,44 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestCut1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1.5, 10, 100}, nil, BinSum, BinLessSize)
+
+  if l := binning.Labels(); len(l) != 3 || l[0] != "[0, 1.5)" || l[2] != "[10, 100)" {
+    t.Error("test failed")
+  }
+  r, err := binning.Cut([]float64{1.5, 0, 200, 99}, nil)
+  if err != nil || r[0] != "[1.5, 10)" || r[1] != "[0, 1.5)" || r[2] != "" || r[3] != "[10, 100)" {
+    t.Error("test failed")
+  }
+  r, err = binning.Cut([]float64{1.5, 0}, []string{"low", "mid", "high"})
+  if err != nil || r[0] != "mid" || r[1] != "low" {
+    t.Error("test failed")
+  }
+  if _, err := binning.Cut([]float64{1.5}, []string{"low"}); err == nil {
+    t.Error("test failed")
+  }
+}
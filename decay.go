@@ -0,0 +1,69 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Decay bin values exponentially with rate lambda, i.e. values are
+// multiplied by exp(-lambda dt) when the clock of the binning is advanced
+// by dt (see Tick and AddSampleAt). The clock starts at zero. Bins that
+// became small can be merged again with FilterBins or refined with Split.
+func WithDecay(lambda float64) Option {
+  return func(c *config) {
+    c.decay = lambda
+  }
+}
+
+// Current time of the clock of the binning
+func (binning *Binning) Time() float64 {
+  return binning.time
+}
+
+// Advance the clock of the binning to t and decay all values (see
+// WithDecay). Bins are repositioned in the sorted list, which requires
+// O(n log n) operations, and all pointers to bins become invalid.
+func (binning *Binning) Tick(t float64) error {
+  if t < binning.time || math.IsNaN(t) {
+    return fmt.Errorf("%w: time `%f' is before the current time `%f'", ErrInvalidArgument, t, binning.time)
+  }
+  f := math.Exp(-binning.config.decay*(t - binning.time))
+  binning.time = t
+  if f == 1 {
+    return nil
+  }
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    at.Y *= f
+    if err := binning.Modified(at); err != nil {
+      return err
+    }
+  }
+  return binning.Update()
+}
+
+// Advance the clock to t (see Tick) and add w to the value of the bin
+// containing x (see AddSample)
+func (binning *Binning) AddSampleAt(x, w, t float64) error {
+  if err := binning.Tick(t); err != nil {
+    return err
+  }
+  return binning.AddSample(x, w)
+}
@@ -0,0 +1,50 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestDecay1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 3}, []float64{4, 0, 0}, BinSum, BinLessY, WithDecay(math.Log(2)))
+
+  if err := binning.AddSampleAt(1.5, 4, 1); err != nil {
+    t.Error(err); return
+  }
+  if binning.Time() != 1 || binning.FindBin(0.5).Y != 2 || binning.FindBin(1.5).Y != 4 {
+    t.Error("test failed")
+  }
+  if err := binning.Tick(3); err != nil {
+    t.Error(err); return
+  }
+  if math.Abs(binning.FindBin(0.5).Y - 0.5) > 1e-12 || math.Abs(binning.FindBin(1.5).Y - 1) > 1e-12 {
+    t.Error("test failed")
+  }
+  // sorted list is updated
+  if binning.Smallest.Lower != 2 || binning.Largest.Lower != 1 {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+  if err := binning.Tick(2); err == nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,48 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Histogram density estimate at positions x, where bin values are counts of
+// samples. The density within bin i is p_i/w_i with p_i = Y_i/N, and its
+// standard error is sqrt(p_i (1-p_i)/N)/w_i. Positions outside the binning
+// have zero density.
+func (binning *Binning) Density(x []float64) ([]float64, []float64) {
+  n := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    n += at.Y
+  }
+  density := make([]float64, len(x))
+  stderr  := make([]float64, len(x))
+  if n <= 0 {
+    return density, stderr
+  }
+  for i := range x {
+    if bin := binning.FindBin(x[i]); bin != nil {
+      p := bin.Y/n
+      w := bin.Size()
+      density[i] = p/w
+      stderr [i] = math.Sqrt(p*(1-p)/n)/w
+    }
+  }
+  return density, stderr
+}
@@ -0,0 +1,37 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestDensity1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 3}, []float64{2, 6}, BinSum, BinLessSize)
+
+  d, s := binning.Density([]float64{-1, 0.5, 2, 3})
+  if d[0] != 0 || d[1] != 0.25 || d[2] != 0.375 || d[3] != 0 {
+    t.Error("test failed")
+  }
+  if math.Abs(s[1] - math.Sqrt(0.25*0.75/8)) > 1e-12 || math.Abs(s[2] - math.Sqrt(0.75*0.25/8)/2) > 1e-12 || s[3] != 0 {
+    t.Error("test failed")
+  }
+}
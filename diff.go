@@ -0,0 +1,154 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Type of a single edit of a patch
+type PatchOp int
+
+const (
+  // Insert boundary X
+  PatchInsert PatchOp = iota
+  // Remove boundary X
+  PatchRemove
+  // Set the value of the bin with lower boundary X to Y
+  PatchSetValue
+)
+
+type PatchEdit struct {
+  Op PatchOp `json:"op"`
+  X  float64 `json:"x"`
+  Y  float64 `json:"y,omitempty"`
+}
+
+// Patch describes the changes that transform one binning into another (see
+// Diff). Edits are ordered by boundary.
+type Patch struct {
+  Edits []PatchEdit `json:"edits"`
+}
+
+// Returns true if the patch contains no edits
+func (patch Patch) Empty() bool {
+  return len(patch.Edits) == 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the patch that transforms a into b. Boundaries of a missing in b
+// are removed, boundaries of b missing in a are inserted, and the values of
+// all bins of b that differ from a are set. Boundaries are compared
+// exactly.
+func Diff(a, b *Binning) Patch {
+  x := a.AppendBoundaries(nil)
+  y := b.AppendBoundaries(nil)
+  // values of a by lower boundary and upper boundary
+  type bin struct {
+    upper float64
+    y     float64
+  }
+  bins := make(map[float64]bin)
+  for at := a.First; at != nil; at = a.Next(at) {
+    bins[at.Lower] = bin{at.Upper, at.Y}
+  }
+  patch := Patch{}
+  i, j  := 0, 0
+  for i < len(x) || j < len(y) {
+    switch {
+    case j == len(y) || i < len(x) && x[i] < y[j]:
+      patch.Edits = append(patch.Edits, PatchEdit{Op: PatchRemove, X: x[i]}); i++
+    case i == len(x) || y[j] < x[i]:
+      patch.Edits = append(patch.Edits, PatchEdit{Op: PatchInsert, X: y[j]}); j++
+    default:
+      i++; j++
+    }
+  }
+  for at := b.First; at != nil; at = b.Next(at) {
+    if v, ok := bins[at.Lower]; !ok || v.upper != at.Upper || v.y != at.Y {
+      patch.Edits = append(patch.Edits, PatchEdit{Op: PatchSetValue, X: at.Lower, Y: at.Y})
+    }
+  }
+  sort.SliceStable(patch.Edits, func(i, j int) bool {
+    return patch.Edits[i].X < patch.Edits[j].X
+  })
+  return patch
+}
+
+// Apply a patch computed by Diff. Values of bins that are not changed by the
+// patch are retained. An error is returned and the binning is left unchanged
+// if the patch does not apply, e.g. if a removed boundary does not exist or
+// if a new bin has no value. Otherwise the binning is reset (see Reset) and
+// all pointers to bins become invalid.
+func (binning *Binning) Apply(patch Patch) error {
+  type bin struct {
+    upper float64
+    y     float64
+  }
+  bins     := make(map[float64]bin)
+  boundary := make(map[float64]bool)
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    bins[at.Lower] = bin{at.Upper, at.Y}
+    boundary[at.Lower] = true
+  }
+  if binning.Last != nil {
+    boundary[binning.Last.Upper] = true
+  }
+  values := make(map[float64]float64)
+  for _, edit := range patch.Edits {
+    switch edit.Op {
+    case PatchInsert:
+      if boundary[edit.X] {
+        return fmt.Errorf("%w: boundary `%f' already exists", ErrInvalidArgument, edit.X)
+      }
+      boundary[edit.X] = true
+    case PatchRemove:
+      if !boundary[edit.X] {
+        return fmt.Errorf("%w: boundary `%f' does not exist", ErrInvalidArgument, edit.X)
+      }
+      delete(boundary, edit.X)
+    case PatchSetValue:
+      values[edit.X] = edit.Y
+    default:
+      return fmt.Errorf("%w: invalid patch operation `%d'", ErrInvalidArgument, edit.Op)
+    }
+  }
+  x := make([]float64, 0, len(boundary))
+  for v := range boundary {
+    x = append(x, v)
+  }
+  sort.Float64s(x)
+  if len(x) < 2 {
+    return ErrTooFewBoundaries
+  }
+  y := make([]float64, len(x)-1)
+  for i := range y {
+    if v, ok := values[x[i]]; ok {
+      y[i] = v
+    } else
+    if v, ok := bins[x[i]]; ok && v.upper == x[i+1] {
+      y[i] = v.y
+    } else {
+      return fmt.Errorf("%w: no value for bin [%f, %f)", ErrInvalidArgument, x[i], x[i+1])
+    }
+  }
+  return binning.Reset(x, y)
+}
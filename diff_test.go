@@ -0,0 +1,54 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestDiff1(t *testing.T) {
+
+  a, _ := New([]float64{0, 1, 2, 4, 5}, []float64{1, 5, 2, 8}, BinSum, BinLessY)
+  b, _ := New([]float64{0, 1, 2, 4, 5}, []float64{1, 5, 2, 8}, BinSum, BinLessY)
+
+  if patch := Diff(a, b); !patch.Empty() {
+    t.Error("test failed")
+  }
+  b.FilterBins(3)
+  b.AddSample(4.5, 1)
+  b.Split(b.FindBin(2), 3)
+
+  patch := Diff(a, b)
+  // remove 1, insert 3, set values of [0, 2), [2, 3), [3, 4) and [4, 5)
+  if len(patch.Edits) != 6 {
+    t.Error("test failed")
+  }
+  if err := a.Apply(patch); err != nil {
+    t.Error(err); return
+  }
+  if a.String() != b.String() {
+    t.Error("test failed")
+  }
+  if err := a.Apply(patch); err == nil {
+    t.Error("test failed")
+  }
+  if a.String() != b.String() {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,141 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Discretizer maps values to bin indices. The boundaries are learned from
+// training data with Fit, where the representative value of each bin is the
+// mean of all training samples in that bin. Boundaries and centers are
+// exported, so that a fitted discretizer can be stored, e.g. as JSON, and
+// used without the strategy. If the strategy creates binnings with a
+// missing bin (see WithMissingBin), NaN values are mapped to the index Len().
+// If Capping is set, outliers are capped before fitting boundaries and the
+// caps are recorded, so that Transform caps values consistently.
+type Discretizer struct {
+  Strategy   Strategy  `json:"-"`
+  Capping    Capping   `json:"-"`
+  Boundaries []float64 `json:"boundaries"`
+  Centers    []float64 `json:"centers"`
+  Missing    bool      `json:"missing,omitempty"`
+  // lower and upper caps of values (see Capping)
+  Caps       []float64 `json:"caps,omitempty"`
+}
+
+func NewDiscretizer(strategy Strategy) *Discretizer {
+  return &Discretizer{Strategy: strategy}
+}
+
+// Number of bins, not including the missing bin
+func (obj *Discretizer) Len() int {
+  return len(obj.Centers)
+}
+
+// Learn bin boundaries from data
+func (obj *Discretizer) Fit(data []float64) error {
+  if obj.Strategy == nil {
+    return fmt.Errorf("%w: no strategy given", ErrInvalidArgument)
+  }
+  obj.Caps = nil
+  if !obj.Capping.disabled() {
+    lo, hi, err := obj.Capping.Limits(data)
+    if err != nil {
+      return err
+    }
+    obj.Caps = []float64{lo, hi}
+    data     = Winsorize(data, lo, hi)
+  }
+  binning, err := obj.Strategy(data)
+  if err != nil {
+    return err
+  }
+  obj.Boundaries = binning.AppendBoundaries(nil)
+  obj.Missing    = binning.Missing != nil
+  obj.Centers    = make([]float64, len(obj.Boundaries)-1)
+  counts        := make([]int, len(obj.Centers))
+  for _, x := range data {
+    if i := obj.index(x); i >= 0 {
+      obj.Centers[i] += x
+      counts[i]++
+    }
+  }
+  for i := range obj.Centers {
+    if counts[i] > 0 {
+      obj.Centers[i] /= float64(counts[i])
+    } else {
+      obj.Centers[i] = obj.Boundaries[i] + (obj.Boundaries[i+1]-obj.Boundaries[i])/2
+    }
+  }
+  return nil
+}
+
+// Index of the bin containing x or -1 if x is non-finite or out of range.
+// Values are capped if caps are given.
+func (obj *Discretizer) index(x float64) int {
+  if len(obj.Caps) == 2 {
+    x = clamp(x, obj.Caps[0], obj.Caps[1])
+  }
+  n := len(obj.Boundaries)-1
+  i := sort.Search(n, func(i int) bool { return obj.Boundaries[i+1] > x })
+  if i < n && obj.Boundaries[i] <= x {
+    return i
+  }
+  return -1
+}
+
+// Map values to bin indices. NaN values are mapped to the missing bin Len()
+// if present. All other non-finite values and values outside the range of
+// the training data are mapped to -1.
+func (obj *Discretizer) Transform(data []float64) []int {
+  r := make([]int, len(data))
+  for i, x := range data {
+    if obj.Missing && math.IsNaN(x) {
+      r[i] = obj.Len()
+    } else {
+      r[i] = obj.index(x)
+    }
+  }
+  return r
+}
+
+// Same as Fit followed by Transform
+func (obj *Discretizer) FitTransform(data []float64) ([]int, error) {
+  if err := obj.Fit(data); err != nil {
+    return nil, err
+  }
+  return obj.Transform(data), nil
+}
+
+// Map bin indices to the representative values of the bins. Invalid indices
+// and the missing bin are mapped to NaN.
+func (obj *Discretizer) InverseTransform(index []int) []float64 {
+  r := make([]float64, len(index))
+  for i, j := range index {
+    if j >= 0 && j < len(obj.Centers) {
+      r[i] = obj.Centers[j]
+    } else {
+      r[i] = math.NaN()
+    }
+  }
+  return r
+}
@@ -0,0 +1,54 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "encoding/json"
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestDiscretizer1(t *testing.T) {
+
+  data := []float64{1, 1, 2, 3, 10, 11, 12, 12}
+
+  d := NewDiscretizer(EqualWidthStrategy(2))
+  r, err := d.FitTransform(data)
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  if d.Len() != 2 || r[0] != 0 || r[3] != 0 || r[4] != 1 || r[7] != 1 {
+    t.Error("test failed")
+  }
+  if v := d.InverseTransform([]int{0, 1, -1}); v[0] != 7.0/4.0 || v[1] != 45.0/4.0 || !math.IsNaN(v[2]) {
+    t.Error("test failed")
+  }
+  if r := d.Transform([]float64{0, 5, 100, math.NaN()}); r[0] != -1 || r[1] != 0 || r[2] != -1 || r[3] != -1 {
+    t.Error("test failed")
+  }
+  // store and restore discretizer
+  b, _ := json.Marshal(d)
+  e    := Discretizer{}
+  if err := json.Unmarshal(b, &e); err != nil || e.Len() != 2 || e.Transform([]float64{11})[0] != 1 {
+    t.Error("test failed")
+  }
+  if err := e.Fit(data); err == nil {
+    t.Error("test failed")
+  }
+}
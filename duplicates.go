@@ -0,0 +1,82 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// Policy for handling duplicate boundaries, which result in bins of zero
+// width
+type DuplicatePolicy int
+
+const (
+  // Keep zero-width bins (default)
+  AllowDuplicates DuplicatePolicy = iota
+  // Return an error
+  RejectDuplicates
+  // Replace duplicate boundaries by a single boundary and merge the values
+  // of the corresponding bins using the sum function
+  CoalesceDuplicates
+)
+
+// Set the policy for handling duplicate boundaries
+func WithDuplicatePolicy(p DuplicatePolicy) Option {
+  return func(c *config) {
+    c.duplicates = p
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Detect and coalesce duplicate lower boundaries of sorted bins, upper is
+// the upper boundary of the last bin
+func (binning *Binning) coalesceDuplicates(upper float64) error {
+  bins := binning.Bins
+  n    := 0
+  for i := 0; i < len(bins); i++ {
+    if n > 0 && binning.equalBoundary(bins[n-1].Lower, bins[i].Lower) {
+      if binning.config.duplicates == RejectDuplicates {
+        return fmt.Errorf("%w: duplicate boundary `%f'", ErrZeroWidthBin, bins[i].Lower)
+      }
+      binning.absorb(&bins[n-1], &bins[i], true)
+      bins[n-1].merged += bins[i].merged + 1
+    } else {
+      bins[n] = bins[i]; n++
+    }
+  }
+  // the last bin has zero width if its lower boundary
+  // equals the upper boundary
+  if n > 0 && binning.equalBoundary(bins[n-1].Lower, upper) {
+    if binning.config.duplicates == RejectDuplicates {
+      return fmt.Errorf("%w: duplicate boundary `%f'", ErrZeroWidthBin, upper)
+    }
+    if n > 1 {
+      binning.absorb(&bins[n-2], &bins[n-1], true)
+      bins[n-2].merged += bins[n-1].merged + 1
+    }
+    n--
+  }
+  if n < 2 {
+    return ErrTooFewBoundaries
+  }
+  binning.Bins  = bins[0:n]
+  binning.order = binning.order[0:n]
+  return nil
+}
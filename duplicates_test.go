@@ -0,0 +1,42 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestDuplicates1(t *testing.T) {
+
+  x := []float64{0, 1, 1, 2, 3, 1, 3}
+  y := []float64{1, 2, 3, 4, 5, 6}
+
+  if _, err := New(x, y, BinSum, BinLessSize, WithDuplicatePolicy(RejectDuplicates)); err == nil {
+    t.Error("test failed")
+  }
+  binning, err := New(x, y, BinSum, BinLessSize, WithDuplicatePolicy(CoalesceDuplicates))
+  if err != nil {
+    t.Error(err); return
+  }
+  if s := binning.String(); s != "[0.000000, 1.000000):1 [1.000000, 2.000000):11 [2.000000, 3.000000):9" {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+}
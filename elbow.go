@@ -0,0 +1,92 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Costs of all merges performed by FilterBins until a single bin remains,
+// where the cost of a merge is the cost of the deleted (smallest) bin, or
+// its value if cost is nil. The i-th cost belongs to the merge that reduces
+// the number of bins from n-i to n-i-1. The binning is not modified.
+func (binning *Binning) MergeCosts(cost func(Bin) float64) ([]float64, error) {
+  if cost == nil {
+    cost = func(bin Bin) float64 { return bin.Y }
+  }
+  tmp, err := binning.clone()
+  if err != nil {
+    return nil, err
+  }
+  r := []float64{}
+  for tmp.First != tmp.Last {
+    r = append(r, cost(*tmp.Smallest))
+    if _, err := tmp.Delete(tmp.Smallest); err != nil {
+      return nil, err
+    }
+  }
+  return r, nil
+}
+
+// Index of the elbow of a sequence of merge costs, i.e. the point with the
+// largest distance to the line between the first and last point after
+// scaling both axes to [0, 1]. Costs are replaced by their running maximum,
+// so that small fluctuations are ignored. Returns -1 if there are less than
+// three costs.
+func Elbow(costs []float64) int {
+  n := len(costs)
+  if n < 3 {
+    return -1
+  }
+  y := make([]float64, n)
+  for i := range costs {
+    y[i] = costs[i]
+    if i > 0 {
+      y[i] = math.Max(y[i], y[i-1])
+    }
+  }
+  lo, hi := y[0], y[n-1]
+  if !(hi > lo) {
+    return -1
+  }
+  k, d := -1, 0.0
+  for i := 1; i+1 < n; i++ {
+    // distance to the diagonal of the unit square
+    if v := math.Abs(float64(i)/float64(n-1) - (y[i] - lo)/(hi - lo)); v > d {
+      k, d = i, v
+    }
+  }
+  return k
+}
+
+// Suggest a number of bins for FilterBins from the elbow of the merge costs
+// (see MergeCosts and Elbow), i.e. the number of bins after the merge at
+// the elbow, which is the last merge before costs increase rapidly
+func (binning *Binning) ElbowBins(cost func(Bin) float64) (int, error) {
+  costs, err := binning.MergeCosts(cost)
+  if err != nil {
+    return 0, err
+  }
+  k := Elbow(costs)
+  if k < 0 {
+    return 0, fmt.Errorf("%w: merge costs have no elbow", ErrInvalidArgument)
+  }
+  return len(costs) - k, nil
+}
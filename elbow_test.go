@@ -0,0 +1,53 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestElbow1(t *testing.T) {
+
+  if k := Elbow([]float64{1, 1, 1, 1, 1, 1, 10, 20, 30}); k != 5 {
+    t.Error("test failed")
+  }
+  if k := Elbow([]float64{1, 2}); k != -1 {
+    t.Error("test failed")
+  }
+}
+
+func TestElbow2(t *testing.T) {
+
+  // three groups of narrow bins separated by wide bins
+  x := []float64{0, 0.1, 0.2, 0.3, 5, 5.1, 5.2, 5.3, 10, 10.1, 10.2, 10.3}
+
+  binning, _ := New(x, nil, BinSum, BinLessSize)
+
+  costs, err := binning.MergeCosts(func(bin Bin) float64 { return bin.Size() })
+  if err != nil {
+    t.Error(err); return
+  }
+  if len(costs) != 10 || len(binning.Bins) != 11 {
+    t.Error("test failed")
+  }
+  // all merges but the last one delete narrow bins
+  if n, err := binning.ElbowBins(func(bin Bin) float64 { return bin.Size() }); err != nil || n != 2 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,59 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Treat boundaries and positions that differ by at most eps as equal. The
+// tolerance applies to the detection of duplicate boundaries (see
+// WithDuplicatePolicy), to FindBin and to EqualBoundaries. A position x that
+// is within eps of a boundary is assigned to the bin starting at this
+// boundary, positions below the upper boundary of the last bin remain in
+// the last bin. By default eps is zero.
+func WithEpsilon(eps float64) Option {
+  return func(c *config) {
+    c.epsilon = math.Abs(eps)
+  }
+}
+
+// Returns true if both boundaries are equal within the tolerance
+func (binning *Binning) equalBoundary(a, b float64) bool {
+  return math.Abs(a-b) <= binning.config.epsilon
+}
+
+// Returns true if both binnings have the same number of bins and all
+// boundaries are equal within the tolerance of this binning
+func (binning *Binning) EqualBoundaries(other *Binning) bool {
+  a := binning.First
+  b := other  .First
+  for ; a != nil && b != nil; a, b = binning.Next(a), other.Next(b) {
+    if !binning.equalBoundary(a.Lower, b.Lower) {
+      return false
+    }
+  }
+  if a != nil || b != nil {
+    return false
+  }
+  if binning.Last == nil || other.Last == nil {
+    return binning.Last == other.Last
+  }
+  return binning.equalBoundary(binning.Last.Upper, other.Last.Upper)
+}
@@ -0,0 +1,90 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestEpsilon1(t *testing.T) {
+
+  x := []float64{0, 0.3+1e-15, 0.3, 1, 2}
+  y := []float64{1, 2, 3, 4}
+
+  binning, err := New(x, y, BinSum, BinLessSize, WithDuplicatePolicy(RejectDuplicates))
+  if err != nil {
+    t.Error("test failed")
+  }
+  if _, err := New(x, y, BinSum, BinLessSize, WithDuplicatePolicy(RejectDuplicates), WithEpsilon(1e-12)); err == nil {
+    t.Error("test failed")
+  }
+  binning, _ = New(x, y, BinSum, BinLessSize, WithDuplicatePolicy(CoalesceDuplicates), WithEpsilon(1e-12))
+  if len(binning.Bins) != 3 || binning.Bins[1].Y != 5 {
+    t.Error("test failed")
+  }
+  if bin := binning.FindBin(1-1e-13); bin == nil || bin.Lower != 1 {
+    t.Error("test failed")
+  }
+  if f := binning.Frozen(); f.FindBin(1-1e-13) != 2 {
+    t.Error("test failed")
+  }
+}
+
+func TestEpsilon2(t *testing.T) {
+
+  binning1, _ := New([]float64{0, 0.3, 1}, nil, BinSum, BinLessSize, WithEpsilon(1e-12))
+  binning2, _ := New([]float64{0, 0.3+1e-15, 1}, nil, BinSum, BinLessSize)
+  binning3, _ := New([]float64{0, 0.3, 1, 2}, nil, BinSum, BinLessSize)
+
+  if !binning1.EqualBoundaries(binning2) {
+    t.Error("test failed")
+  }
+  if binning2.EqualBoundaries(binning1) {
+    t.Error("test failed")
+  }
+  if binning1.EqualBoundaries(binning3) {
+    t.Error("test failed")
+  }
+}
+
+func TestEpsilon3(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2}, nil, BinSum, BinLessSize, WithEpsilon(1e-12))
+
+  // positions are not moved beyond the upper boundary
+  if bin := binning.FindBin(2-1e-13); bin == nil || bin.Lower != 1 {
+    t.Error("test failed")
+  }
+  if bin := binning.FindBin(2); bin != nil {
+    t.Error("test failed")
+  }
+  if f := binning.Frozen(); f.FindBin(2-1e-13) != 1 || f.FindBin(1-1e-13) != 1 {
+    t.Error("test failed")
+  }
+  if p := binning.Persistent(); p.FindBin(2-1e-13) != 1 || p.FindBin(1-1e-13) != 1 {
+    t.Error("test failed")
+  }
+  // largest sample is within the range of the binning
+  samples := []float64{1, 2, 3}
+  binning, _ = FromSamples(samples, BinLessSize, WithEpsilon(1e-12))
+  if bin := binning.FindBin(3); bin == nil || bin.Lower != 3 {
+    t.Error("test failed")
+  }
+}
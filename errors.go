@@ -0,0 +1,65 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "errors"
+
+/* -------------------------------------------------------------------------- */
+
+// Returned if less than two bins are given or remain after removing
+// invalid boundaries
+var ErrTooFewBoundaries = errors.New("too few boundaries")
+
+// Returned if the number of values does not match the number of bins
+var ErrLengthMismatch = errors.New("invalid length of value vector")
+
+// Returned if boundaries are required to be sorted but are not
+var ErrUnsorted = errors.New("boundaries are not sorted")
+
+// Returned if duplicate boundaries are rejected
+var ErrZeroWidthBin = errors.New("zero-width bin")
+
+// Returned if NaN or infinite values are rejected
+var ErrNonFinite = errors.New("NaN or infinite value")
+
+// Returned if a value is outside the range of a binning
+var ErrOutOfRange = errors.New("value out of range")
+
+// Returned if a bin does not belong to the binning, e.g. if it belongs to
+// another binning or if it was invalidated by Compact or Update
+var ErrForeignBin = errors.New("bin does not belong to binning")
+
+// Returned if a bin was already deleted
+var ErrBinDeleted = errors.New("bin is deleted")
+
+// Returned if an argument has an invalid value
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// Returned if input could not be parsed
+var ErrInvalidFormat = errors.New("invalid format")
+
+// Returned if a transaction was already committed or rolled back, or if an
+// enclosing transaction is finished before a nested one
+var ErrTxDone = errors.New("transaction is not active")
+
+// Returned by Validate if an invariant of the binning is violated
+var ErrCorrupted = errors.New("binning is corrupted")
+
+// Returned if no binning satisfies all constraints
+var ErrInfeasible = errors.New("constraints are infeasible")
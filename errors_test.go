@@ -0,0 +1,49 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+import   "strings"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestErrors1(t *testing.T) {
+
+  if _, err := New([]float64{1, 2}, nil, BinSum, BinLessSize); !errors.Is(err, ErrTooFewBoundaries) {
+    t.Error("test failed")
+  }
+  if _, err := New([]float64{1, 2, 3, 4}, []float64{1, 2}, BinSum, BinLessSize); !errors.Is(err, ErrLengthMismatch) {
+    t.Error("test failed")
+  }
+  if _, err := New([]float64{1, 2, 2, 4}, nil, BinSum, BinLessSize, WithDuplicatePolicy(RejectDuplicates)); !errors.Is(err, ErrZeroWidthBin) {
+    t.Error("test failed")
+  }
+  if _, err := ReadStream(strings.NewReader("1 1\n0 1\n2\n"), 2, BinSum, BinLessSize); !errors.Is(err, ErrUnsorted) {
+    t.Error("test failed")
+  }
+  if _, err := ReadStream(strings.NewReader("1 a\n"), 2, BinSum, BinLessSize); !errors.Is(err, ErrInvalidFormat) {
+    t.Error("test failed")
+  }
+  binning, _ := New([]float64{1, 2, 3, 4}, nil, BinSum, BinLessSize)
+  if err := binning.AddSample(5, 1); !errors.Is(err, ErrOutOfRange) {
+    t.Error("test failed")
+  }
+}
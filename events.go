@@ -0,0 +1,111 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+/* -------------------------------------------------------------------------- */
+
+// Kind of a change of a binning
+type EventKind int
+
+const (
+  // The binning was initialized with new boundaries and values (see Reset)
+  EventReset EventKind = iota
+  // A bin was merged with one of its neighbors
+  EventMerge
+  // The value of a bin was modified (see AddSample and Modified)
+  EventUpdate
+  // A filter method finished merging bins
+  EventFilter
+  // A bin was split into two bins (see Split)
+  EventSplit
+  // A merge was reversed (see Undo)
+  EventUndo
+)
+
+func (kind EventKind) String() string {
+  switch kind {
+  case EventReset:
+    return "reset"
+  case EventMerge:
+    return "merge"
+  case EventUpdate:
+    return "update"
+  case EventFilter:
+    return "filter"
+  case EventSplit:
+    return "split"
+  case EventUndo:
+    return "undo"
+  default:
+    return "unknown"
+  }
+}
+
+// Event describes a change of a binning. Bins are copies, which remain valid
+// after the binning is modified.
+type Event struct {
+  Kind    EventKind
+  // resulting bin of a merge, the modified bin of an update, the left
+  // bin of a split or the restored bin of an undo
+  Bin     Bin
+  // bin that was deleted by a merge
+  Deleted Bin
+  // right bin that was created by a split or the deleted bin that was
+  // recreated by an undo
+  Created Bin
+  // number of bins after a reset or filter
+  Bins    int
+}
+
+type subscriber struct {
+  id int
+  f  func(Event)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Call f for every change of the binning. Events are delivered synchronously
+// in the order of subscription, hence f must not modify the binning. The
+// returned function cancels the subscription.
+func (binning *Binning) Subscribe(f func(Event)) func() {
+  binning.subscriberId++
+  id := binning.subscriberId
+  binning.subscribers = append(binning.subscribers, subscriber{id, f})
+  return func() {
+    for i, s := range binning.subscribers {
+      if s.id == id {
+        binning.subscribers = append(binning.subscribers[0:i:i], binning.subscribers[i+1:]...)
+        break
+      }
+    }
+  }
+}
+
+func (binning *Binning) emit(event Event) {
+  if binning.tx != nil {
+    // deliver events when the transaction is committed
+    if len(binning.subscribers) > 0 {
+      binning.tx.events = append(binning.tx.events, event)
+    }
+    return
+  }
+  for _, s := range binning.subscribers {
+    s.f(event)
+  }
+}
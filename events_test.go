@@ -0,0 +1,56 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestEvents1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 3, 4}, []float64{1, 5, 2, 8}, BinSum, BinLessY)
+
+  events := []Event{}
+  cancel := binning.Subscribe(func(event Event) {
+    events = append(events, event)
+  })
+  if err := binning.AddSample(2.5, 1); err != nil {
+    t.Error("test failed"); return
+  }
+  if err := binning.FilterBins(3); err != nil {
+    t.Error("test failed"); return
+  }
+  if len(events) != 3 {
+    t.Error("test failed"); return
+  }
+  if events[0].Kind != EventUpdate || events[0].Bin.Y != 3 {
+    t.Error("test failed")
+  }
+  if events[1].Kind != EventMerge || events[1].Deleted.Y != 1 || events[1].Bin.Y != 6 || events[1].Bin.Lower != 0 || events[1].Bin.Upper != 2 {
+    t.Error("test failed")
+  }
+  if events[2].Kind != EventFilter || events[2].Bins != 3 {
+    t.Error("test failed")
+  }
+  cancel()
+  binning.FilterBinsHeap(2)
+  if len(events) != 3 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,146 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "bytes"
+import "fmt"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Frozen is an immutable snapshot of a binning stored as flat arrays. It is
+// safe for concurrent use without locking, while the original binning is
+// modified.
+type Frozen struct {
+  // boundaries of all n bins (length n+1)
+  boundaries []float64
+  // values of all bins (length n)
+  values     []float64
+  // prefix sums of values (length n+1)
+  prefix     []float64
+  // tolerance for boundary comparisons
+  epsilon    float64
+}
+
+func newFrozen(boundaries, values []float64, epsilon float64) *Frozen {
+  prefix := make([]float64, len(values)+1)
+  for i, y := range values {
+    prefix[i+1] = prefix[i] + y
+  }
+  return &Frozen{
+    boundaries: boundaries,
+    values    : values,
+    prefix    : prefix,
+    epsilon   : epsilon }
+}
+
+// Create an immutable snapshot of the binning
+func (binning *Binning) Frozen() *Frozen {
+  return newFrozen(binning.AppendBoundaries(nil), binning.AppendValues(nil), binning.config.epsilon)
+}
+
+// Create an immutable snapshot of the binning under a read lock
+func (obj *SyncBinning) Frozen() *Frozen {
+  obj.mutex.RLock()
+  defer obj.mutex.RUnlock()
+  return obj.binning.Frozen()
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Number of bins
+func (obj *Frozen) Len() int {
+  return len(obj.values)
+}
+
+// Returns the i-th bin
+func (obj *Frozen) Bin(i int) Bin {
+  return Bin{Y: obj.values[i], Lower: obj.boundaries[i], Upper: obj.boundaries[i+1]}
+}
+
+// Returns a copy of all boundaries
+func (obj *Frozen) Boundaries() []float64 {
+  return append([]float64{}, obj.boundaries...)
+}
+
+// Returns a copy of all values
+func (obj *Frozen) Values() []float64 {
+  return append([]float64{}, obj.values...)
+}
+
+// Returns the index of the bin containing x or -1 if x is out of range
+func (obj *Frozen) FindBin(x float64) int {
+  n := len(obj.values)
+  if n > 0 && x + obj.epsilon < obj.boundaries[n] {
+    x += obj.epsilon
+  }
+  i := sort.Search(n, func(i int) bool { return obj.boundaries[i+1] > x })
+  if i < n && obj.boundaries[i] <= x {
+    return i
+  }
+  return -1
+}
+
+// Sum of all bin values
+func (obj *Frozen) Total() float64 {
+  return obj.prefix[len(obj.values)]
+}
+
+// Sum of bin values below x, where values are assumed to be uniformly
+// distributed within bins
+func (obj *Frozen) mass(x float64) float64 {
+  n := len(obj.values)
+  i := sort.Search(n, func(i int) bool { return obj.boundaries[i+1] > x })
+  if i == n {
+    return obj.prefix[n]
+  }
+  if x <= obj.boundaries[i] {
+    return obj.prefix[i]
+  }
+  return obj.prefix[i] + obj.values[i]*(x-obj.boundaries[i])/(obj.boundaries[i+1]-obj.boundaries[i])
+}
+
+// Sum of bin values in the interval [lo, hi), see Binning.Aggregate. Sums
+// are computed from prefix sums with O(log n) operations.
+func (obj *Frozen) Aggregate(lo, hi float64) float64 {
+  if lo >= hi {
+    return 0.0
+  }
+  return obj.mass(hi) - obj.mass(lo)
+}
+
+// Fraction of the total value below x, i.e. the cumulative distribution
+// function if values are counts
+func (obj *Frozen) CDF(x float64) float64 {
+  if t := obj.Total(); t != 0 {
+    return obj.mass(x)/t
+  }
+  return 0.0
+}
+
+func (obj *Frozen) String() string {
+  var buffer bytes.Buffer
+  for i := 0; i < obj.Len(); i++ {
+    if i != 0 {
+      fmt.Fprintf(&buffer, " ")
+    }
+    fmt.Fprintf(&buffer, "%v", obj.Bin(i))
+  }
+  return buffer.String()
+}
@@ -0,0 +1,73 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestFrozen1(t *testing.T) {
+
+  x := []float64{-100,-99,1,2,3,6,8,19,21,120,300,350,355,380}
+  y := []float64{1,2,3,4,5,6,7,8,9,10,11,12,13}
+
+  binning, _ := New(x, y, BinSum, BinLessY)
+  binning.Delete(&binning.Bins[3])
+
+  frozen := binning.Frozen()
+  binning.FilterBins(3)
+
+  if frozen.Len() != 12 {
+    t.Error("test failed")
+  }
+  if i := frozen.FindBin(2.5); i != 2 || frozen.Bin(i).Lower != 1 || frozen.Bin(i).Y != 7 {
+    t.Error("test failed")
+  }
+  if i := frozen.FindBin(380); i != -1 {
+    t.Error("test failed")
+  }
+  if r := frozen.Aggregate(0, 4.5); r != 2.0/100.0 + 7 + 5.0/2.0 {
+    t.Error("test failed")
+  }
+  if frozen.String() == binning.String() {
+    t.Error("test failed")
+  }
+}
+
+func TestFrozen2(t *testing.T) {
+
+  x := []float64{0, 1, 2, 4, 8}
+  y := []float64{1, 2, 3, 2}
+
+  binning, _ := New(x, y, BinSum, BinLessY)
+  frozen := binning.Frozen()
+
+  if frozen.Total() != 8 {
+    t.Error("test failed")
+  }
+  for _, r := range [][2]float64{{-1, 9}, {0.5, 3}, {1, 2}, {3, 3}, {5, 20}, {-5, -1}} {
+    if a, b := frozen.Aggregate(r[0], r[1]), binning.Aggregate(r[0], r[1]); a != b {
+      t.Error("test failed")
+    }
+  }
+  if frozen.CDF(-1) != 0 || frozen.CDF(2) != 3.0/8.0 || frozen.CDF(8) != 1 {
+    t.Error("test failed")
+  }
+}
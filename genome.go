@@ -0,0 +1,175 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "container/heap"
+import "context"
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// GenomeBinning holds one binning per chromosome (or sequence) with a shared
+// configuration. Bins are merged genome-wide, but never across chromosome
+// boundaries.
+type GenomeBinning struct {
+  Sum      func(Bin, Bin) float64
+  Less     func(Bin, Bin) bool
+  options  []Option
+  names    []string
+  binnings []*Binning
+  index    map[string]int
+}
+
+// Create an empty genome binning, all arguments are passed to New when
+// chromosomes are added
+func NewGenomeBinning(sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) *GenomeBinning {
+  return &GenomeBinning{Sum: sum, Less: less, options: options, index: make(map[string]int)}
+}
+
+// Add a chromosome with bin boundaries x and values y, see New
+func (obj *GenomeBinning) Add(name string, x, y []float64) error {
+  if _, ok := obj.index[name]; ok {
+    return fmt.Errorf("%w: duplicate chromosome `%s'", ErrInvalidArgument, name)
+  }
+  binning, err := New(x, y, obj.Sum, obj.Less, obj.options...)
+  if err != nil {
+    return fmt.Errorf("chromosome `%s': %w", name, err)
+  }
+  obj.index[name] = len(obj.names)
+  obj.names    = append(obj.names, name)
+  obj.binnings = append(obj.binnings, binning)
+  return nil
+}
+
+// Names of all chromosomes in the order they were added
+func (obj *GenomeBinning) Chromosomes() []string {
+  return append([]string{}, obj.names...)
+}
+
+// Returns the binning of a chromosome or nil if it does not exist
+func (obj *GenomeBinning) Binning(name string) *Binning {
+  if i, ok := obj.index[name]; ok {
+    return obj.binnings[i]
+  }
+  return nil
+}
+
+// Total number of bins of all chromosomes
+func (obj *GenomeBinning) Len() int {
+  n := 0
+  for _, binning := range obj.binnings {
+    n += len(binning.Bins) - binning.deleted
+  }
+  return n
+}
+
+// Find the bin of a chromosome containing position x, see Binning.FindBin
+func (obj *GenomeBinning) FindBin(name string, x float64) *Bin {
+  if binning := obj.Binning(name); binning != nil {
+    return binning.FindBin(x)
+  }
+  return nil
+}
+
+// Sum of bin values of a chromosome in the interval [lo, hi), see
+// Binning.Aggregate
+func (obj *GenomeBinning) Aggregate(name string, lo, hi float64) float64 {
+  if binning := obj.Binning(name); binning != nil {
+    return binning.Aggregate(lo, hi)
+  }
+  return 0.0
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Heap of chromosomes ordered by their smallest bins
+type genomeHeap struct {
+  genome *GenomeBinning
+  items  []int
+}
+
+func (h genomeHeap) Len() int {
+  return len(h.items)
+}
+
+func (h genomeHeap) Less(i, j int) bool {
+  a := h.genome.binnings[h.items[i]].Smallest
+  b := h.genome.binnings[h.items[j]].Smallest
+  if h.genome.Less(*a, *b) {
+    return true
+  }
+  if h.genome.Less(*b, *a) {
+    return false
+  }
+  return h.items[i] < h.items[j]
+}
+
+func (h genomeHeap) Swap(i, j int) {
+  h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *genomeHeap) Push(x interface{}) {
+  h.items = append(h.items, x.(int))
+}
+
+func (h *genomeHeap) Pop() interface{} {
+  n := len(h.items)
+  r := h.items[n-1]
+  h.items = h.items[0:n-1]
+  return r
+}
+
+// Merge bins until at most n bins remain in total. In each step the
+// smallest bin of all chromosomes is merged, where each chromosome keeps at
+// least one bin.
+func (obj *GenomeBinning) FilterBins(n int) error {
+  return obj.FilterBinsContext(context.Background(), n)
+}
+
+// Same as FilterBins, but stops merging bins when the context is canceled
+// (see Binning.FilterBinsContext)
+func (obj *GenomeBinning) FilterBinsContext(ctx context.Context, n int) error {
+  h := genomeHeap{genome: obj}
+  for i, binning := range obj.binnings {
+    if len(binning.Bins) - binning.deleted > 1 {
+      h.items = append(h.items, i)
+    }
+  }
+  heap.Init(&h)
+  m := obj.Len()
+  for i := 0; m > n && h.Len() > 0; i++ {
+    if i % checkInterval == 0 && ctx.Err() != nil {
+      break
+    }
+    binning := obj.binnings[h.items[0]]
+    if _, err := binning.Delete(binning.Smallest); err != nil {
+      return err
+    }
+    if len(binning.Bins) - binning.deleted > 1 {
+      heap.Fix(&h, 0)
+    } else {
+      heap.Pop(&h)
+    }
+    m--
+  }
+  for _, binning := range obj.binnings {
+    binning.Compact()
+  }
+  return ctx.Err()
+}
@@ -0,0 +1,65 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestGenome1(t *testing.T) {
+
+  genome := NewGenomeBinning(BinSum, BinLessSize)
+  if err := genome.Add("chr1", []float64{0, 100, 200, 210, 400}, []float64{1, 2, 3, 4}); err != nil {
+    t.Error("test failed")
+  }
+  if err := genome.Add("chr2", []float64{0, 5, 300}, []float64{5, 6}); err != nil {
+    t.Error("test failed")
+  }
+  if err := genome.Add("chr3", []float64{0, 40, 50}, []float64{7, 8}); err != nil {
+    t.Error("test failed")
+  }
+  if err := genome.Add("chr1", []float64{0, 1}, nil); err == nil {
+    t.Error("test failed")
+  }
+  if genome.Len() != 8 {
+    t.Error("test failed")
+  }
+  if err := genome.FilterBins(4); err != nil {
+    t.Error("test failed")
+  }
+  // merges [0,5) on chr2, [200,210) on chr1, [40,50) on chr3 and the
+  // smallest remaining bin, which is [0,100) on chr1
+  if genome.Len() != 4 || len(genome.Binning("chr2").Bins) != 1 || len(genome.Binning("chr3").Bins) != 1 {
+    t.Error("test failed")
+  }
+  if bin := genome.FindBin("chr1", 150); bin == nil || bin.Lower != 0 || bin.Upper != 210 {
+    t.Error("test failed")
+  }
+  if genome.FindBin("chrX", 150) != nil {
+    t.Error("test failed")
+  }
+  if genome.Aggregate("chr2", 0, 300) != 11 {
+    t.Error("test failed")
+  }
+  genome.FilterBins(1)
+  if genome.Len() != 3 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,174 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Aggregation of bin values onto a regular grid
+type AggMode int
+
+const (
+  // Sum of bin values proportionally to the overlap with the grid cell,
+  // i.e. values are assumed to be uniformly distributed within bins (see
+  // Aggregate)
+  AggSum AggMode = iota
+  // Mean of bin values weighted by their overlap with the grid cell
+  AggMean
+  // Largest value of all bins that overlap with the grid cell
+  AggMax
+)
+
+func (m AggMode) String() string {
+  switch m {
+  case AggSum:  return "sum"
+  case AggMean: return "mean"
+  case AggMax:  return "max"
+  }
+  return fmt.Sprintf("AggMode(%d)", int(m))
+}
+
+// Aggregate bins onto a regular grid with cells of width step, where cell
+// boundaries are multiples of step that cover the range of the binning.
+// The new binning uses the sum and less functions and all options of this
+// binning.
+func (binning *Binning) ToRegularGrid(step float64, agg AggMode) (*Binning, error) {
+  if !(step > 0) || math.IsInf(step, 1) {
+    return nil, fmt.Errorf("%w: grid step `%f'", ErrInvalidArgument, step)
+  }
+  if agg != AggSum && agg != AggMean && agg != AggMax {
+    return nil, fmt.Errorf("%w: invalid aggregation `%v'", ErrInvalidArgument, agg)
+  }
+  if binning.config.circular {
+    return nil, fmt.Errorf("%w: regular grids of circular binnings are not supported", ErrInvalidArgument)
+  }
+  if binning.First == nil {
+    return nil, ErrTooFewBoundaries
+  }
+  lo := math.Floor(binning.First.Lower/step)
+  hi := math.Ceil (binning.Last .Upper/step)
+  if hi - lo < 1 || hi - lo > math.MaxInt32 {
+    return nil, fmt.Errorf("%w: grid step `%f' gives too many cells", ErrInvalidArgument, step)
+  }
+  n := int(hi - lo)
+  x := make([]float64, n+1)
+  for i := range x {
+    x[i] = (lo + float64(i))*step
+  }
+  y := make([]float64, n)
+  w := make([]float64, n)
+  if agg == AggMax {
+    for i := range y {
+      y[i] = math.Inf(-1)
+    }
+  }
+  // sweep over bins and cells
+  i  := 0
+  at := binning.First
+  for at != nil && i < n {
+    if a, b := math.Max(x[i], at.Lower), math.Min(x[i+1], at.Upper); b > a {
+      v := binning.Value(at)
+      switch agg {
+      case AggSum:  y[i] += v*(b-a)/at.Size()
+      case AggMean: y[i] += v*(b-a); w[i] += b-a
+      case AggMax:  y[i]  = math.Max(y[i], v)
+      }
+    }
+    if at.Upper <= x[i+1] {
+      at = binning.Next(at)
+    } else {
+      i++
+    }
+  }
+  if agg == AggMean {
+    for i := range y {
+      y[i] /= w[i]
+    }
+  }
+  return New(x, y, binning.Sum, binning.lessFunc, binning.options...)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Split each bin into factor bins of equal width (see RefineTo)
+func (binning *Binning) Refine(factor int, model func(lo, hi float64) float64) (*Binning, error) {
+  if factor < 1 {
+    return nil, fmt.Errorf("%w: refinement factor must be positive", ErrInvalidArgument)
+  }
+  edges := []float64{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    for i := 1; i < factor; i++ {
+      edges = append(edges, at.Lower + float64(i)*at.Size()/float64(factor))
+    }
+  }
+  return binning.RefineTo(edges, model)
+}
+
+// Split bins at all edges within the range of the binning. The value of each
+// bin is distributed among its parts proportionally to the mass model(lo,
+// hi) of each part [lo, hi), or proportionally to their widths if model is
+// nil or has no mass within the bin. The new binning uses the sum and less
+// functions and all options of this binning.
+func (binning *Binning) RefineTo(edges []float64, model func(lo, hi float64) float64) (*Binning, error) {
+  if binning.config.circular {
+    return nil, fmt.Errorf("%w: refinement of circular binnings is not supported", ErrInvalidArgument)
+  }
+  if model == nil {
+    model = func(lo, hi float64) float64 { return hi - lo }
+  }
+  e := append([]float64{}, edges...)
+  sort.Float64s(e)
+  x := []float64{}
+  y := []float64{}
+  j := 0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    // parts of the bin
+    p := []float64{at.Lower}
+    for ; j < len(e) && e[j] < at.Upper; j++ {
+      if e[j] > p[len(p)-1] {
+        p = append(p, e[j])
+      }
+    }
+    p = append(p, at.Upper)
+    m := make([]float64, len(p)-1)
+    s := 0.0
+    for i := range m {
+      m[i] = model(p[i], p[i+1])
+      s   += m[i]
+    }
+    if !(s > 0) || math.IsInf(s, 1) {
+      for i := range m {
+        m[i] = p[i+1] - p[i]
+      }
+      s = at.Size()
+    }
+    v := binning.Value(at)
+    for i := range m {
+      x = append(x, p[i])
+      y = append(y, v*m[i]/s)
+    }
+  }
+  if binning.Last != nil {
+    x = append(x, binning.Last.Upper)
+  }
+  return New(x, y, binning.Sum, binning.lessFunc, binning.options...)
+}
@@ -0,0 +1,110 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestGrid1(t *testing.T) {
+
+  binning, _ := New([]float64{0.5, 1, 3, 4.5}, []float64{1, 4, 3}, BinSum, BinLessY)
+
+  r, err := binning.ToRegularGrid(1, AggSum)
+  if err != nil {
+    t.Error(err); return
+  }
+  if x := r.AppendBoundaries(nil); len(x) != 6 || x[0] != 0 || x[5] != 5 {
+    t.Error("test failed")
+  }
+  if v := r.AppendValues(nil); v[0] != 1 || v[1] != 2 || v[2] != 2 || v[3] != 2 || v[4] != 1 {
+    t.Error("test failed")
+  }
+  r, _ = binning.ToRegularGrid(2, AggMean)
+  // cell [0, 2): 1 over width 0.5 and 4 over width 1
+  if v := r.AppendValues(nil); math.Abs(v[0] - 4.5/1.5) > 1e-12 || v[1] != 3.5 || v[2] != 3 {
+    t.Error("test failed")
+  }
+  r, _ = binning.ToRegularGrid(2, AggMax)
+  if v := r.AppendValues(nil); v[0] != 4 || v[1] != 4 || v[2] != 3 {
+    t.Error("test failed")
+  }
+  if _, err := binning.ToRegularGrid(0, AggSum); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestGrid2(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 3}, []float64{2, 4}, BinSum, BinLessY)
+
+  r, err := binning.Refine(2, nil)
+  if err != nil {
+    t.Error(err); return
+  }
+  if x := r.AppendBoundaries(nil); len(x) != 5 || x[1] != 0.5 || x[3] != 2 {
+    t.Error("test failed")
+  }
+  if v := r.AppendValues(nil); v[0] != 1 || v[1] != 1 || v[2] != 2 || v[3] != 2 {
+    t.Error("test failed")
+  }
+  // linearly increasing density
+  model := func(lo, hi float64) float64 { return hi*hi - lo*lo }
+  r, _ = binning.RefineTo([]float64{-1, 0.5, 1, 2, 7}, model)
+  if x := r.AppendBoundaries(nil); len(x) != 5 || x[1] != 0.5 || x[3] != 2 {
+    t.Error("test failed")
+  }
+  if v := r.AppendValues(nil); v[0] != 0.5 || v[1] != 1.5 || v[2] != 1.5 || v[3] != 2.5 {
+    t.Error("test failed")
+  }
+  if _, err := binning.Refine(0, nil); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestGrid3(t *testing.T) {
+
+  f := func(lo, hi float64) float64 { return 2*(hi - lo) }
+
+  binning, _ := New([]float64{0, 1, 3}, nil, nil, BinLessSize, WithLazyValues(f))
+
+  r, err := binning.ToRegularGrid(1, AggSum)
+  if err != nil {
+    t.Error(err); return
+  }
+  if v := r.AppendValues(nil); len(v) != 3 || v[0] != 2 || v[1] != 2 || v[2] != 2 {
+    t.Error("test failed")
+  }
+}
+
+func TestGrid4(t *testing.T) {
+
+  f := func(lo, hi float64) float64 { return 2*(hi - lo) }
+
+  binning, _ := New([]float64{0, 1, 3}, nil, nil, BinLessSize, WithLazyValues(f))
+
+  r, err := binning.Refine(2, nil)
+  if err != nil {
+    t.Error(err); return
+  }
+  if v := r.AppendValues(nil); len(v) != 4 || v[0] != 1 || v[1] != 1 || v[2] != 2 || v[3] != 2 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,201 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package smartbinninggrpc implements the SmartBinning gRPC service defined
+// in smartbinning.proto. The generated code is updated with
+//
+//   protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative smartbinning.proto
+package smartbinninggrpc
+
+/* -------------------------------------------------------------------------- */
+
+import "container/list"
+import "context"
+import "fmt"
+import "io"
+import "sync"
+
+import "google.golang.org/grpc/codes"
+import "google.golang.org/grpc/status"
+
+import "github.com/pbenner/smartBinning"
+
+/* -------------------------------------------------------------------------- */
+
+// Default number of binnings stored by a server (see Server.Capacity)
+const DefaultCapacity = 1024
+
+// Accuracy parameter of the KLL sketch that summarizes samples of Ingest
+const IngestAccuracy = 1024
+
+// Server implements the SmartBinning service. Fitted binnings are stored as
+// immutable snapshots, so that queries do not block each other.
+type Server struct {
+  UnimplementedSmartBinningServer
+  // maximum number of stored binnings, the least recently used binning is
+  // evicted if this number is exceeded
+  Capacity int
+  mutex    sync.Mutex
+  binnings map[string]*list.Element
+  // stored binnings, the most recently used binning first
+  lru      *list.List
+  next     int
+}
+
+type entry struct {
+  id  string
+  f  *smartBinning.Frozen
+}
+
+func NewServer() *Server {
+  return &Server{Capacity: DefaultCapacity, binnings: make(map[string]*list.Element), lru: list.New()}
+}
+
+func (s *Server) lookup(id string) (*smartBinning.Frozen, error) {
+  s.mutex.Lock()
+  defer s.mutex.Unlock()
+  if e, ok := s.binnings[id]; ok {
+    s.lru.MoveToFront(e)
+    return e.Value.(*entry).f, nil
+  }
+  return nil, status.Errorf(codes.NotFound, "binning `%s' not found", id)
+}
+
+func (s *Server) store(binning *smartBinning.Binning) *FitResponse {
+  f := binning.Frozen()
+  s.mutex.Lock()
+  id := fmt.Sprintf("%d", s.next)
+  s.binnings[id] = s.lru.PushFront(&entry{id: id, f: f})
+  s.next++
+  for s.Capacity > 0 && s.lru.Len() > s.Capacity {
+    e := s.lru.Back()
+    s.lru.Remove(e)
+    delete(s.binnings, e.Value.(*entry).id)
+  }
+  s.mutex.Unlock()
+  r := FitResponse{Id: id}
+  for i := 0; i < f.Len(); i++ {
+    r.Bins = append(r.Bins, newBin(f.Bin(i)))
+  }
+  return &r
+}
+
+func newBin(bin smartBinning.Bin) *Bin {
+  return &Bin{Lower: bin.Lower, Upper: bin.Upper, Y: bin.Y}
+}
+
+func lessFunction(method Method) (func(smartBinning.Bin, smartBinning.Bin) bool, error) {
+  switch method {
+  case Method_METHOD_COUNT:
+    return smartBinning.BinLessY, nil
+  case Method_METHOD_WIDTH:
+    return smartBinning.BinLessSize, nil
+  }
+  return nil, status.Errorf(codes.InvalidArgument, "invalid method `%v'", method)
+}
+
+func fit(binning *smartBinning.Binning, err error, bins int32) (*smartBinning.Binning, error) {
+  if err != nil {
+    return nil, status.Error(codes.InvalidArgument, err.Error())
+  }
+  if bins < 1 {
+    return nil, status.Error(codes.InvalidArgument, "number of bins must be positive")
+  }
+  if err := binning.FilterBins(int(bins)); err != nil {
+    return nil, status.Error(codes.Internal, err.Error())
+  }
+  return binning, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (s *Server) Fit(ctx context.Context, request *FitRequest) (*FitResponse, error) {
+  less, err := lessFunction(request.Method)
+  if err != nil {
+    return nil, err
+  }
+  var binning *smartBinning.Binning
+  switch {
+  case len(request.Samples) > 0 && len(request.Boundaries) == 0:
+    binning, err = smartBinning.FromSamples(request.Samples, less)
+  case len(request.Samples) == 0 && len(request.Boundaries) > 0:
+    binning, err = smartBinning.New(request.Boundaries, request.Values, smartBinning.BinSum, less)
+  default:
+    return nil, status.Error(codes.InvalidArgument, "either samples or boundaries must be given")
+  }
+  if binning, err = fit(binning, err, request.Bins); err != nil {
+    return nil, err
+  }
+  return s.store(binning), nil
+}
+
+// Samples are summarized by a KLL sketch, which requires memory independent
+// of the number of samples. Boundaries of the initial binning are placed at
+// IngestAccuracy quantiles of all samples.
+func (s *Server) Ingest(stream SmartBinning_IngestServer) error {
+  var first  *IngestRequest
+  var sketch *smartBinning.KLL
+  for {
+    request, err := stream.Recv()
+    if err == io.EOF {
+      break
+    }
+    if err != nil {
+      return err
+    }
+    if first == nil {
+      first = request
+      if sketch, err = smartBinning.NewKLL(IngestAccuracy, 1); err != nil {
+        return status.Error(codes.Internal, err.Error())
+      }
+    }
+    for _, x := range request.Samples {
+      sketch.Add(x)
+    }
+  }
+  if first == nil {
+    return status.Error(codes.InvalidArgument, "no samples given")
+  }
+  less, err := lessFunction(first.Method)
+  if err != nil {
+    return err
+  }
+  binning, err := sketch.Binning(smartBinning.EqualQuantiles(IngestAccuracy), less)
+  if binning, err = fit(binning, err, first.Bins); err != nil {
+    return err
+  }
+  return stream.SendAndClose(s.store(binning))
+}
+
+func (s *Server) FindBin(ctx context.Context, request *FindBinRequest) (*FindBinResponse, error) {
+  f, err := s.lookup(request.Id)
+  if err != nil {
+    return nil, err
+  }
+  i := f.FindBin(request.X)
+  if i < 0 {
+    return &FindBinResponse{Index: -1}, nil
+  }
+  return &FindBinResponse{Found: true, Index: int32(i), Bin: newBin(f.Bin(i))}, nil
+}
+
+func (s *Server) Aggregate(ctx context.Context, request *AggregateRequest) (*AggregateResponse, error) {
+  f, err := s.lookup(request.Id)
+  if err != nil {
+    return nil, err
+  }
+  return &AggregateResponse{Value: f.Aggregate(request.Lo, request.Hi)}, nil
+}
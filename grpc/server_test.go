@@ -0,0 +1,156 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartbinninggrpc
+
+/* -------------------------------------------------------------------------- */
+
+import   "context"
+import   "math"
+import   "net"
+import   "testing"
+
+import   "google.golang.org/grpc"
+import   "google.golang.org/grpc/codes"
+import   "google.golang.org/grpc/credentials/insecure"
+import   "google.golang.org/grpc/status"
+import   "google.golang.org/grpc/test/bufconn"
+
+/* -------------------------------------------------------------------------- */
+
+func newTestClient(t *testing.T) (SmartBinningClient, func()) {
+  listener := bufconn.Listen(1 << 20)
+  server   := grpc.NewServer()
+  RegisterSmartBinningServer(server, NewServer())
+  go server.Serve(listener)
+
+  conn, err := grpc.NewClient("passthrough:///bufnet",
+    grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+    grpc.WithTransportCredentials(insecure.NewCredentials()))
+  if err != nil {
+    t.Fatal(err)
+  }
+  return NewSmartBinningClient(conn), func() { conn.Close(); server.Stop() }
+}
+
+func TestServer1(t *testing.T) {
+
+  client, stop := newTestClient(t)
+  defer stop()
+
+  ctx := context.Background()
+  r, err := client.Fit(ctx, &FitRequest{Samples: []float64{1, 2, 2, 3, 10, 11}, Bins: 2, Method: Method_METHOD_WIDTH})
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  if len(r.Bins) != 2 || r.Bins[0].Lower != 1 || r.Bins[0].Upper != 3 || r.Bins[0].Y != 3 {
+    t.Error("test failed")
+  }
+  b, err := client.FindBin(ctx, &FindBinRequest{Id: r.Id, X: 10})
+  if err != nil || !b.Found || b.Index != 1 || b.Bin.Y != 3 {
+    t.Error("test failed")
+  }
+  a, err := client.Aggregate(ctx, &AggregateRequest{Id: r.Id, Lo: 0, Hi: 100})
+  if err != nil || a.Value != 6 {
+    t.Error("test failed")
+  }
+  if _, err := client.FindBin(ctx, &FindBinRequest{Id: "foo"}); status.Code(err) != codes.NotFound {
+    t.Error("test failed")
+  }
+  if _, err := client.Fit(ctx, &FitRequest{Bins: 2}); status.Code(err) != codes.InvalidArgument {
+    t.Error("test failed")
+  }
+}
+
+func TestServer2(t *testing.T) {
+
+  client, stop := newTestClient(t)
+  defer stop()
+
+  stream, err := client.Ingest(context.Background())
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  stream.Send(&IngestRequest{Bins: 2, Method: Method_METHOD_WIDTH, Samples: []float64{1, 2, 2}})
+  stream.Send(&IngestRequest{Samples: []float64{3, 10, 11}})
+  r, err := stream.CloseAndRecv()
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  if len(r.Bins) != 2 || r.Bins[1].Lower != 3 || r.Bins[1].Y != 3 {
+    t.Error("test failed")
+  }
+}
+
+func TestServer3(t *testing.T) {
+
+  s := NewServer()
+  s.Capacity = 2
+
+  ctx := context.Background()
+  ids := []string{}
+  for i := 0; i < 3; i++ {
+    r, err := s.Fit(ctx, &FitRequest{Samples: []float64{1, 2, 3}, Bins: 2})
+    if err != nil {
+      t.Error(err); return
+    }
+    ids = append(ids, r.Id)
+    if i == 1 {
+      // mark first binning as recently used
+      if _, err := s.FindBin(ctx, &FindBinRequest{Id: ids[0], X: 1}); err != nil {
+        t.Error(err)
+      }
+    }
+  }
+  // least recently used binning is evicted
+  if _, err := s.FindBin(ctx, &FindBinRequest{Id: ids[1], X: 1}); status.Code(err) != codes.NotFound {
+    t.Error("test failed")
+  }
+  for _, id := range []string{ids[0], ids[2]} {
+    if _, err := s.FindBin(ctx, &FindBinRequest{Id: id, X: 1}); err != nil {
+      t.Error(err)
+    }
+  }
+}
+
+func TestServer4(t *testing.T) {
+
+  client, stop := newTestClient(t)
+  defer stop()
+
+  stream, err := client.Ingest(context.Background())
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  for i := 0; i < 100; i++ {
+    samples := make([]float64, 1000)
+    for j := range samples {
+      samples[j] = float64((i*1000 + j) % 997)
+    }
+    stream.Send(&IngestRequest{Bins: 4, Samples: samples})
+  }
+  r, err := stream.CloseAndRecv()
+  if err != nil {
+    t.Error(err); return
+  }
+  sum := 0.0
+  for _, bin := range r.Bins {
+    sum += bin.Y
+  }
+  if len(r.Bins) != 4 || r.Bins[0].Lower != 0 || math.Abs(sum - 100000) > 1e-6 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,96 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "bufio"
+import "fmt"
+import "io"
+import "strconv"
+import "strings"
+
+/* -------------------------------------------------------------------------- */
+
+// A single step of an HdrHistogram iteration (e.g. linear or logarithmic
+// bucket values), where To is the highest value of the bucket and Count
+// the number of values added in this step
+type HdrBucket struct {
+  To    float64
+  Count float64
+}
+
+// Create a binning from HdrHistogram buckets. Each bucket extends from the
+// upper value of the previous bucket to its own upper value, the first
+// bucket starts at lowest. Buckets must be ordered by their upper value,
+// buckets with identical upper values are coalesced. Leading buckets with
+// upper value lowest are counted in the first bin.
+func FromHdrBuckets(lowest float64, buckets []HdrBucket) (*Binning, error) {
+  x := []float64{lowest}
+  y := []float64{}
+  c := 0.0
+  for _, b := range buckets {
+    switch {
+    case b.To < x[len(x)-1]:
+      return nil, fmt.Errorf("%w: HdrHistogram buckets", ErrUnsorted)
+    case b.To == x[len(x)-1]:
+      if len(y) == 0 {
+        c += b.Count
+      } else {
+        y[len(y)-1] += b.Count
+      }
+    default:
+      x = append(x, b.To)
+      y = append(y, b.Count+c)
+      c = 0.0
+    }
+  }
+  return New(x, y, BinSum, BinLessY)
+}
+
+// Import an HdrHistogram percentile distribution as written by
+// outputPercentileDistribution (hgrm format). Bin values are the number of
+// counts recorded between successive values, the first bin starts at zero.
+func ReadHdrHistogram(reader io.Reader) (*Binning, error) {
+  buckets := []HdrBucket{}
+  total   := 0.0
+  scanner := bufio.NewScanner(reader)
+  for scanner.Scan() {
+    line := strings.TrimSpace(scanner.Text())
+    if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "Value") {
+      continue
+    }
+    fields := strings.Fields(line)
+    if len(fields) < 3 {
+      return nil, fmt.Errorf("%w: HdrHistogram line `%s'", ErrInvalidFormat, line)
+    }
+    v, err := strconv.ParseFloat(fields[0], 64)
+    if err != nil {
+      return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+    }
+    c, err := strconv.ParseFloat(fields[2], 64)
+    if err != nil {
+      return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+    }
+    buckets = append(buckets, HdrBucket{To: v, Count: c-total})
+    total   = c
+  }
+  if err := scanner.Err(); err != nil {
+    return nil, err
+  }
+  return FromHdrBuckets(0.0, buckets)
+}
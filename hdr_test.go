@@ -0,0 +1,84 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "strings"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestHdr1(t *testing.T) {
+
+  s := `       Value     Percentile TotalCount 1/(1-Percentile)
+
+       1.000 0.000000000000          2           1.00
+       2.000 0.500000000000          5           2.00
+       2.000 0.600000000000          5           2.50
+       8.000 0.900000000000          9          10.00
+      16.000 1.000000000000         10
+#[Mean    =        3.000, StdDeviation   =        4.000]
+#[Max     =       16.000, Total count    =           10]
+#[Buckets =           10, SubBuckets     =         2048]
+`
+  binning, err := ReadHdrHistogram(strings.NewReader(s))
+  if err != nil {
+    t.Error(err); return
+  }
+  if len(binning.Bins) != 4 {
+    t.Error("test failed")
+  }
+  if binning.First.Lower != 0 || binning.First.Upper != 1 || binning.First.Y != 2 {
+    t.Error("test failed")
+  }
+  if binning.Bins[1].Y != 3 || binning.Bins[2].Y != 4 {
+    t.Error("test failed")
+  }
+  if binning.Last.Lower != 8 || binning.Last.Upper != 16 || binning.Last.Y != 1 {
+    t.Error("test failed")
+  }
+  if _, err := FromHdrBuckets(0, []HdrBucket{{2, 1}, {1, 1}, {3, 1}}); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestHdr2(t *testing.T) {
+
+  s := `       Value     Percentile TotalCount 1/(1-Percentile)
+
+       0.000 0.000000000000          3           1.00
+       1.000 0.500000000000          5           2.00
+       4.000 1.000000000000         10
+#[Mean    =        1.000, StdDeviation   =        1.000]
+#[Max     =        4.000, Total count    =           10]
+`
+  binning, err := ReadHdrHistogram(strings.NewReader(s))
+  if err != nil {
+    t.Error(err); return
+  }
+  if len(binning.Bins) != 2 {
+    t.Error("test failed")
+  }
+  if binning.First.Lower != 0 || binning.First.Upper != 1 || binning.First.Y != 5 {
+    t.Error("test failed")
+  }
+  if binning.Last.Lower != 1 || binning.Last.Upper != 4 || binning.Last.Y != 5 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,123 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "container/heap"
+import "context"
+
+/* -------------------------------------------------------------------------- */
+
+type binHeap struct {
+  binning *Binning
+  bins    []int32
+}
+
+func newBinHeap(binning *Binning) *binHeap {
+  h := binHeap{binning: binning}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    at.heapIndex = int32(len(h.bins))
+    h.bins = append(h.bins, at.index)
+  }
+  heap.Init(&h)
+  return &h
+}
+
+func (h binHeap) Len() int {
+  return len(h.bins)
+}
+
+func (h binHeap) Less(i, j int) bool {
+  return h.binning.less(&h.binning.Bins[h.bins[i]], &h.binning.Bins[h.bins[j]])
+}
+
+func (h binHeap) Swap(i, j int) {
+  h.bins[i], h.bins[j] = h.bins[j], h.bins[i]
+  h.binning.Bins[h.bins[i]].heapIndex = int32(i)
+  h.binning.Bins[h.bins[j]].heapIndex = int32(j)
+}
+
+func (h *binHeap) Push(x interface{}) {
+  bin := x.(*Bin)
+  bin.heapIndex = int32(len(h.bins))
+  h.bins = append(h.bins, bin.index)
+}
+
+func (h *binHeap) Pop() interface{} {
+  n   := len(h.bins)
+  bin := &h.binning.Bins[h.bins[n-1]]
+  h.bins = h.bins[0:n-1]
+  bin.heapIndex = noBin
+  return bin
+}
+
+func (h *binHeap) fix(bin *Bin) {
+  if bin != nil && bin.heapIndex >= 0 {
+    heap.Fix(h, int(bin.heapIndex))
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Same as FilterBins, but the smallest bin is tracked with a binary heap
+// instead of the sorted linked list, so that each merge costs O(log n)
+// operations independent of the input. The sorted list is updated once
+// all bins are merged.
+func (binning *Binning) FilterBinsHeap(n int) error {
+  return binning.FilterBinsHeapContext(context.Background(), n)
+}
+
+// Same as FilterBinsHeap, but stops merging bins when the context is
+// canceled (see FilterBinsContext)
+func (binning *Binning) FilterBinsHeapContext(ctx context.Context, n int) error {
+  if len(binning.Bins) == 0 || len(binning.Bins) < n {
+    return nil
+  }
+  h := newBinHeap(binning)
+  k := h.Len()
+  m := k - n
+  for i := 0; i < m; i++ {
+    if i % checkInterval == 0 {
+      if ctx.Err() != nil {
+        break
+      }
+      binning.progress(i, m)
+    }
+    bin := heap.Pop(h).(*Bin)
+    if bin.prev == noBin && bin.next == noBin {
+      break
+    }
+    binning.modified(bin)
+    bin = binning.mergeBin(bin)
+    binning.modified(bin)
+    // the order of neighboring bins depends on
+    // the merged bin, hence they must be fixed too
+    h.fix(bin)
+    h.fix(binning.Prev(bin))
+    h.fix(binning.Next(bin))
+  }
+  if err := binning.Update(); err != nil {
+    return err
+  }
+  binning.log("filter", "method", "FilterBinsHeap", "bins", len(binning.Bins), "merges", k-len(binning.Bins))
+  binning.emit(Event{Kind: EventFilter, Bins: len(binning.Bins)})
+  if ctx.Err() == nil {
+    binning.progress(m, m)
+  }
+  return ctx.Err()
+}
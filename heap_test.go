@@ -0,0 +1,72 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestHeap1(t *testing.T) {
+
+  x := []float64{-100,-99,1,2,3,6,8,19,21,120,300,350,355,380}
+  y := []float64{1,2,3,4,5,6,7,8,9,10,11,12,13}
+
+  binning, _ := New(x, y, BinSum, BinLessSize)
+  binning.FilterBinsHeap(5)
+
+  if len(binning.Bins) != 5 {
+    t.Error("test failed")
+  }
+  if binning.First.Lower != -100 {
+    t.Error("test failed")
+  }
+  if binning.First.Upper != 1 {
+    t.Error("test failed")
+  }
+  if binning.Largest.Lower != 120 {
+    t.Error("test failed")
+  }
+  if binning.Largest.Upper != 300 {
+    t.Error("test failed")
+  }
+}
+
+func TestHeap2(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 1001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+  }
+  b1, _ := New(x, nil, BinSum, BinLessSize)
+  b2, _ := New(x, nil, BinSum, BinLessSize)
+  b1.FilterBins    (20)
+  b2.FilterBinsHeap(20)
+
+  if len(b1.Bins) != len(b2.Bins) {
+    t.Error("test failed"); return
+  }
+  for i := 0; i < len(b1.Bins); i++ {
+    if b1.Bins[i].Lower != b2.Bins[i].Lower || b1.Bins[i].Upper != b2.Bins[i].Upper {
+      t.Error("test failed")
+    }
+  }
+}
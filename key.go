@@ -0,0 +1,46 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+/* -------------------------------------------------------------------------- */
+
+// Order bins by a key instead of a less function. The key of each bin is
+// computed once and only recomputed when the bin is merged. If this option
+// is given, the less function passed to New is ignored.
+func WithKey(key func(Bin) float64) Option {
+  return func(c *config) {
+    c.key = key
+  }
+}
+
+func binLessKey(a, b Bin) bool {
+  return a.key < b.key
+}
+
+func (binning *Binning) updateKey(bin *Bin) {
+  if binning.config.key != nil {
+    binning.Value(bin)
+    bin.key = binning.config.key(*bin)
+  }
+}
+
+// Cached value of the key function given by WithKey
+func (bin Bin) Key() float64 {
+  return bin.key
+}
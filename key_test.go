@@ -0,0 +1,55 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestKey1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 1001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+  }
+  n  := 0
+  b1, _ := New(x, nil, BinSum, BinLessSize)
+  b2, _ := New(x, nil, BinSum, nil, WithKey(func(bin Bin) float64 {
+    n++
+    return bin.Size()
+  }))
+  b1.FilterBins(20)
+  b2.FilterBins(20)
+
+  for i := 0; i < len(b1.Bins); i++ {
+    if b1.Bins[i].Lower != b2.Bins[i].Lower || b1.Bins[i].Upper != b2.Bins[i].Upper {
+      t.Error("test failed"); break
+    }
+  }
+  // one evaluation per bin and merge
+  if n != 1000 + 980 {
+    t.Error("test failed")
+  }
+  if b2.Largest.Key() != b2.Largest.Size() {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,239 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "math/rand"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// KLL is a mergeable quantile sketch (Karnin, Lang and Liberty, 2016),
+// which summarizes a stream of samples with O(k) memory. Items at level h
+// of the sketch represent 2^h samples.
+type KLL struct {
+  k       int
+  levels  [][]float64
+  n       int
+  min     float64
+  max     float64
+  rng    *rand.Rand
+}
+
+// Create a sketch with accuracy parameter k, where the rank error is
+// approximately 1.7/k. Compactions are randomized with a random number
+// generator initialized with seed.
+func NewKLL(k int, seed int64) (*KLL, error) {
+  return NewKLLRand(k, rand.New(rand.NewSource(seed)))
+}
+
+// Same as NewKLL, but compactions are randomized with rng, which must not
+// be used concurrently by other goroutines
+func NewKLLRand(k int, rng *rand.Rand) (*KLL, error) {
+  if k < 8 {
+    return nil, fmt.Errorf("%w: accuracy parameter must be at least 8", ErrInvalidArgument)
+  }
+  if rng == nil {
+    return nil, fmt.Errorf("%w: no random number generator given", ErrInvalidArgument)
+  }
+  return &KLL{
+    k     : k,
+    levels: [][]float64{{}},
+    min   : math.Inf( 1),
+    max   : math.Inf(-1),
+    rng   : rng }, nil
+}
+
+// Capacity of level h
+func (sketch *KLL) capacity(h int) int {
+  d := len(sketch.levels) - h - 1
+  return int(math.Max(2, math.Ceil(float64(sketch.k)*math.Pow(2.0/3.0, float64(d)))))
+}
+
+// Compact the lowest level that exceeds its capacity, until the sketch fits
+// into its total capacity
+func (sketch *KLL) compress() {
+  for {
+    size, capacity := 0, 0
+    for h := range sketch.levels {
+      size     += len(sketch.levels[h])
+      capacity += sketch.capacity(h)
+    }
+    if size <= capacity {
+      return
+    }
+    for h := range sketch.levels {
+      if len(sketch.levels[h]) < sketch.capacity(h) {
+        continue
+      }
+      if h+1 == len(sketch.levels) {
+        sketch.levels = append(sketch.levels, []float64{})
+      }
+      level := sketch.levels[h]
+      sort.Float64s(level)
+      // an odd item remains at this level
+      rest := []float64{}
+      if len(level) % 2 == 1 {
+        rest  = append(rest, level[len(level)-1])
+        level = level[0:len(level)-1]
+      }
+      for i := sketch.rng.Intn(2); i < len(level); i += 2 {
+        sketch.levels[h+1] = append(sketch.levels[h+1], level[i])
+      }
+      sketch.levels[h] = append(level[0:0], rest...)
+      break
+    }
+  }
+}
+
+// Add a sample to the sketch. Non-finite samples are ignored.
+func (sketch *KLL) Add(x float64) {
+  if !isFinite(x) {
+    return
+  }
+  sketch.n++
+  sketch.min = math.Min(sketch.min, x)
+  sketch.max = math.Max(sketch.max, x)
+  sketch.levels[0] = append(sketch.levels[0], x)
+  if len(sketch.levels[0]) >= sketch.capacity(0) {
+    sketch.compress()
+  }
+}
+
+// Add all samples summarized by other to this sketch
+func (sketch *KLL) Merge(other *KLL) {
+  for len(sketch.levels) < len(other.levels) {
+    sketch.levels = append(sketch.levels, []float64{})
+  }
+  for h := range other.levels {
+    sketch.levels[h] = append(sketch.levels[h], other.levels[h]...)
+  }
+  sketch.n  += other.n
+  sketch.min = math.Min(sketch.min, other.min)
+  sketch.max = math.Max(sketch.max, other.max)
+  sketch.compress()
+}
+
+// Number of samples
+func (sketch *KLL) Count() int {
+  return sketch.n
+}
+
+// Returns all items sorted by value together with their cumulative weights
+func (sketch *KLL) items() ([]float64, []float64) {
+  type item struct {
+    x float64
+    w float64
+  }
+  items := []item{}
+  for h := range sketch.levels {
+    for _, x := range sketch.levels[h] {
+      items = append(items, item{x, math.Ldexp(1, h)})
+    }
+  }
+  sort.Slice(items, func(i, j int) bool { return items[i].x < items[j].x })
+  x := make([]float64, len(items))
+  w := make([]float64, len(items))
+  for i := range items {
+    x[i] = items[i].x
+    w[i] = items[i].w
+    if i > 0 {
+      w[i] += w[i-1]
+    }
+  }
+  return x, w
+}
+
+// Approximate q-quantile of all samples
+func (sketch *KLL) Quantile(q float64) float64 {
+  if sketch.n == 0 {
+    return math.NaN()
+  }
+  if q <= 0 {
+    return sketch.min
+  }
+  if q >= 1 {
+    return sketch.max
+  }
+  x, w := sketch.items()
+  i := sort.SearchFloat64s(w, q*w[len(w)-1])
+  if i == len(x) {
+    i--
+  }
+  return x[i]
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Create a binning with boundaries at the approximate q-quantiles of all
+// samples, where q must be increasing. The smallest sample is always the
+// first boundary, the upper boundary of the last bin is the next float64
+// after the largest sample. Bin values are the estimated number of samples
+// in each bin. Duplicate quantiles are dropped. If all samples are equal,
+// an empty bin is appended (see FromSamples).
+func (sketch *KLL) Binning(q []float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  if sketch.n == 0 {
+    return nil, fmt.Errorf("%w: no samples", ErrTooFewBoundaries)
+  }
+  b := []float64{sketch.min}
+  for i := range q {
+    if i > 0 && q[i] < q[i-1] {
+      return nil, fmt.Errorf("%w: quantiles must be increasing", ErrUnsorted)
+    }
+    if v := sketch.Quantile(q[i]); v > b[len(b)-1] && v < sketch.max {
+      b = append(b, v)
+    }
+  }
+  b = append(b, math.Nextafter(sketch.max, math.Inf(1)))
+  if sketch.min == sketch.max {
+    // constant samples
+    b = append(b, math.Nextafter(b[1], math.Inf(1)))
+  }
+  if len(b) < 3 {
+    return nil, ErrTooFewBoundaries
+  }
+  // estimate counts from the weights of all items
+  x, w := sketch.items()
+  y := make([]float64, len(b)-1)
+  c := 0.0
+  for i := range y {
+    j := sort.SearchFloat64s(x, b[i+1])
+    if j > 0 {
+      y[i] = w[j-1] - c
+      c    = w[j-1]
+    }
+  }
+  // weights of compacted items do not sum to n exactly
+  if c > 0 {
+    for i := range y {
+      y[i] *= float64(sketch.n)/c
+    }
+  }
+  return New(b, y, BinSum, less, options...)
+}
+
+// Returns the quantile levels 0, 1/n, ..., 1 of n bins with equal counts
+func EqualQuantiles(n int) []float64 {
+  q := make([]float64, n+1)
+  for i := range q {
+    q[i] = float64(i)/float64(n)
+  }
+  return q
+}
@@ -0,0 +1,73 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestKLL1(t *testing.T) {
+
+  a, _ := NewKLL(200, 1)
+  b, _ := NewKLL(200, 2)
+  r := rand.New(rand.NewSource(1))
+  for i := 0; i < 50000; i++ {
+    a.Add(r.Float64())
+    b.Add(1 + r.Float64())
+  }
+  a.Merge(b)
+  if a.Count() != 100000 {
+    t.Error("test failed")
+  }
+  for _, q := range []float64{0.1, 0.25, 0.5, 0.75, 0.9} {
+    if math.Abs(a.Quantile(q) - 2*q) > 0.05 {
+      t.Error("test failed")
+    }
+  }
+  binning, err := a.Binning(EqualQuantiles(4), BinLessY)
+  if err != nil || len(binning.Bins) != 4 {
+    t.Error("test failed"); return
+  }
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if math.Abs(at.Y - 25000) > 2500 {
+      t.Error("test failed")
+    }
+  }
+  if binning.First.Lower != a.Quantile(0) || binning.Last.Upper <= a.Quantile(1) {
+    t.Error("test failed")
+  }
+}
+
+func TestKLL2(t *testing.T) {
+
+  a, _ := NewKLL(200, 1)
+  for i := 0; i < 1000; i++ {
+    a.Add(2)
+  }
+  // constant samples
+  binning, err := a.Binning(EqualQuantiles(4), BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if v := binning.AppendValues(nil); len(v) != 2 || v[0] != 1000 || v[1] != 0 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,102 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Generate log-linear boundaries as used by HdrHistogram, which cover
+// [0, highest] with a relative precision of the given number of significant
+// decimal digits. The first 2^k buckets have width lowest, where 2^k is the
+// smallest power of two not less than 2*10^digits. Each following range
+// [2^k lowest 2^i, 2^k lowest 2^(i+1)) is divided into 2^(k-1) buckets of
+// equal width.
+func LogLinearBoundaries(lowest, highest float64, digits int) ([]float64, error) {
+  if !(lowest > 0) || math.IsInf(lowest, 0) {
+    return nil, fmt.Errorf("%w: lowest discernible value `%f'", ErrInvalidArgument, lowest)
+  }
+  if !(highest >= 2*lowest) || math.IsInf(highest, 0) {
+    return nil, fmt.Errorf("%w: highest value `%f'", ErrInvalidArgument, highest)
+  }
+  if digits < 0 || digits > 5 {
+    return nil, fmt.Errorf("%w: number of significant digits must be between 0 and 5", ErrInvalidArgument)
+  }
+  n := 1
+  for n < 2*int(math.Pow10(digits)) {
+    n *= 2
+  }
+  x := make([]float64, 0, n+1)
+  for i := 0; i <= n; i++ {
+    x = append(x, float64(i)*lowest)
+  }
+  for w := 2*lowest; x[len(x)-1] <= highest; w *= 2 {
+    for i := 0; i < n/2; i++ {
+      x = append(x, x[len(x)-1] + w)
+    }
+  }
+  return x, nil
+}
+
+// Create an empty latency histogram with log-linear buckets (see
+// LogLinearBoundaries), where samples are recorded with AddSample and bins
+// with the fewest samples are merged first
+func NewLatencyHistogram(lowest, highest float64, digits int, options ...Option) (*Binning, error) {
+  x, err := LogLinearBoundaries(lowest, highest, digits)
+  if err != nil {
+    return nil, err
+  }
+  return New(x, []float64{0}, BinSum, BinLessY, options...)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns the q-quantile of the distribution given by the bin values, where
+// values are assumed to be uniformly distributed within bins. NaN is
+// returned if q is not in [0, 1] or if the sum of all values is not
+// positive.
+func (binning *Binning) Quantile(q float64) float64 {
+  n := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    n += at.Y
+  }
+  if !(q >= 0 && q <= 1) || !(n > 0) {
+    return math.NaN()
+  }
+  t := q*n
+  s := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if at.Y > 0 && s + at.Y >= t {
+      return at.Lower + at.Size()*math.Max(0, t-s)/at.Y
+    }
+    s += at.Y
+  }
+  return binning.Last.Upper
+}
+
+// Returns percentiles p (between 0 and 100) of the distribution given by
+// the bin values (see Quantile)
+func (binning *Binning) Percentiles(p ...float64) []float64 {
+  r := make([]float64, len(p))
+  for i := range p {
+    r[i] = binning.Quantile(p[i]/100)
+  }
+  return r
+}
@@ -0,0 +1,52 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestLatency1(t *testing.T) {
+
+  x, err := LogLinearBoundaries(1, 10000, 1)
+  if err != nil {
+    t.Error(err); return
+  }
+  // 32 buckets of width 1, followed by 16 buckets per doubling
+  if x[32] != 32 || x[33] != 34 || x[48] != 64 || x[49] != 68 || x[len(x)-1] <= 10000 {
+    t.Error("test failed")
+  }
+  binning, err := NewLatencyHistogram(1, 10000, 2)
+  if err != nil {
+    t.Error(err); return
+  }
+  for i := 1; i <= 1000; i++ {
+    if err := binning.AddSample(float64(i), 1); err != nil {
+      t.Error(err); return
+    }
+  }
+  p := binning.Percentiles(50, 99, 100)
+  if math.Abs(p[0] - 500) > 5 || math.Abs(p[1] - 990) > 10 || math.Abs(p[2] - 1001) > 10 {
+    t.Error("test failed")
+  }
+  if !math.IsNaN(binning.Quantile(2)) {
+    t.Error("test failed")
+  }
+}
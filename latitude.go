@@ -0,0 +1,96 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Fraction of the surface of a sphere between latitudes lo and hi (in
+// degrees)
+func LatitudeArea(lo, hi float64) float64 {
+  return (math.Sin(hi*math.Pi/180) - math.Sin(lo*math.Pi/180))/2
+}
+
+// Fraction of the surface of a sphere covered by a latitude bin
+func (bin Bin) Area() float64 {
+  return LatitudeArea(bin.Lower, bin.Upper)
+}
+
+// Merge bins with the smallest surface area first, which replaces the width
+// of a bin for latitude binnings
+func BinLessArea(a, b Bin) bool {
+  return a.Area() < b.Area()
+}
+
+// Boundaries of n latitude bands between -90 and 90 degrees that have equal
+// surface area. Bands are narrow near the equator and wide near the poles.
+func EqualAreaLatitudes(n int) ([]float64, error) {
+  if n < 1 {
+    return nil, fmt.Errorf("%w: number of latitude bands must be positive", ErrInvalidArgument)
+  }
+  x := make([]float64, n+1)
+  for i := range x {
+    x[i] = math.Asin(2*float64(i)/float64(n) - 1)*180/math.Pi
+  }
+  // avoid rounding errors at the poles and the equator
+  x[0], x[n] = -90, 90
+  if n % 2 == 0 {
+    x[n/2] = 0
+  }
+  return x, nil
+}
+
+// Create a binning of n equal-area latitude bands (see EqualAreaLatitudes),
+// where the value of each band is the sum of the weights y of all latitudes
+// lat within the band. The northern boundary of the last band includes the
+// north pole.
+func NewLatitudeBinning(lat, y []float64, n int, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  if len(lat) != len(y) {
+    return nil, ErrLengthMismatch
+  }
+  x, err := EqualAreaLatitudes(n)
+  if err != nil {
+    return nil, err
+  }
+  v := make([]float64, n)
+  for i := range lat {
+    if !(lat[i] >= -90 && lat[i] <= 90) {
+      return nil, fmt.Errorf("%w: invalid latitude `%f'", ErrInvalidArgument, lat[i])
+    }
+    j := sort.Search(n, func(j int) bool { return x[j+1] > lat[i] })
+    if j == n {
+      j--
+    }
+    v[j] += y[i]
+  }
+  return New(x, v, sum, less, options...)
+}
+
+// Area-weighted values of all bins, i.e. the value of each bin divided by
+// its fraction of the surface of the sphere
+func (binning *Binning) AreaDensities() []float64 {
+  r := []float64{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    r = append(r, at.Y/at.Area())
+  }
+  return r
+}
@@ -0,0 +1,73 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestLatitude1(t *testing.T) {
+
+  x, err := EqualAreaLatitudes(4)
+  if err != nil {
+    t.Error(err); return
+  }
+  if x[2] != 0 || math.Abs(x[3] - 30) > 1e-12 {
+    t.Error("test failed")
+  }
+  for i := 0; i+1 < len(x); i++ {
+    if math.Abs(LatitudeArea(x[i], x[i+1]) - 0.25) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+  if _, err := EqualAreaLatitudes(0); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestLatitude2(t *testing.T) {
+
+  lat := []float64{-80, -10, 10, 20, 45, 90}
+  y   := []float64{  1,   1,  1,  1,  1,  1}
+
+  binning, err := NewLatitudeBinning(lat, y, 4, BinSum, BinLessArea)
+  if err != nil {
+    t.Error(err); return
+  }
+  if v := binning.AppendValues(nil); v[0] != 1 || v[1] != 1 || v[2] != 2 || v[3] != 2 {
+    t.Error("test failed")
+  }
+  if d := binning.AreaDensities(); d[3] != 8 {
+    t.Error("test failed")
+  }
+  less, err := LookupLess("area")
+  if err != nil {
+    t.Error(err); return
+  }
+  // equal-area bands are merged from south to north
+  binning, _ = NewLatitudeBinning(lat, y, 4, BinSum, less)
+  binning.FilterBins(3)
+  if x := binning.AppendBoundaries(nil); len(x) != 4 || x[1] != 0 {
+    t.Error("test failed")
+  }
+  if _, err := NewLatitudeBinning([]float64{91}, []float64{1}, 4, BinSum, less); err == nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,64 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Function that computes the value of the bin [lo, hi)
+type YFunc func(lo, hi float64) float64
+
+// Compute bin values lazily with f instead of passing values to New and
+// merging them with the sum function, which is ignored. The value of a bin
+// is computed when it is first needed and cached until the bin is merged.
+// Values that are not yet computed are NaN, hence the less function must
+// not depend on Y, e.g. BinLessSize. Bins are ordered by their values with
+// WithKey, which computes values before calling the key function. Methods
+// that read Y directly, such as Aggregate, require that all values are
+// computed with Evaluate. The function f must be safe for concurrent use if
+// WithParallelism is given.
+func WithLazyValues(f YFunc) Option {
+  return func(c *config) {
+    c.yfunc = f
+  }
+}
+
+// Sum function of lazy binnings, merged values are computed on demand
+func binSumLazy(a, b Bin) float64 {
+  return math.NaN()
+}
+
+// Returns the value of a bin, which is computed if necessary (see
+// WithLazyValues)
+func (binning *Binning) Value(bin *Bin) float64 {
+  if bin.stale {
+    bin.Y     = binning.config.yfunc(bin.Lower, bin.Upper)
+    bin.stale = false
+    binning.indexUpdate(bin)
+  }
+  return bin.Y
+}
+
+// Compute values of all bins (see WithLazyValues)
+func (binning *Binning) Evaluate() {
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    binning.Value(at)
+  }
+}
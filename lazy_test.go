@@ -0,0 +1,57 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestLazy1(t *testing.T) {
+
+  calls := 0
+  f := func(lo, hi float64) float64 {
+    calls++
+    return hi*hi - lo*lo
+  }
+  x := []float64{0, 1, 3, 4, 8}
+  // values are not needed for ordering bins by size
+  binning, err := New(x, nil, nil, BinLessSize, WithLazyValues(f))
+  if err != nil {
+    t.Error(err); return
+  }
+  binning.FilterBins(2)
+  if calls != 0 {
+    t.Error("test failed")
+  }
+  if v := binning.AppendValues(nil); len(v) != 2 || v[0] != 16 || v[1] != 48 || calls != 2 {
+    t.Error("test failed")
+  }
+  binning.AppendValues(nil)
+  if calls != 2 {
+    t.Error("test failed")
+  }
+  // values are computed for ordering bins by value
+  lazy, _ := New(x, nil, nil, nil, WithLazyValues(f), WithKey(func(bin Bin) float64 { return bin.Y }))
+  eager, _ := New(x, []float64{1, 8, 7, 48}, BinSum, BinLessY)
+  lazy .FilterBins(2)
+  eager.FilterBins(2)
+  if lazy.String() != eager.String() {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,86 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "log"
+import "strings"
+
+/* -------------------------------------------------------------------------- */
+
+// Logger receives structured events of a binning. Each event has a name and a
+// list of alternating keys and values. The following events are emitted:
+//
+//   construct  bins
+//   merge      lower, upper, y (deleted bin), into_lower, into_upper, into_y (resulting bin)
+//   filter     method, bins, merges
+//   split      lower, upper (original bin), at, left_y, right_y
+//   undo       lower, upper, y (recreated bin)
+//   rollback   bins
+//
+// Events within a transaction (see Begin) are logged when the transaction
+// is committed and dropped when it is rolled back.
+type Logger interface {
+  Log(event string, keyvals ...interface{})
+}
+
+// Send all events of a binning to logger
+func WithLogger(logger Logger) Option {
+  return func(c *config) {
+    c.logger = logger
+  }
+}
+
+// Event that is logged when a transaction is committed
+type logRecord struct {
+  event   string
+  keyvals []interface{}
+}
+
+func (binning *Binning) log(event string, keyvals ...interface{}) {
+  if binning.config.logger == nil {
+    return
+  }
+  if binning.tx != nil {
+    binning.tx.logs = append(binning.tx.logs, logRecord{event, keyvals})
+    return
+  }
+  binning.config.logger.Log(event, keyvals...)
+}
+
+/* -------------------------------------------------------------------------- */
+
+type stdLogger struct {
+  logger *log.Logger
+}
+
+// Create a Logger that writes each event as a single line of key=value pairs
+// to logger
+func NewStdLogger(logger *log.Logger) Logger {
+  return stdLogger{logger}
+}
+
+func (obj stdLogger) Log(event string, keyvals ...interface{}) {
+  var builder strings.Builder
+  fmt.Fprintf(&builder, "event=%s", event)
+  for i := 0; i+1 < len(keyvals); i += 2 {
+    fmt.Fprintf(&builder, " %v=%v", keyvals[i], keyvals[i+1])
+  }
+  obj.logger.Print(builder.String())
+}
@@ -0,0 +1,107 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "bytes"
+import   "log"
+import   "strings"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+type testLogger struct {
+  events []string
+}
+
+func (obj *testLogger) Log(event string, keyvals ...interface{}) {
+  obj.events = append(obj.events, event)
+}
+
+func TestLogger1(t *testing.T) {
+
+  x := []float64{-100, 0, 80, 120, 220, 380}
+  y := []float64{3, 4, 7, 1, 2}
+
+  logger     := &testLogger{}
+  binning, _ := New(x, y, BinSum, BinLessSize, WithLogger(logger))
+  binning.FilterBins(2)
+
+  if strings.Join(logger.events, " ") != "construct merge merge merge filter" {
+    t.Error("test failed")
+  }
+}
+
+func TestLogger2(t *testing.T) {
+
+  x := []float64{-100, 0, 80, 120, 220, 380}
+  y := []float64{3, 4, 7, 1, 2}
+
+  var buffer bytes.Buffer
+  binning, _ := New(x, y, BinSum, BinLessSize, WithLogger(NewStdLogger(log.New(&buffer, "", 0))))
+  binning.FilterBins(4)
+
+  lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+  if len(lines) != 3 {
+    t.Error("test failed")
+  }
+  if lines[0] != "event=construct bins=5" {
+    t.Error("test failed")
+  }
+  if lines[1] != "event=merge lower=80 upper=120 y=7 into_lower=0 into_upper=120 into_y=11" {
+    t.Error("test failed")
+  }
+  if lines[2] != "event=filter method=FilterBins bins=4 merges=1" {
+    t.Error("test failed")
+  }
+}
+
+func TestLogger3(t *testing.T) {
+
+  x := []float64{-100, 0, 80, 120, 220, 380}
+  y := []float64{3, 4, 7, 1, 2}
+
+  logger     := &testLogger{}
+  binning, _ := New(x, y, BinSum, BinLessSize, WithLogger(logger))
+
+  tx := binning.Begin()
+  tx.Delete(binning.Smallest)
+  inner := binning.Begin()
+  inner.Delete(binning.Smallest)
+  inner.Rollback()
+  if strings.Join(logger.events, " ") != "construct" {
+    t.Error("test failed")
+  }
+  tx.Commit()
+  if strings.Join(logger.events, " ") != "construct merge rollback" {
+    t.Error("test failed")
+  }
+  // merges of a failed replay are not logged
+  logger.events = nil
+  trace := "boundaries -0x1.9p+06 0x0p+00 0x1.4p+06 0x1.ep+06 0x1.b8p+07 0x1.7cp+08\n" +
+    "values 0x1.8p+01 0x1p+02 0x1.cp+02 0x1p+00 0x1p+01\n" +
+    "merge 1 0x0p+00 right 0x1p+00\n" +
+    "merge 0 0x1p+00 right 0x1p+00\n"
+  binning, _ = New(x, y, BinSum, BinLessSize, WithLogger(logger))
+  if err := binning.ReplayTrace(strings.NewReader(trace)); err == nil {
+    t.Error("test failed")
+  }
+  if strings.Join(logger.events, " ") != "construct rollback" {
+    t.Error("test failed")
+  }
+}
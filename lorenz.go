@@ -0,0 +1,81 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// Check that the binning is a grouped distribution of non-negative
+// quantities and return the total population and the total quantity, where
+// the values of each bin are treated as uniformly distributed within the bin
+func (binning *Binning) groupedTotals() (float64, float64, error) {
+  n, m := 0.0, 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if at.Lower < 0 || at.Y < 0 {
+      return 0, 0, fmt.Errorf("%w: bins and values must be non-negative", ErrInvalidArgument)
+    }
+    n += at.Y
+    m += at.Y*(at.Lower + at.Upper)/2
+  }
+  if n <= 0 || m <= 0 {
+    return 0, 0, fmt.Errorf("%w: empty distribution", ErrInvalidArgument)
+  }
+  return n, m, nil
+}
+
+// Lorenz curve of the grouped distribution given by the binning, i.e. the
+// cumulative population shares and the corresponding cumulative shares of
+// the total quantity at all bin boundaries. Bin values are the population
+// of each bin, which is uniformly distributed within the bin.
+func (binning *Binning) Lorenz() ([]float64, []float64, error) {
+  n, m, err := binning.groupedTotals()
+  if err != nil {
+    return nil, nil, err
+  }
+  p := []float64{0}
+  l := []float64{0}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    p = append(p, p[len(p)-1] + at.Y/n)
+    l = append(l, l[len(l)-1] + at.Y*(at.Lower + at.Upper)/2/m)
+  }
+  return p, l, nil
+}
+
+// Gini coefficient of the grouped distribution given by the binning (see
+// Lorenz). The coefficient is the sum of the Gini coefficient between bins
+// and the contributions of the uniform distributions within bins.
+func (binning *Binning) Gini() (float64, error) {
+  n, m, err := binning.groupedTotals()
+  if err != nil {
+    return 0, err
+  }
+  g := 1.0
+  l := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    p := at.Y/n
+    s := at.Y*(at.Lower + at.Upper)/2/m
+    g -= p*(2*l + s)
+    if at.Lower + at.Upper > 0 {
+      g += p*s*at.Size()/(3*(at.Lower + at.Upper))
+    }
+    l += s
+  }
+  return g, nil
+}
@@ -0,0 +1,49 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestLorenz1(t *testing.T) {
+
+  // uniform distribution on [0, 1] has a Gini coefficient of 1/3, which
+  // must not depend on the binning
+  for _, x := range [][]float64{{0, 0.5, 1}, {0, 0.25, 0.5, 1}, {0, 0.1, 0.2, 0.7, 1}} {
+    y := make([]float64, len(x)-1)
+    for i := range y {
+      y[i] = x[i+1] - x[i]
+    }
+    binning, _ := New(x, y, BinSum, BinLessSize)
+    if g, err := binning.Gini(); err != nil || math.Abs(g - 1.0/3.0) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+  binning, _ := New([]float64{0, 1, 3}, []float64{1, 1}, BinSum, BinLessSize)
+  p, l, err := binning.Lorenz()
+  if err != nil || len(p) != 3 || p[1] != 0.5 || l[1] != 0.2 || l[2] != 1 {
+    t.Error("test failed")
+  }
+  binning, _ = New([]float64{-1, 1, 3}, []float64{1, 1}, BinSum, BinLessSize)
+  if _, err := binning.Gini(); err == nil {
+    t.Error("test failed")
+  }
+}
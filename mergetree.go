@@ -0,0 +1,131 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "context"
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// Node of the tree of merges performed by FilterBinsTree. Each node is a
+// bin, the children of a node are the two bins that were merged into it
+// ordered by position. Leaves are the bins before filtering.
+type MergeNode struct {
+  Bin      Bin
+  Children []*MergeNode
+  // number of the merge that created this node, starting at one, or zero
+  // for leaves
+  Step     int
+}
+
+// Returns true if the node is a bin before filtering
+func (node *MergeNode) Leaf() bool {
+  return len(node.Children) == 0
+}
+
+// Returns the leaves of the subtree in order
+func (node *MergeNode) Leaves() []*MergeNode {
+  if node.Leaf() {
+    return []*MergeNode{node}
+  }
+  r := []*MergeNode{}
+  for _, child := range node.Children {
+    r = append(r, child.Leaves()...)
+  }
+  return r
+}
+
+// Replace the i-th bin of a sequence of nodes, e.g. the roots returned by
+// FilterBinsTree, by its children, i.e. undo the merge that created this
+// bin. A new slice is returned.
+func Refine(nodes []*MergeNode, i int) ([]*MergeNode, error) {
+  if i < 0 || i >= len(nodes) {
+    return nil, fmt.Errorf("%w: invalid node `%d'", ErrOutOfRange, i)
+  }
+  if nodes[i].Leaf() {
+    return nil, fmt.Errorf("%w: node `%d' cannot be refined", ErrInvalidArgument, i)
+  }
+  r := make([]*MergeNode, 0, len(nodes)+1)
+  r  = append(r, nodes[0:i]...)
+  r  = append(r, nodes[i].Children...)
+  r  = append(r, nodes[i+1:]...)
+  return r, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Same as FilterBins, but returns the tree of all merges, i.e. the final
+// bins in order as roots of their merge trees. Modified bins are reinserted
+// (see Update) before merging.
+func (binning *Binning) FilterBinsTree(n int) ([]*MergeNode, error) {
+  return binning.FilterBinsTreeContext(context.Background(), n)
+}
+
+// Same as FilterBinsTree, but stops merging bins when the context is
+// canceled (see FilterBinsContext)
+func (binning *Binning) FilterBinsTreeContext(ctx context.Context, n int) ([]*MergeNode, error) {
+  // reinsert modified bins, which are not in the sorted list
+  if err := binning.Update(); err != nil {
+    return nil, err
+  }
+  // current node of each bin
+  nodes := make([]*MergeNode, len(binning.Bins))
+  for i := range binning.Bins {
+    bin := &binning.Bins[i]
+    nodes[i] = &MergeNode{Bin: Bin{Y: bin.Y, Lower: bin.Lower, Upper: bin.Upper, merged: bin.merged}}
+  }
+  k := len(binning.Bins)
+  m := k - n
+  for i := 0; i < m; i++ {
+    if i % checkInterval == 0 {
+      if ctx.Err() != nil {
+        break
+      }
+      binning.progress(i, m)
+    }
+    if binning.Smallest == nil {
+      break
+    }
+    d := nodes[binning.Smallest.index]
+    bin, err := binning.Delete(binning.Smallest)
+    if err != nil {
+      return nil, err
+    }
+    s    := nodes[bin.index]
+    node := &MergeNode{Bin: Bin{Y: bin.Y, Lower: bin.Lower, Upper: bin.Upper, merged: bin.merged}, Step: i+1}
+    if bin.Upper == s.Bin.Upper {
+      // deleted bin preceded the resulting bin
+      node.Children = []*MergeNode{d, s}
+    } else {
+      node.Children = []*MergeNode{s, d}
+    }
+    nodes[bin.index] = node
+  }
+  r := []*MergeNode{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    r = append(r, nodes[at.index])
+  }
+  binning.Compact()
+  binning.log("filter", "method", "FilterBinsTree", "bins", len(binning.Bins), "merges", k-len(binning.Bins))
+  binning.emit(Event{Kind: EventFilter, Bins: len(binning.Bins)})
+  if ctx.Err() == nil && m > 0 {
+    binning.progress(m, m)
+  }
+  return r, ctx.Err()
+}
@@ -0,0 +1,65 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestMergeTree1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 4, 5}, []float64{1, 5, 2, 8}, BinSum, BinLessY)
+
+  roots, err := binning.FilterBinsTree(2)
+  if err != nil || len(roots) != 2 {
+    t.Error("test failed"); return
+  }
+  // merges: [0,1) into [1,2), then [2,4) into [0,2)
+  if roots[0].Step != 2 || roots[0].Bin.String() != "[0.000000, 4.000000):8" || len(roots[0].Leaves()) != 3 {
+    t.Error("test failed")
+  }
+  if !roots[1].Leaf() || roots[1].Bin.Y != 8 {
+    t.Error("test failed")
+  }
+  refined, err := Refine(roots, 0)
+  if err != nil || len(refined) != 3 || refined[0].Bin.String() != "[0.000000, 2.000000):6" || refined[1].Bin.String() != "[2.000000, 4.000000):2" {
+    t.Error("test failed")
+  }
+  if _, err := Refine(refined, 2); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestMergeTree2(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 4, 5}, []float64{8, 2, 5, 1}, BinSum, BinLessY)
+
+  // modify all bins without calling Update
+  for i, y := range []float64{1, 5, 2, 8} {
+    binning.Bins[i].Y = y
+    binning.Modified(&binning.Bins[i])
+  }
+  roots, err := binning.FilterBinsTree(2)
+  if err != nil || len(roots) != 2 {
+    t.Error("test failed"); return
+  }
+  if roots[0].Step != 2 || roots[0].Bin.String() != "[0.000000, 4.000000):8" || len(roots[0].Leaves()) != 3 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,96 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Collect NaN samples in a separate bin (see Binning.Missing), which is not
+// part of the real line and therefore never merged with other bins
+func WithMissingBin() Option {
+  return func(c *config) {
+    c.missing = true
+  }
+}
+
+// Same as index, but NaN values are mapped to the position of the missing
+// bin, which follows all other bins
+func (binning *Binning) indexMissing(x float64) int {
+  if math.IsNaN(x) && binning.Missing != nil {
+    return len(binning.Bins) - binning.deleted
+  }
+  return binning.index(x)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Weight of evidence of each bin for samples x with binary target, i.e. the
+// logarithm of the fraction of all negative samples in a bin divided by the
+// fraction of all positive samples in the bin, and the information value
+// of the binning. If the binning has a missing bin, its weight of evidence
+// follows those of all other bins and NaN samples are counted by it. Other
+// samples outside the binning are ignored. A count of 0.5 is used for bins
+// without negative or positive samples.
+func (binning *Binning) WeightOfEvidence(x []float64, target []bool) ([]float64, float64, error) {
+  if len(x) != len(target) {
+    return nil, 0, ErrLengthMismatch
+  }
+  n := len(binning.Bins) - binning.deleted
+  m := n
+  if binning.Missing != nil {
+    m++
+  }
+  neg := make([]float64, m)
+  pos := make([]float64, m)
+  for i := range x {
+    j := binning.indexMissing(x[i])
+    if j < 0 {
+      continue
+    }
+    if target[i] {
+      pos[j]++
+    } else {
+      neg[j]++
+    }
+  }
+  return weightOfEvidence(neg, pos)
+}
+
+// Weight of evidence and information value given the numbers of negative
+// and positive samples in each bin
+func weightOfEvidence(neg, pos []float64) ([]float64, float64, error) {
+  tn, tp := 0.0, 0.0
+  for j := range neg {
+    tn += neg[j]
+    tp += pos[j]
+  }
+  if tn == 0 || tp == 0 {
+    return nil, 0, fmt.Errorf("%w: samples of both classes are required", ErrInvalidArgument)
+  }
+  woe := make([]float64, len(neg))
+  iv  := 0.0
+  for j := range woe {
+    a, b := math.Max(neg[j], 0.5)/tn, math.Max(pos[j], 0.5)/tp
+    woe[j] = math.Log(a/b)
+    iv    += (a - b)*woe[j]
+  }
+  return woe, iv, nil
+}
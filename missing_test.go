@@ -0,0 +1,96 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "errors"
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestMissing1(t *testing.T) {
+
+  nan  := math.NaN()
+  data := []float64{1, 2, nan, 3, 4, nan, 5, 6}
+
+  binning, err := FromSamples(data, BinLessY, WithMissingBin())
+  if err != nil {
+    t.Error(err); return
+  }
+  if binning.Missing == nil || binning.Missing.Y != 2 {
+    t.Error("test failed"); return
+  }
+  binning.FilterBins(2)
+  if binning.Missing.Y != 2 || binning.AppendValues(nil)[0] != 2 {
+    t.Error("test failed")
+  }
+  binning.AddSample(nan, 1)
+  if binning.Missing.Y != 3 {
+    t.Error("test failed")
+  }
+  if _, err := binning.Delete(binning.Missing); !errors.Is(err, ErrForeignBin) {
+    t.Error("test failed")
+  }
+  if binning, _ := FromSamples(data, BinLessY); binning.Missing != nil {
+    t.Error("test failed")
+  }
+}
+
+func TestMissing2(t *testing.T) {
+
+  nan  := math.NaN()
+  data := []float64{1, 2, nan, 3, 4, nan, 5, 6}
+
+  d := NewDiscretizer(EqualCountStrategy(2, WithMissingBin()))
+  r, err := d.FitTransform(data)
+  if err != nil {
+    t.Error(err); return
+  }
+  if r[2] != 2 || r[0] != 0 || r[7] != 1 || !math.IsNaN(d.InverseTransform(r)[2]) {
+    t.Error("test failed")
+  }
+  if d.OneHotWidth(0) != 3 || d.OneHotSparse([]float64{nan}, 0)[0] != 2 {
+    t.Error("test failed")
+  }
+}
+
+func TestMissing3(t *testing.T) {
+
+  nan    := math.NaN()
+  data   := []float64{1, 2, nan, 3, 4, nan, 5, 6}
+  target := []bool{false, false, true, false, true, true, true, false}
+
+  binning, _ := FromSamples(data, BinLessY, WithMissingBin())
+  binning.FilterBins(2)
+
+  woe, iv, err := binning.WeightOfEvidence(data, target)
+  if err != nil {
+    t.Error(err); return
+  }
+  // negative samples: 2, 2, 0.5 (smoothed); positive samples: 0.5, 2, 2
+  w := []float64{math.Log(2.0/4/(0.5/4)), 0, math.Log(0.5/4/(2.0/4))}
+  if len(woe) != 3 || iv <= 0 {
+    t.Error("test failed"); return
+  }
+  for i := range w {
+    if math.Abs(woe[i] - w[i]) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+}
@@ -0,0 +1,66 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "reflect"
+import "unsafe"
+
+/* -------------------------------------------------------------------------- */
+
+// Create an arena for n bins that is backed by a memory-mapped file at
+// path, which is created or truncated. Pages of the arena are swapped to
+// the file by the operating system, so that binnings with more initial bins
+// than fit into memory can be processed (see WithArena). Bins of such
+// binnings must not hold payloads (see Bin.Data), since the garbage
+// collector does not scan memory-mapped files. Compact (and hence
+// FilterBins) moves the remaining bins to the heap, afterwards the arena
+// can be released with Close. Bins that are still in the arena become
+// invalid when the arena is released.
+func NewMmapArena(path string, n int) (*Arena, error) {
+  if n < 1 {
+    return nil, fmt.Errorf("%w: arena size must be positive", ErrInvalidArgument)
+  }
+  m := n*int(unsafe.Sizeof(Bin{}))
+  data, unmap, err := mmapFile(path, m + n*int(unsafe.Sizeof(int32(0))))
+  if err != nil {
+    return nil, err
+  }
+  arena := Arena{unmap: unmap}
+  bins  := (*reflect.SliceHeader)(unsafe.Pointer(&arena.bins))
+  bins.Data = uintptr(unsafe.Pointer(&data[0]))
+  bins.Len  = n
+  bins.Cap  = n
+  order := (*reflect.SliceHeader)(unsafe.Pointer(&arena.order))
+  order.Data = uintptr(unsafe.Pointer(&data[m]))
+  order.Len  = n
+  order.Cap  = n
+  return &arena, nil
+}
+
+// Release the memory of a memory-mapped arena. All binnings allocated from
+// the arena become invalid. Close has no effect on other arenas.
+func (arena *Arena) Close() error {
+  if arena.unmap == nil {
+    return nil
+  }
+  unmap := arena.unmap
+  arena.bins, arena.order, arena.n, arena.unmap = nil, nil, 0, nil
+  return unmap()
+}
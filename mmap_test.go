@@ -0,0 +1,100 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "io/ioutil"
+import   "math/rand"
+import   "os"
+import   "path/filepath"
+import   "runtime"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestMmap1(t *testing.T) {
+  if runtime.GOOS == "windows" || runtime.GOOS == "js" {
+    t.Skip("memory-mapped arenas are not supported")
+  }
+  dir, err := ioutil.TempDir("", "smartBinning")
+  if err != nil {
+    t.Error(err); return
+  }
+  defer os.RemoveAll(dir)
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 1001)
+  y := make([]float64, 1000)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(r.Intn(10))
+  }
+  arena, err := NewMmapArena(filepath.Join(dir, "bins"), 1000)
+  if err != nil {
+    t.Error(err); return
+  }
+  b1, _ := New(x, y, BinSum, BinLessY, WithArena(arena))
+  b2, _ := New(x, y, BinSum, BinLessY)
+
+  if &b1.Bins[0] != &arena.bins[0] {
+    t.Error("test failed")
+  }
+  b1.FilterBins(10)
+  b2.FilterBins(10)
+
+  if b1.String() != b2.String() {
+    t.Error("test failed")
+  }
+  if err := arena.Close(); err != nil {
+    t.Error(err)
+  }
+}
+
+func TestMmap2(t *testing.T) {
+  if runtime.GOOS == "windows" || runtime.GOOS == "js" {
+    t.Skip("memory-mapped arenas are not supported")
+  }
+  dir, err := ioutil.TempDir("", "smartBinning")
+  if err != nil {
+    t.Error(err); return
+  }
+  defer os.RemoveAll(dir)
+
+  x := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8}
+  y := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+
+  arena, err := NewMmapArena(filepath.Join(dir, "bins"), 10)
+  if err != nil {
+    t.Error(err); return
+  }
+  b1, _ := New(x, y, BinSum, BinLessY, WithArena(arena))
+  b2, _ := New(x, y, BinSum, BinLessY)
+
+  b1.FilterBins(3)
+  b2.FilterBins(3)
+  // bins are moved to the heap
+  if err := arena.Close(); err != nil {
+    t.Error(err); return
+  }
+  if b1.String() != b2.String() {
+    t.Error("test failed")
+  }
+  if err := b1.Reset(x, y); err != nil || len(b1.Bins) != 8 {
+    t.Error("test failed")
+  }
+}
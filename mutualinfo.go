@@ -0,0 +1,114 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Count samples x with class labels in {0, ..., classes-1} for each bin,
+// i.e. r[i][c] is the number of samples in the i-th bin with label c.
+// Samples outside the binning are ignored.
+func (binning *Binning) JointCounts(x []float64, labels []int, classes int) ([][]float64, error) {
+  if len(x) != len(labels) {
+    return nil, ErrLengthMismatch
+  }
+  n := len(binning.Bins) - binning.deleted
+  r := make([][]float64, n)
+  for i := range r {
+    r[i] = make([]float64, classes)
+  }
+  for i := range x {
+    if labels[i] < 0 || labels[i] >= classes {
+      return nil, fmt.Errorf("%w: label `%d'", ErrOutOfRange, labels[i])
+    }
+    if j := binning.index(x[i]); j >= 0 {
+      r[j][labels[i]]++
+    }
+  }
+  return r, nil
+}
+
+// Compute the marginal entropies of bins and classes and the mutual
+// information in nats
+func entropies(counts [][]float64) (float64, float64, float64) {
+  n  := 0.0
+  nc := []float64{}
+  for i := range counts {
+    for c, v := range counts[i] {
+      for len(nc) <= c {
+        nc = append(nc, 0)
+      }
+      nc[c] += v
+      n     += v
+    }
+  }
+  if n <= 0 {
+    return 0, 0, 0
+  }
+  hb, hc, mi := 0.0, 0.0, 0.0
+  for _, v := range nc {
+    if v > 0 {
+      hc -= v/n*math.Log(v/n)
+    }
+  }
+  for i := range counts {
+    ni := 0.0
+    for _, v := range counts[i] {
+      ni += v
+    }
+    if ni > 0 {
+      hb -= ni/n*math.Log(ni/n)
+    }
+    for c, v := range counts[i] {
+      if v > 0 {
+        mi += v/n*math.Log(v*n/(ni*nc[c]))
+      }
+    }
+  }
+  return hb, hc, math.Max(0, mi)
+}
+
+// Mutual information in nats between bins and classes given the joint
+// counts (see JointCounts)
+func MutualInformation(counts [][]float64) float64 {
+  _, _, mi := entropies(counts)
+  return mi
+}
+
+// Mutual information normalized by the geometric mean of both entropies,
+// which is in [0, 1]
+func NormalizedMutualInformation(counts [][]float64) float64 {
+  hb, hc, mi := entropies(counts)
+  if hb <= 0 || hc <= 0 {
+    return 0
+  }
+  return mi/math.Sqrt(hb*hc)
+}
+
+// Fraction of the entropy of the classes explained by the bins, i.e. the
+// mutual information divided by the entropy of the classes
+func UncertaintyCoefficient(counts [][]float64) float64 {
+  _, hc, mi := entropies(counts)
+  if hc <= 0 {
+    return 0
+  }
+  return mi/hc
+}
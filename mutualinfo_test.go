@@ -0,0 +1,46 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestMutualInformation1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2}, nil, BinSum, BinLessSize)
+
+  counts, err := binning.JointCounts([]float64{0.5, 0.5, 1.5, 1.5, 5}, []int{0, 0, 1, 1, 0}, 2)
+  if err != nil || counts[0][0] != 2 || counts[0][1] != 0 || counts[1][1] != 2 {
+    t.Error("test failed"); return
+  }
+  // bins determine the class
+  if math.Abs(MutualInformation(counts) - math.Log(2)) > 1e-12 || math.Abs(NormalizedMutualInformation(counts) - 1) > 1e-12 {
+    t.Error("test failed")
+  }
+  // bins and classes are independent
+  counts = [][]float64{{1, 1}, {2, 2}}
+  if MutualInformation(counts) != 0 || UncertaintyCoefficient(counts) != 0 {
+    t.Error("test failed")
+  }
+  if _, err := binning.JointCounts([]float64{0.5}, []int{2}, 2); err == nil {
+    t.Error("test failed")
+  }
+}
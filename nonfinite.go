@@ -0,0 +1,165 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Policy for handling NaN and infinite boundaries as well as NaN values.
+// Infinite values are always accepted, since they are required for
+// representing log(0) in log-space.
+type NonFinitePolicy int
+
+const (
+  // Use boundaries and values as given (default)
+  IgnoreNonFinite NonFinitePolicy = iota
+  // Return an error
+  RejectNonFinite
+  // Drop boundaries and NaN values, the range of a dropped bin is covered
+  // by its left neighbor
+  DropNonFinite
+  // Assign infinite boundaries to the first or last bin, i.e. the value of
+  // a bin with lower boundary -Inf is merged with the first bin and the
+  // value of a bin with lower boundary +Inf, or upper boundary +Inf, with
+  // the last bin. NaN boundaries and values are dropped.
+  ClampNonFiniteToTails
+)
+
+// Set the policy for handling NaN and infinite boundaries and values
+func WithNonFinitePolicy(p NonFinitePolicy) Option {
+  return func(c *config) {
+    c.nonFinite = p
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func isFinite(x float64) bool {
+  return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+// Apply the non-finite policy to boundaries x and values y. The arguments
+// are returned unmodified if they contain no non-finite values.
+func (binning *Binning) filterNonFinite(x, y []float64) ([]float64, []float64, error) {
+  n  := len(x)-1
+  ok := true
+  for i := 0; i <= n && ok; i++ {
+    ok = isFinite(x[i])
+  }
+  for i := 0; i < len(y) && ok; i++ {
+    ok = !math.IsNaN(y[i])
+  }
+  if ok || binning.config.nonFinite == IgnoreNonFinite {
+    return x, y, nil
+  }
+  if binning.config.nonFinite == RejectNonFinite {
+    return nil, nil, fmt.Errorf("%w in boundaries or values", ErrNonFinite)
+  }
+  // value of bin i
+  value := func(i int) float64 {
+    switch len(y) {
+    case 0:
+      return 0.0
+    case 1:
+      return y[0]
+    default:
+      return y[i]
+    }
+  }
+  rx := []float64{}
+  ry := []float64{}
+  // values of bins with infinite lower boundaries
+  var lo, hi []float64
+  for i := 0; i < n; i++ {
+    switch {
+    case math.IsNaN(x[i]) || math.IsNaN(value(i)):
+    case math.IsInf(x[i], -1):
+      lo = append(lo, value(i))
+    case math.IsInf(x[i],  1):
+      hi = append(hi, value(i))
+    default:
+      rx = append(rx, x[i])
+      ry = append(ry, value(i))
+    }
+  }
+  // upper boundary
+  if isFinite(x[n]) {
+    rx = append(rx, x[n])
+  } else if len(rx) > 0 {
+    // use largest lower boundary as upper boundary
+    k := 0
+    for i := 1; i < len(rx); i++ {
+      if rx[i] > rx[k] {
+        k = i
+      }
+    }
+    rx[k], rx[len(rx)-1] = rx[len(rx)-1], rx[k]
+    ry[k], ry[len(ry)-1] = ry[len(ry)-1], ry[k]
+    // the last bin extends to infinity and is part of the tail
+    hi = append(hi, ry[len(ry)-1])
+    ry = ry[0:len(ry)-1]
+  }
+  if binning.config.nonFinite == ClampNonFiniteToTails && len(ry) > 0 {
+    // find first and last bin
+    kmin, kmax := 0, 0
+    for i := 1; i < len(ry); i++ {
+      if rx[i] < rx[kmin] {
+        kmin = i
+      }
+      if rx[i] > rx[kmax] {
+        kmax = i
+      }
+    }
+    for _, v := range lo {
+      ry[kmin] = binning.Sum(Bin{Y: ry[kmin]}, Bin{Y: v})
+    }
+    for _, v := range hi {
+      ry[kmax] = binning.Sum(Bin{Y: ry[kmax]}, Bin{Y: v})
+    }
+  }
+  if len(y) == 0 {
+    ry = nil
+  }
+  if len(rx) < 3 {
+    return nil, nil, fmt.Errorf("%w: too few finite boundaries", ErrTooFewBoundaries)
+  }
+  return rx, ry, nil
+}
+
+// Apply the non-finite policy to a sample with value x and weight w. The
+// function returns false if the sample should be dropped.
+func (binning *Binning) filterNonFiniteSample(x, w float64) (float64, bool, error) {
+  if (isFinite(x) && !math.IsNaN(w)) || binning.config.nonFinite == IgnoreNonFinite {
+    return x, true, nil
+  }
+  switch binning.config.nonFinite {
+  case RejectNonFinite:
+    return x, false, fmt.Errorf("%w in sample", ErrNonFinite)
+  case ClampNonFiniteToTails:
+    if !math.IsNaN(w) && math.IsInf(x, -1) && binning.First != nil {
+      return binning.First.Lower, true, nil
+    }
+    if !math.IsNaN(w) && math.IsInf(x,  1) && binning.Last  != nil {
+      return binning.Last .Lower, true, nil
+    }
+  }
+  return x, false, nil
+}
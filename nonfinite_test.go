@@ -0,0 +1,55 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestNonFinite1(t *testing.T) {
+
+  x := []float64{0, 1, math.NaN(), 2, math.Inf(-1), 3, 4, math.Inf(1)}
+  y := []float64{1, 2, 3, 4, 5, 6, 7}
+
+  if _, err := New(x, y, BinSum, BinLessSize, WithNonFinitePolicy(RejectNonFinite)); err == nil {
+    t.Error("test failed")
+  }
+  b1, err := New(x, y, BinSum, BinLessSize, WithNonFinitePolicy(DropNonFinite))
+  if err != nil {
+    t.Error(err); return
+  }
+  if s := b1.String(); s != "[0.000000, 1.000000):1 [1.000000, 2.000000):2 [2.000000, 3.000000):4 [3.000000, 4.000000):6" {
+    t.Error("test failed")
+  }
+  b2, err := New(x, y, BinSum, BinLessSize, WithNonFinitePolicy(ClampNonFiniteToTails))
+  if err != nil {
+    t.Error(err); return
+  }
+  if s := b2.String(); s != "[0.000000, 1.000000):6 [1.000000, 2.000000):2 [2.000000, 3.000000):4 [3.000000, 4.000000):13" {
+    t.Error("test failed")
+  }
+  if err := b2.AddSample(math.Inf(1), 1); err != nil || b2.Last.Y != 14 {
+    t.Error("test failed")
+  }
+  if err := b2.AddSample(math.NaN(), 1); err != nil {
+    t.Error("test failed")
+  }
+}
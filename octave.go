@@ -0,0 +1,101 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Frequency band of a fractional-octave filter bank
+type OctaveBand struct {
+  Lower   float64
+  Upper   float64
+  // exact mid-band frequency
+  Center  float64
+  // nominal mid-band frequency (e.g. 31.5 Hz), which equals the exact
+  // frequency for fractions other than octaves and third octaves
+  Nominal float64
+}
+
+// Preferred numbers (R10 series) used for nominal frequencies
+var octaveNominal = [10]float64{1, 1.25, 1.6, 2, 2.5, 3.15, 4, 5, 6.3, 8}
+
+// Generate all 1/b-octave bands (b = 1 for octaves and b = 3 for third
+// octaves) that overlap with the frequency range [lo, hi]. Mid-band
+// frequencies are 1000 G^(k/b) Hz with the base-ten octave ratio
+// G = 10^(3/10) (ANSI S1.11 and IEC 61260), band edges are the mid-band
+// frequencies multiplied by G^(-1/2b) and G^(1/2b).
+func OctaveBands(b int, lo, hi float64) ([]OctaveBand, error) {
+  if b < 1 {
+    return nil, fmt.Errorf("%w: octave fraction must be positive", ErrInvalidArgument)
+  }
+  if !(lo > 0) || !(hi >= lo) || math.IsInf(hi, 0) {
+    return nil, fmt.Errorf("%w: frequency range [%f, %f]", ErrInvalidArgument, lo, hi)
+  }
+  // position of a frequency in units of bands
+  band := func(f float64) float64 {
+    return float64(b)*(math.Log10(f)-3)/0.3
+  }
+  edge := func(k float64) float64 {
+    return math.Pow(10, 3 + 0.3*k/float64(b))
+  }
+  r := []OctaveBand{}
+  for k := math.Floor(band(lo)+0.5); edge(k-0.5) <= hi; k++ {
+    c := edge(k)
+    n := c
+    if b == 1 || b == 3 {
+      // exponent of the R10 series
+      i := int(math.Round(10*math.Log10(c)))
+      n  = octaveNominal[(i%10+10)%10]*math.Pow10(int(math.Floor(float64(i)/10)))
+    }
+    r = append(r, OctaveBand{Lower: edge(k-0.5), Upper: edge(k+0.5), Center: c, Nominal: n})
+  }
+  return r, nil
+}
+
+// Create a binning of spectral data with 1/b-octave bands (see OctaveBands)
+// covering all frequencies f, where the value of each band is the sum of
+// the powers y of all frequencies within the band
+func NewOctaveBinning(f, y []float64, b int, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  if len(f) == 0 || len(f) != len(y) {
+    return nil, ErrLengthMismatch
+  }
+  lo, hi := math.Inf(1), math.Inf(-1)
+  for _, v := range f {
+    lo = math.Min(lo, v)
+    hi = math.Max(hi, v)
+  }
+  bands, err := OctaveBands(b, lo, hi)
+  if err != nil {
+    return nil, err
+  }
+  x := make([]float64, len(bands)+1)
+  for i := range bands {
+    x[i] = bands[i].Lower
+  }
+  x[len(bands)] = bands[len(bands)-1].Upper
+  v := make([]float64, len(bands))
+  for i := range f {
+    j := sort.Search(len(bands), func(j int) bool { return x[j+1] > f[i] })
+    v[j] += y[i]
+  }
+  return New(x, v, sum, less, options...)
+}
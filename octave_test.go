@@ -0,0 +1,65 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestOctave1(t *testing.T) {
+
+  bands, err := OctaveBands(1, 31.5, 16000)
+  if err != nil {
+    t.Error(err); return
+  }
+  nominal := []float64{31.5, 63, 125, 250, 500, 1000, 2000, 4000, 8000, 16000}
+  if len(bands) != len(nominal) {
+    t.Error("test failed"); return
+  }
+  for i := range bands {
+    if bands[i].Nominal != nominal[i] || (i > 0 && bands[i].Lower != bands[i-1].Upper) {
+      t.Error("test failed")
+    }
+  }
+  if bands[5].Center != 1000 {
+    t.Error("test failed")
+  }
+  bands, _ = OctaveBands(3, 900, 1100)
+  if len(bands) != 1 || bands[0].Nominal != 1000 {
+    t.Error("test failed")
+  }
+}
+
+func TestOctave2(t *testing.T) {
+
+  f := []float64{100, 110, 125, 1000, 1010}
+  y := []float64{1, 2, 3, 4, 5}
+
+  binning, err := NewOctaveBinning(f, y, 3, BinSum, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if binning.FindBin(105).Y != 3 || binning.FindBin(125).Y != 3 || binning.FindBin(1000).Y != 9 {
+    t.Error("test failed")
+  }
+  binning.FilterBins(3)
+  if binning.Aggregate(binning.First.Lower, binning.Last.Upper) != 15 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,105 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Additional columns of a one-hot encoding, which follow the columns of all
+// bins in the given order
+type OneHotColumns int
+
+const (
+  // column for non-finite values
+  OneHotMissing OneHotColumns = 1 << iota
+  // column for values below the first bin
+  OneHotUnderflow
+  // column for values above the last bin
+  OneHotOverflow
+)
+
+// Column of x in a one-hot encoding or -1 if x has no column
+func (obj *Discretizer) oneHotColumn(x float64, columns OneHotColumns) int {
+  columns = obj.oneHotColumns(columns)
+  n := obj.Len()
+  if i := obj.index(x); i >= 0 {
+    return i
+  }
+  for _, c := range []OneHotColumns{OneHotMissing, OneHotUnderflow, OneHotOverflow} {
+    if columns & c == 0 {
+      continue
+    }
+    switch {
+    case c == OneHotMissing   && math.IsNaN(x):
+      return n
+    case c == OneHotUnderflow && x <  obj.Boundaries[0]:
+      return n
+    case c == OneHotOverflow  && x >= obj.Boundaries[len(obj.Boundaries)-1]:
+      return n
+    }
+    n++
+  }
+  return -1
+}
+
+// A discretizer with missing bin always has a column for missing values
+func (obj *Discretizer) oneHotColumns(columns OneHotColumns) OneHotColumns {
+  if obj.Missing {
+    columns |= OneHotMissing
+  }
+  return columns
+}
+
+// Number of columns of a one-hot encoding
+func (obj *Discretizer) OneHotWidth(columns OneHotColumns) int {
+  columns = obj.oneHotColumns(columns)
+  n := obj.Len()
+  for _, c := range []OneHotColumns{OneHotMissing, OneHotUnderflow, OneHotOverflow} {
+    if columns & c != 0 {
+      n++
+    }
+  }
+  return n
+}
+
+// Encode each value as a row of indicator features, one for each bin and one
+// for each additional column. Rows of values without a column are zero.
+func (obj *Discretizer) OneHot(data []float64, columns OneHotColumns) [][]float64 {
+  m := obj.OneHotWidth(columns)
+  r := make([][]float64, len(data))
+  v := make([]float64, len(data)*m)
+  for i, x := range data {
+    r[i] = v[i*m:(i+1)*m:(i+1)*m]
+    if j := obj.oneHotColumn(x, columns); j >= 0 {
+      r[i][j] = 1
+    }
+  }
+  return r
+}
+
+// Sparse one-hot encoding, where the column of the non-zero feature is given
+// for each value or -1 if the row is zero
+func (obj *Discretizer) OneHotSparse(data []float64, columns OneHotColumns) []int {
+  r := make([]int, len(data))
+  for i, x := range data {
+    r[i] = obj.oneHotColumn(x, columns)
+  }
+  return r
+}
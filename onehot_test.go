@@ -0,0 +1,45 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestOneHot1(t *testing.T) {
+
+  d := Discretizer{Boundaries: []float64{0, 1, 2}, Centers: []float64{0.5, 1.5}}
+
+  data := []float64{0.5, 1.5, math.NaN(), -1, 3}
+  r := d.OneHot(data, 0)
+  if len(r) != 5 || len(r[0]) != 2 || r[0][0] != 1 || r[1][1] != 1 || r[2][0] != 0 || r[2][1] != 0 {
+    t.Error("test failed")
+  }
+  r = d.OneHot(data, OneHotMissing | OneHotUnderflow | OneHotOverflow)
+  if len(r[0]) != 5 || r[2][2] != 1 || r[3][3] != 1 || r[4][4] != 1 {
+    t.Error("test failed")
+  }
+  if s := d.OneHotSparse(data, OneHotOverflow); s[0] != 0 || s[1] != 1 || s[2] != -1 || s[3] != -1 || s[4] != 2 {
+    t.Error("test failed")
+  }
+  if s := d.OneHotSparse([]float64{math.Inf(1)}, OneHotMissing | OneHotOverflow); s[0] != 3 {
+    t.Error("test failed")
+  }
+}
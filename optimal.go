@@ -0,0 +1,256 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Direction of a monotonicity constraint
+type Monotonicity int
+
+const (
+  // No constraint
+  MonotoneNone Monotonicity = iota
+  // The trend of each bin is at least the trend of its left neighbor
+  MonotoneIncreasing
+  // The trend of each bin is at most the trend of its left neighbor
+  MonotoneDecreasing
+)
+
+// Constraints of an optimal binning. Zero values disable the respective
+// constraint.
+type OptimalConstraints struct {
+  // maximum number of bins
+  MaxBins      int
+  // minimum value of each bin
+  MinValue     float64
+  // statistic of a bin that must be monotone over all bins, e.g. the event
+  // rate of a bin with GroupCounts payload
+  Trend        func(Bin) float64
+  Monotonicity Monotonicity
+}
+
+// Optimal binning problem: Partition a sequence of bins into contiguous
+// groups, such that the sum of the objective over all merged bins is
+// maximal and all constraints are satisfied
+type OptimalProblem struct {
+  // bins in order
+  Bins        []Bin
+  // merge two neighboring bins, where a is the left bin
+  Merge       func(a, b Bin) Bin
+  // objective of a merged bin
+  Objective   func(Bin) float64
+  Constraints OptimalConstraints
+}
+
+// Returns true if the trend b may follow a
+func (c OptimalConstraints) monotone(a, b float64) bool {
+  switch c.Monotonicity {
+  case MonotoneIncreasing: return a <= b
+  case MonotoneDecreasing: return a >= b
+  }
+  return true
+}
+
+// Solver computes the solution of an optimal binning problem, i.e. the
+// indices of the first bin of each group in increasing order, where the
+// first index is zero. Solutions must have at least two groups, since a
+// binning has at least two bins. ErrInfeasible is returned if no solution
+// exists. An
+// external solver, e.g. a MIP solver, can be plugged in by implementing
+// this interface.
+type Solver interface {
+  Solve(p OptimalProblem) ([]int, error)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Exact solver using dynamic programming over the last group of each
+// partial solution, which requires O(k n^3) operations for n bins and at
+// most k groups
+type ExactSolver struct{}
+
+func (ExactSolver) Solve(p OptimalProblem) ([]int, error) {
+  c := p.Constraints
+  n := len(p.Bins)
+  if n == 0 {
+    return nil, fmt.Errorf("%w: no bins given", ErrInvalidArgument)
+  }
+  if p.Merge == nil || p.Objective == nil {
+    return nil, fmt.Errorf("%w: merge and objective functions are required", ErrInvalidArgument)
+  }
+  if c.Monotonicity != MonotoneNone && c.Trend == nil {
+    return nil, fmt.Errorf("%w: monotonicity requires a trend function", ErrInvalidArgument)
+  }
+  m := n
+  if c.MaxBins > 0 && c.MaxBins < n {
+    m = c.MaxBins
+  }
+  // objective and trend of all groups [i, j), where
+  // infeasible groups have an objective of -Inf
+  objective := make([][]float64, n+1)
+  trend     := make([][]float64, n+1)
+  for i := 0; i < n; i++ {
+    objective[i] = make([]float64, n+1)
+    trend    [i] = make([]float64, n+1)
+    bin := p.Bins[i]
+    for j := i+1; j <= n; j++ {
+      if j > i+1 {
+        bin = p.Merge(bin, p.Bins[j-1])
+      }
+      if bin.Y < c.MinValue {
+        objective[i][j] = math.Inf(-1)
+      } else {
+        objective[i][j] = p.Objective(bin)
+      }
+      if c.Trend != nil {
+        trend[i][j] = c.Trend(bin)
+      }
+    }
+  }
+  // best[k][i][j] is the largest objective of a partition of bins
+  // [0, j) into k+1 groups with last group [i, j), and prev[k][i][j]
+  // the first bin of the previous group
+  best := make([][][]float64, m)
+  prev := make([][][]int32, m)
+  for k := 0; k < m; k++ {
+    best[k] = make([][]float64, n)
+    prev[k] = make([][]int32, n)
+    for i := 0; i < n; i++ {
+      best[k][i] = make([]float64, n+1)
+      prev[k][i] = make([]int32, n+1)
+      for j := range best[k][i] {
+        best[k][i][j] = math.Inf(-1)
+      }
+    }
+  }
+  for j := 1; j <= n; j++ {
+    best[0][0][j] = objective[0][j]
+  }
+  for k := 1; k < m; k++ {
+    for i := k; i < n; i++ {
+      for j := i+1; j <= n; j++ {
+        if math.IsInf(objective[i][j], -1) {
+          continue
+        }
+        for l := k-1; l < i; l++ {
+          if math.IsInf(best[k-1][l][i], -1) || !c.monotone(trend[l][i], trend[i][j]) {
+            continue
+          }
+          if v := best[k-1][l][i] + objective[i][j]; v > best[k][i][j] {
+            best[k][i][j] = v
+            prev[k][i][j] = int32(l)
+          }
+        }
+      }
+    }
+  }
+  // find best solution with at least two groups, preferring fewer groups
+  bk, bi := -1, -1
+  for k := 1; k < m; k++ {
+    for i := 0; i < n; i++ {
+      if v := best[k][i][n]; !math.IsInf(v, -1) && (bk < 0 || v > best[bk][bi][n]) {
+        bk, bi = k, i
+      }
+    }
+  }
+  if bk < 0 {
+    return nil, ErrInfeasible
+  }
+  r := make([]int, bk+1)
+  for k, i, j := bk, bi, n; k >= 0; k-- {
+    r[k] = i
+    i, j = int(prev[k][i][j]), i
+  }
+  return r, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Merge bins such that the sum of the objective over all bins is maximal
+// subject to constraints c. The problem is solved exactly by solver, or by
+// ExactSolver if solver is nil. Bins are merged with the sum function or
+// aggregator of the binning. The binning is reset (see Reset) and all
+// pointers to bins become invalid.
+func (binning *Binning) FilterBinsOptimal(objective func(Bin) float64, c OptimalConstraints, solver Solver) error {
+  if binning.config.circular {
+    return fmt.Errorf("%w: optimal binning of circular binnings is not supported", ErrInvalidArgument)
+  }
+  if solver == nil {
+    solver = ExactSolver{}
+  }
+  p := OptimalProblem{Objective: objective, Constraints: c, Merge: binning.merge}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    p.Bins = append(p.Bins, *at)
+  }
+  r, err := solver.Solve(p)
+  if err != nil {
+    return err
+  }
+  return binning.resetGroups(p.Bins, r)
+}
+
+// Merge two neighboring bins with the sum function or aggregator, where a
+// is the left bin
+func (binning *Binning) merge(a, b Bin) Bin {
+  binning.absorb(&a, &b, true)
+  a.Upper = b.Upper
+  return a
+}
+
+// Reset the binning to groups of contiguous bins, where starts are the
+// indices of the first bin of each group
+func (binning *Binning) resetGroups(bins []Bin, starts []int) error {
+  if len(starts) < 2 {
+    return fmt.Errorf("%w: at least two groups are required", ErrInfeasible)
+  }
+  x    := make([]float64, len(starts)+1)
+  y    := make([]float64, len(starts))
+  data := make([]interface{}, len(starts))
+  for k := range starts {
+    j := len(bins)
+    if k+1 < len(starts) {
+      j = starts[k+1]
+    }
+    if starts[k] >= j || (k == 0 && starts[k] != 0) {
+      return fmt.Errorf("%w: invalid groups `%v'", ErrInvalidArgument, starts)
+    }
+    bin := bins[starts[k]]
+    for i := starts[k]+1; i < j; i++ {
+      bin = binning.merge(bin, bins[i])
+    }
+    x   [k] = bin.Lower
+    y   [k] = bin.Y
+    data[k] = bin.Data
+  }
+  x[len(starts)] = bins[len(bins)-1].Upper
+  if err := binning.Reset(x, y); err != nil {
+    return err
+  }
+  // the less function may depend on payloads
+  for i := range data {
+    if data[i] != nil {
+      binning.Bins[i].Data = data[i]
+      binning.modified(&binning.Bins[i])
+    }
+  }
+  return binning.Update()
+}
@@ -0,0 +1,107 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "errors"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+type fixedSolver []int
+
+func (s fixedSolver) Solve(p OptimalProblem) ([]int, error) {
+  return s, nil
+}
+
+func TestOptimal1(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4, 5, 6}
+  y := []float64{1, 1, 1, 1, 1, 1}
+
+  binning, _ := New(x, y, BinSum, BinLessY)
+  // prefer bins with a value of 2
+  objective := func(bin Bin) float64 { return -(bin.Y-2)*(bin.Y-2) }
+
+  if err := binning.FilterBinsOptimal(objective, OptimalConstraints{MaxBins: 4}, nil); err != nil {
+    t.Error(err); return
+  }
+  if x := binning.AppendBoundaries(nil); len(x) != 4 || x[1] != 2 || x[2] != 4 {
+    t.Error("test failed")
+  }
+  binning, _ = New(x, y, BinSum, BinLessY)
+  if err := binning.FilterBinsOptimal(objective, OptimalConstraints{MinValue: 7}, nil); !errors.Is(err, ErrInfeasible) {
+    t.Error("test failed")
+  }
+  binning, _ = New(x, y, BinSum, BinLessY)
+  if err := binning.FilterBinsOptimal(objective, OptimalConstraints{}, fixedSolver{0, 1}); err != nil {
+    t.Error(err); return
+  }
+  if v := binning.AppendValues(nil); len(v) != 2 || v[1] != 5 {
+    t.Error("test failed")
+  }
+}
+
+func TestOptimal2(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4, 5}
+  y := []float64{1, 4, 2, 3, 5}
+
+  binning, _ := New(x, y, BinSum, BinLessY)
+  // maximize the number of bins subject to increasing densities
+  c := OptimalConstraints{
+    Trend       : func(bin Bin) float64 { return bin.Y/bin.Size() },
+    Monotonicity: MonotoneIncreasing }
+  if err := binning.FilterBinsOptimal(func(Bin) float64 { return 1 }, c, nil); err != nil {
+    t.Error(err); return
+  }
+  if x := binning.AppendBoundaries(nil); len(x) != 5 || x[1] != 1 || x[2] != 3 || x[3] != 4 {
+    t.Error("test failed")
+  }
+  if err := binning.Validate(); err != nil {
+    t.Error(err)
+  }
+}
+
+func TestOptimal3(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4}
+  y := []float64{1, 2, 3, 4}
+
+  // additive objective, all partitions have the same objective
+  binning, _ := New(x, y, BinSum, BinLessY)
+  if err := binning.FilterBinsOptimal(func(bin Bin) float64 { return bin.Y }, OptimalConstraints{}, nil); err != nil {
+    t.Error(err); return
+  }
+  if v := binning.AppendValues(nil); len(v) != 2 || v[0] != 1 || v[1] != 9 {
+    t.Error("test failed")
+  }
+  binning, _ = New(x, y, BinSum, BinLessY)
+  if err := binning.FilterBinsOptimal(func(bin Bin) float64 { return bin.Y }, OptimalConstraints{MaxBins: 1}, nil); !errors.Is(err, ErrInfeasible) {
+    t.Error("test failed")
+  }
+  if err := binning.FilterBinsOptimal(func(bin Bin) float64 { return bin.Y }, OptimalConstraints{}, fixedSolver{0}); !errors.Is(err, ErrInfeasible) {
+    t.Error("test failed")
+  }
+  if len(binning.Bins) != 4 {
+    t.Error("test failed")
+  }
+  if err := binning.FilterBinsBalanced(1, 0.5, false); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+}
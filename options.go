@@ -0,0 +1,80 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+/* -------------------------------------------------------------------------- */
+
+import "sync"
+
+/* -------------------------------------------------------------------------- */
+
+// Option of a binning, which can be passed to New
+type Option func(*config)
+
+type config struct {
+  parallelism int
+  arena      *Arena
+  pool       *sync.Pool
+  key         func(Bin) float64
+  progress    func(done, total int)
+  ties        TieBreaking
+  merge       MergeRule
+  nonFinite   NonFinitePolicy
+  duplicates  DuplicatePolicy
+  logger      Logger
+  epsilon     float64
+  circular    bool
+  undo        int
+  decay       float64
+  yfunc       YFunc
+  aggregator  Aggregator
+  selfCheck   bool
+  rangeIndex  bool
+  prefixIndex bool
+  missing     bool
+  trace       bool
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Use n goroutines for constructing a binning. The less function must be
+// safe for concurrent use if n is greater than one.
+func WithParallelism(n int) Option {
+  return func(c *config) {
+    c.parallelism = n
+  }
+}
+
+// Call f periodically during construction and filtering of bins. During
+// construction, done and total refer to the number of construction steps,
+// during filtering to the number of merges.
+func WithProgress(f func(done, total int)) Option {
+  return func(c *config) {
+    c.progress = f
+  }
+}
+
+// Number of steps reported during construction
+const constructionSteps = 4
+
+func (binning *Binning) progress(done, total int) {
+  if binning.config.progress != nil {
+    binning.config.progress(done, total)
+  }
+}
@@ -0,0 +1,45 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestProgress1(t *testing.T) {
+
+  x := make([]float64, 10001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + float64(i % 7 + 1)
+  }
+  calls := [][2]int{}
+  binning, _ := New(x, nil, BinSum, BinLessSize, WithProgress(func(done, total int) {
+    calls = append(calls, [2]int{done, total})
+  }))
+  if len(calls) != 4 || calls[3] != [2]int{4, 4} {
+    t.Error("test failed")
+  }
+  calls = nil
+
+  binning.FilterBins(10)
+  if len(calls) != 11 || calls[0] != [2]int{0, 9990} || calls[10] != [2]int{9990, 9990} {
+    t.Error("test failed")
+  }
+}
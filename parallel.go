@@ -0,0 +1,239 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "context"
+import "fmt"
+import "sort"
+import "sync"
+
+/* -------------------------------------------------------------------------- */
+
+// Call f(i) for i = 0, ..., n-1 using p goroutines
+func parallelFor(n, p int, f func(i int)) {
+  if p <= 1 {
+    for i := 0; i < n; i++ {
+      f(i)
+    }
+    return
+  }
+  var wg sync.WaitGroup
+  for k := 0; k < p; k++ {
+    wg.Add(1)
+    go func(lo, hi int) {
+      defer wg.Done()
+      for i := lo; i < hi; i++ {
+        f(i)
+      }
+    }(k*n/p, (k+1)*n/p)
+  }
+  wg.Wait()
+}
+
+// Split [0, n) into p chunks, call f for each chunk concurrently and merge
+// neighboring chunks pairwise in parallel
+func parallelMergeSort(n, p int, sortChunk func(lo, hi int), merge func(lo, mid, hi int)) {
+  chunks := make([]int, p+1)
+  for k := 0; k <= p; k++ {
+    chunks[k] = k*n/p
+  }
+  var wg sync.WaitGroup
+  for k := 0; k < p; k++ {
+    wg.Add(1)
+    go func(lo, hi int) {
+      defer wg.Done()
+      sortChunk(lo, hi)
+    }(chunks[k], chunks[k+1])
+  }
+  wg.Wait()
+  for len(chunks) > 2 {
+    c := []int{0}
+    for k := 0; k < len(chunks)-1; k += 2 {
+      if k+2 < len(chunks) {
+        wg.Add(1)
+        go func(lo, mid, hi int) {
+          defer wg.Done()
+          merge(lo, mid, hi)
+        }(chunks[k], chunks[k+1], chunks[k+2])
+        c = append(c, chunks[k+2])
+      } else {
+        c = append(c, chunks[k+1])
+      }
+    }
+    wg.Wait()
+    chunks = c
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Sort bins by their lower boundaries using p goroutines
+func parallelSortBins(bins binList, p int) {
+  tmp := make(binList, len(bins))
+  parallelMergeSort(len(bins), p,
+    func(lo, hi int) {
+      sort.Sort(bins[lo:hi])
+    },
+    func(lo, mid, hi int) {
+      i, j := lo, mid
+      for t := lo; t < hi; t++ {
+        if j >= hi || (i < mid && !bins.Less(j, i)) {
+          tmp[t] = bins[i]; i++
+        } else {
+          tmp[t] = bins[j]; j++
+        }
+      }
+      copy(bins[lo:hi], tmp[lo:hi])
+    })
+}
+
+// Sort bins by the less function of the binning using p goroutines
+func parallelSort(obj binListSorted, p int) {
+  tmp := make([]int32, len(obj.bins))
+  parallelMergeSort(len(obj.bins), p,
+    func(lo, hi int) {
+      sort.Sort(binListSorted{obj.binning, obj.bins[lo:hi]})
+    },
+    func(lo, mid, hi int) {
+      i, j := lo, mid
+      for t := lo; t < hi; t++ {
+        if j >= hi || (i < mid && !obj.Less(j, i)) {
+          tmp[t] = obj.bins[i]; i++
+        } else {
+          tmp[t] = obj.bins[j]; j++
+        }
+      }
+      copy(obj.bins[lo:hi], tmp[lo:hi])
+    })
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Same as FilterBins, but bins are merged concurrently by p goroutines. The
+// binning is partitioned into p segments of contiguous bins, each segment
+// is reduced independently to twice its share of the n bins, and finally
+// the remaining merges, including those across segment boundaries, are
+// performed sequentially. The result is in general not identical to
+// FilterBins. Merges within segments are recorded (see WithUndo and
+// WithMergeTrace), logged and delivered to subscribers once all segments
+// are filtered. The less function must be safe for concurrent use. Circular
+// binnings are filtered sequentially.
+func (binning *Binning) FilterBinsParallel(n, p int) error {
+  return binning.FilterBinsParallelContext(context.Background(), n, p)
+}
+
+// Same as FilterBinsParallel, but stops merging bins when the context is
+// canceled (see FilterBinsContext)
+func (binning *Binning) FilterBinsParallelContext(ctx context.Context, n, p int) error {
+  if p < 1 {
+    return fmt.Errorf("%w: number of goroutines must be positive", ErrInvalidArgument)
+  }
+  k := len(binning.Bins) - binning.deleted
+  if n < 1 || k <= 2*n || k < 2*p || p == 1 || binning.period > 0 {
+    return binning.FilterBinsContext(ctx, n)
+  }
+  // copy active bins, values are not evaluated (see WithLazyValues)
+  bins := make([]Bin, 0, k)
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    bin := *at
+    bin.dirty = false
+    bins = append(bins, bin)
+  }
+  // segments are filtered without options that
+  // refer to the state of the whole binning
+  config := binning.config
+  config.arena       = nil
+  config.pool        = nil
+  config.progress    = nil
+  config.logger      = nil
+  config.parallelism = 0
+  // merges are logged and delivered to subscribers
+  // after all segments are filtered
+  notify   := binning.config.logger != nil || len(binning.subscribers) > 0
+  segments := make([]*Binning, p)
+  events   := make([][]Event, p)
+  errs     := make([]error, p)
+  var wg sync.WaitGroup
+  for s := 0; s < p; s++ {
+    wg.Add(1)
+    go func(s, lo, hi int) {
+      defer wg.Done()
+      segment := &Binning{Sum: binning.Sum, lessFunc: binning.lessFunc, config: config}
+      segment.Less = segment.lessWrapper
+      segment.allocate(hi-lo)
+      copy(segment.Bins, bins[lo:hi])
+      segment.link()
+      if binning.trace != nil {
+        segment.trace = &mergeTrace{boundaries: binning.trace.boundaries}
+      }
+      if notify {
+        segment.Subscribe(func(event Event) {
+          if event.Kind == EventMerge {
+            events[s] = append(events[s], event)
+          }
+        })
+      }
+      m := 2*n*(hi-lo)/k
+      if m < 1 {
+        m = 1
+      }
+      errs[s]     = segment.FilterBinsContext(ctx, m)
+      segments[s] = segment
+    }(s, s*k/p, (s+1)*k/p)
+  }
+  wg.Wait()
+  for s := range errs {
+    if errs[s] != nil {
+      return errs[s]
+    }
+  }
+  // stitch segments, keeping the state of all resulting bins
+  m := 0
+  for _, segment := range segments {
+    m += len(segment.Bins)
+  }
+  binning.allocate(m)
+  binning.indexInvalidate()
+  binning.dirty   = binning.dirty[0:0]
+  binning.deleted = 0
+  m = 0
+  for _, segment := range segments {
+    m += copy(binning.Bins[m:], segment.Bins)
+  }
+  binning.link()
+  // record merges of all segments, which are independent of each other
+  for s, segment := range segments {
+    if binning.config.undo > 0 {
+      binning.undo = append(binning.undo, segment.undo...)
+      if n := binning.config.undo; len(binning.undo) > n {
+        binning.undo = binning.undo[len(binning.undo)-n:]
+      }
+    }
+    if binning.trace != nil {
+      binning.trace.merges = append(binning.trace.merges, segment.trace.merges...)
+    }
+    for i := range events[s] {
+      binning.notifyMerge(&events[s][i].Deleted, &events[s][i].Bin)
+    }
+  }
+  if err := binning.check("parallel filtering"); err != nil {
+    return err
+  }
+  return binning.FilterBinsContext(ctx, n)
+}
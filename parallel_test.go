@@ -0,0 +1,151 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "bytes"
+import   "math/rand"
+import   "reflect"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestParallel1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 10001)
+  for i := 0; i < len(x); i++ {
+    x[i] = r.Float64()
+  }
+  b1, _ := New(x, nil, BinSum, BinLessSize)
+  b2, _ := New(x, nil, BinSum, BinLessSize, WithParallelism(3))
+
+  for i := 0; i < len(b1.Bins); i++ {
+    if b1.Bins[i].Lower != b2.Bins[i].Lower || b1.Bins[i].Upper != b2.Bins[i].Upper {
+      t.Error("test failed"); break
+    }
+  }
+  for at1, at2 := b1.Smallest, b2.Smallest; at1 != nil || at2 != nil; at1, at2 = b1.Larger(at1), b2.Larger(at2) {
+    if at1 == nil || at2 == nil || at1.index != at2.index {
+      t.Error("test failed"); break
+    }
+  }
+  checkSkipList(t, b2)
+}
+
+func TestParallel2(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 10001)
+  y := make([]float64, 10000)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(1 + r.Intn(10))
+  }
+  b1, _ := New(x, y, BinSum, BinLessY)
+  b2, _ := New(x, y, BinSum, BinLessY)
+  b1.FilterBins(20)
+  if err := b2.FilterBinsParallel(20, 4); err != nil {
+    t.Error(err); return
+  }
+  if len(b2.Bins) != 20 || b2.Aggregate(x[0], x[10000]) != b1.Aggregate(x[0], x[10000]) {
+    t.Error("test failed")
+  }
+  if err := b2.Validate(); err != nil {
+    t.Error(err)
+  }
+  // bins have approximately equal counts
+  for at := b2.First; at != nil; at = b2.Next(at) {
+    if at.Y < 0.5*b1.Smallest.Y || at.Y > 2*b1.Largest.Y {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestParallel3(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 10001)
+  y := make([]float64, 10000)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(1 + r.Intn(10))
+  }
+  options := []Option{WithAggregator(StatsAggregator{}), WithUndo(100000), WithMergeTrace()}
+  b1, _ := New(x, y, nil, BinLessY, options...)
+  b2, _ := New(x, y, nil, BinLessY, options...)
+  merges := 0
+  b2.Subscribe(func(event Event) {
+    if event.Kind == EventMerge {
+      merges++
+    }
+  })
+  b1.FilterBins(20)
+  if err := b2.FilterBinsParallel(20, 4); err != nil {
+    t.Error(err); return
+  }
+  if merges != 10000-20 || b2.UndoLen() != 10000-20 {
+    t.Error("test failed")
+  }
+  // merge counts and payloads are consistent with the original bins
+  // and their totals are the same as for FilterBins
+  total := [2]BinStats{}
+  count := [2]int{}
+  for k, b := range []*Binning{b1, b2} {
+    for at := b.First; at != nil; at = b.Next(at) {
+      s := at.Data.(BinStats)
+      i := 0
+      for x[i] < at.Lower {
+        i++
+      }
+      j := i
+      for x[j] < at.Upper {
+        j++
+      }
+      if at.MergedCount() != j-i-1 || int(s.Count) != j-i || s.Sum != at.Y {
+        t.Error("test failed")
+      }
+      count[k]       += at.MergedCount()
+      total[k].Count += s.Count
+      total[k].Sum   += s.Sum
+    }
+  }
+  if count[0] != count[1] || total[0] != total[1] {
+    t.Error("test failed")
+  }
+  // the merge trace reproduces the result
+  buffer := bytes.Buffer{}
+  if err := b2.ExportTrace(&buffer); err != nil {
+    t.Error(err); return
+  }
+  b3, _ := New(x, y, nil, BinLessY, options...)
+  if err := b3.ReplayTrace(&buffer); err != nil {
+    t.Error(err); return
+  }
+  if !reflect.DeepEqual(b2.AppendBoundaries(nil), b3.AppendBoundaries(nil)) {
+    t.Error("test failed")
+  }
+  // all merges can be reversed
+  if err := b2.Undo(b2.UndoLen()); err != nil {
+    t.Error(err); return
+  }
+  if !reflect.DeepEqual(b2.AppendBoundaries(nil), x) || !reflect.DeepEqual(b2.AppendValues(nil), y) {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,284 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "bytes"
+import "context"
+import "fmt"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Node of a balanced tree over the positions of the initial bins. Merged
+// bins remain in the tree as inactive nodes, so that the shape of the tree
+// never changes.
+type persistentNode struct {
+  left   *persistentNode
+  right  *persistentNode
+  bin     Bin
+  active  bool
+  // number of active bins in the subtree
+  count   int
+}
+
+func (node *persistentNode) size() int {
+  if node == nil {
+    return 0
+  }
+  return node.count
+}
+
+// Persistent is an immutable version of a binning. Versions created by
+// FilterBinsPersistent share all unmodified parts, so that each merge
+// requires only O(log n) additional memory. Access to the i-th bin requires
+// O(log n) operations.
+type Persistent struct {
+  root    *persistentNode
+  // number of initial bins, which determines the shape of the tree
+  n        int
+  epsilon  float64
+}
+
+// Create a persistent version of the binning
+func (binning *Binning) Persistent() *Persistent {
+  bins := []Bin{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    bins = append(bins, Bin{Y: at.Y, Lower: at.Lower, Upper: at.Upper, merged: at.merged})
+  }
+  var build func(lo, hi int) *persistentNode
+  build = func(lo, hi int) *persistentNode {
+    if lo >= hi {
+      return nil
+    }
+    m := lo + (hi-lo)/2
+    return &persistentNode{
+      left  : build(lo, m),
+      right : build(m+1, hi),
+      bin   : bins[m],
+      active: true,
+      count : hi-lo }
+  }
+  return &Persistent{root: build(0, len(bins)), n: len(bins), epsilon: binning.config.epsilon}
+}
+
+// Return a new version where the initial bin at position i is replaced by
+// bin or deactivated
+func (obj *Persistent) set(i int, bin Bin, active bool) *Persistent {
+  var set func(node *persistentNode, lo, hi int) *persistentNode
+  set = func(node *persistentNode, lo, hi int) *persistentNode {
+    r := *node
+    switch m := lo + (hi-lo)/2; {
+    case i < m:
+      r.left  = set(node.left, lo, m)
+    case i > m:
+      r.right = set(node.right, m+1, hi)
+    default:
+      r.bin    = bin
+      r.active = active
+    }
+    r.count = r.left.size() + r.right.size()
+    if r.active {
+      r.count++
+    }
+    return &r
+  }
+  return &Persistent{root: set(obj.root, 0, obj.n), n: obj.n, epsilon: obj.epsilon}
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Same as FilterBins, but returns all intermediate binnings, i.e. the i-th
+// version is the binning after i merges. Modified bins are reinserted (see
+// Update) before merging.
+func (binning *Binning) FilterBinsPersistent(n int) ([]*Persistent, error) {
+  return binning.FilterBinsPersistentContext(context.Background(), n)
+}
+
+// Same as FilterBinsPersistent, but stops merging bins when the context is
+// canceled (see FilterBinsContext)
+func (binning *Binning) FilterBinsPersistentContext(ctx context.Context, n int) ([]*Persistent, error) {
+  r := []*Persistent{}
+  err := binning.filterBinsTrace(ctx, n, "FilterBinsPersistent", func(s Snapshot) {
+    r = append(r, s.Version)
+  })
+  return r, err
+}
+
+/* -------------------------------------------------------------------------- */
+
+// State of a binning after a merge step of FilterBinsTrace
+type Snapshot struct {
+  // number of merges
+  Step    int
+  // deleted bin and the bin it was merged into, zero for the initial state
+  Deleted Bin
+  Result  Bin
+  // binning after this step
+  Version *Persistent
+}
+
+// Same as FilterBins, but returns the initial binning and the binning after
+// each merge together with the merged bins, e.g. for animating how the final
+// bins emerged. Modified bins are reinserted (see Update) before merging.
+func (binning *Binning) FilterBinsTrace(n int) ([]Snapshot, error) {
+  r := []Snapshot{}
+  err := binning.FilterBinsTraceFunc(n, func(s Snapshot) {
+    r = append(r, s)
+  })
+  return r, err
+}
+
+// Same as FilterBinsTrace, but calls f for each snapshot instead of
+// collecting all snapshots
+func (binning *Binning) FilterBinsTraceFunc(n int, f func(Snapshot)) error {
+  return binning.filterBinsTrace(context.Background(), n, "FilterBinsTrace", f)
+}
+
+func (binning *Binning) filterBinsTrace(ctx context.Context, n int, method string, f func(Snapshot)) error {
+  // reinsert modified bins, which are not in the sorted list
+  if err := binning.Update(); err != nil {
+    return err
+  }
+  version := binning.Persistent()
+  f(Snapshot{Version: version})
+  k := len(binning.Bins)
+  m := k - n
+  for i := 0; i < m; i++ {
+    if i % checkInterval == 0 {
+      if ctx.Err() != nil {
+        break
+      }
+      binning.progress(i, m)
+    }
+    if binning.Smallest == nil {
+      break
+    }
+    j := int(binning.Smallest.index)
+    d := *binning.Smallest
+    bin, err := binning.Delete(binning.Smallest)
+    if err != nil {
+      return err
+    }
+    result := Bin{Y: bin.Y, Lower: bin.Lower, Upper: bin.Upper, merged: bin.merged}
+    version = version.set(j, Bin{}, false)
+    version = version.set(int(bin.index), result, true)
+    f(Snapshot{Step: i+1, Deleted: Bin{Y: d.Y, Lower: d.Lower, Upper: d.Upper, merged: d.merged}, Result: result, Version: version})
+  }
+  binning.Compact()
+  binning.log("filter", "method", method, "bins", len(binning.Bins), "merges", k-len(binning.Bins))
+  binning.emit(Event{Kind: EventFilter, Bins: len(binning.Bins)})
+  if ctx.Err() == nil && m > 0 {
+    binning.progress(m, m)
+  }
+  return ctx.Err()
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Number of bins
+func (obj *Persistent) Len() int {
+  return obj.root.size()
+}
+
+// Returns the i-th bin
+func (obj *Persistent) Bin(i int) Bin {
+  node := obj.root
+  for node != nil {
+    switch l := node.left.size(); {
+    case i < l:
+      node = node.left
+    case i == l && node.active:
+      return node.bin
+    default:
+      i -= l
+      if node.active {
+        i--
+      }
+      node = node.right
+    }
+  }
+  panic("index out of range")
+}
+
+// Call f for all bins in order
+func (obj *Persistent) walk(f func(Bin)) {
+  var walk func(node *persistentNode)
+  walk = func(node *persistentNode) {
+    if node.size() == 0 {
+      return
+    }
+    walk(node.left)
+    if node.active {
+      f(node.bin)
+    }
+    walk(node.right)
+  }
+  walk(obj.root)
+}
+
+// Returns all boundaries
+func (obj *Persistent) Boundaries() []float64 {
+  r := []float64{}
+  obj.walk(func(bin Bin) {
+    r = append(r, bin.Lower)
+  })
+  if n := obj.Len(); n > 0 {
+    r = append(r, obj.Bin(n-1).Upper)
+  }
+  return r
+}
+
+// Returns all values
+func (obj *Persistent) Values() []float64 {
+  r := []float64{}
+  obj.walk(func(bin Bin) {
+    r = append(r, bin.Y)
+  })
+  return r
+}
+
+// Returns the index of the bin containing x or -1 if x is out of range. The
+// search requires O(log^2 n) operations.
+func (obj *Persistent) FindBin(x float64) int {
+  n := obj.Len()
+  if n > 0 && x + obj.epsilon < obj.Bin(n-1).Upper {
+    x += obj.epsilon
+  }
+  i := sort.Search(n, func(i int) bool { return obj.Bin(i).Upper > x })
+  if i < n && obj.Bin(i).Lower <= x {
+    return i
+  }
+  return -1
+}
+
+// Convert this version to a flat immutable snapshot
+func (obj *Persistent) Frozen() *Frozen {
+  return newFrozen(obj.Boundaries(), obj.Values(), obj.epsilon)
+}
+
+func (obj *Persistent) String() string {
+  var buffer bytes.Buffer
+  obj.walk(func(bin Bin) {
+    if buffer.Len() > 0 {
+      fmt.Fprintf(&buffer, " ")
+    }
+    fmt.Fprintf(&buffer, "%v", bin)
+  })
+  return buffer.String()
+}
@@ -0,0 +1,97 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestPersistent1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 101)
+  y := make([]float64, 100)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(r.Intn(10))
+  }
+  binning, _ := New(x, y, BinSum, BinLessY)
+  versions, err := binning.FilterBinsPersistent(5)
+  if err != nil || len(versions) != 96 {
+    t.Error("test failed"); return
+  }
+  if versions[95].String() != binning.String() {
+    t.Error("test failed")
+  }
+  for _, k := range []int{0, 10, 50, 94} {
+    expected, _ := New(x, y, BinSum, BinLessY)
+    expected.FilterBins(100-k)
+    if versions[k].Len() != 100-k || versions[k].Frozen().String() != expected.Frozen().String() {
+      t.Error("test failed")
+    }
+    if i := versions[k].FindBin(x[50]); versions[k].Bin(i).String() != expected.Frozen().Bin(expected.Frozen().FindBin(x[50])).String() {
+      t.Error("test failed")
+    }
+  }
+  if versions[0].FindBin(-1) != -1 {
+    t.Error("test failed")
+  }
+}
+
+func TestPersistentTrace1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 3, 4, 5}, []float64{3, 1, 4, 2, 5}, BinSum, BinLessY)
+
+  snapshots, err := binning.FilterBinsTrace(2)
+  if err != nil {
+    t.Error(err); return
+  }
+  if len(snapshots) != 4 || snapshots[0].Step != 0 || snapshots[0].Version.Len() != 5 {
+    t.Error("test failed"); return
+  }
+  // the bin [1, 2) with value 1 is merged first with its smaller neighbor
+  if s := snapshots[1]; s.Step != 1 || s.Deleted.Lower != 1 || s.Result.Lower != 0 || s.Result.Y != 4 || s.Version.Len() != 4 {
+    t.Error("test failed")
+  }
+  last := snapshots[len(snapshots)-1].Version.Boundaries()
+  if x := binning.AppendBoundaries(nil); len(x) != len(last) || x[1] != last[1] {
+    t.Error("test failed")
+  }
+}
+
+func TestPersistentTrace2(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 3}, []float64{3, 1, 4}, BinSum, BinLessY)
+
+  // remove all bins from the sorted list
+  for i := range binning.Bins {
+    binning.Bins[i].Y = float64(10-i)
+    if err := binning.Modified(&binning.Bins[i]); err != nil {
+      t.Error(err); return
+    }
+  }
+  snapshots, err := binning.FilterBinsTrace(2)
+  if err != nil {
+    t.Error(err); return
+  }
+  if len(snapshots) != 2 || snapshots[1].Deleted.Lower != 2 || snapshots[1].Result.Y != 17 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,121 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "sync"
+
+/* -------------------------------------------------------------------------- */
+
+// Arena is a preallocated block of memory from which bins of many binnings
+// are allocated. Binnings that require more memory than available allocate
+// from the heap.
+type Arena struct {
+  bins  binList
+  order []int32
+  n     int
+  // release memory of memory-mapped arenas
+  unmap func() error
+}
+
+func NewArena(n int) *Arena {
+  return &Arena{bins: make(binList, n), order: make([]int32, n)}
+}
+
+// Make the whole memory of the arena available again. All binnings
+// allocated from the arena become invalid.
+func (arena *Arena) Reset() {
+  arena.n = 0
+}
+
+func (arena *Arena) allocate(n int) (binList, []int32) {
+  if arena.n+n > len(arena.bins) {
+    return nil, nil
+  }
+  i, j := arena.n, arena.n+n
+  arena.n = j
+  return arena.bins[i:j:j], arena.order[i:j:j]
+}
+
+/* -------------------------------------------------------------------------- */
+
+type storage struct {
+  bins  binList
+  links []skipLink
+  order []int32
+}
+
+// Allocate bins from the given arena. Compact (and hence FilterBins) moves
+// the remaining bins to the heap.
+func WithArena(arena *Arena) Option {
+  return func(c *config) {
+    c.arena = arena
+  }
+}
+
+// Take memory for bins from a pool, memory is returned to the pool by
+// calling the Release method of a binning
+func WithPool(pool *sync.Pool) Option {
+  return func(c *config) {
+    c.pool = pool
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (binning *Binning) allocate(n int) {
+  if binning.Bins == nil && binning.config.pool != nil {
+    if s, ok := binning.config.pool.Get().(*storage); ok {
+      binning.Bins      = s.bins
+      binning.skipLinks = s.links
+      binning.order     = s.order
+      binning.arena     = false
+    }
+  }
+  if cap(binning.Bins) < n || cap(binning.order) < n {
+    binning.Bins, binning.order = nil, nil
+    if arena := binning.config.arena; arena != nil {
+      binning.Bins, binning.order = arena.allocate(n)
+    }
+    binning.arena = binning.Bins != nil
+    if binning.Bins == nil {
+      binning.Bins  = make(binList, n)
+      binning.order = make([]int32, n)
+    }
+  }
+  binning.Bins  = binning.Bins [0:n]
+  binning.order = binning.order[0:n]
+}
+
+// Return the memory of the binning to the pool given by WithPool. The
+// binning must not be used afterwards, except for calling Reset.
+func (binning *Binning) Release() {
+  if pool := binning.config.pool; pool != nil && binning.Bins != nil && !binning.arena {
+    pool.Put(&storage{binning.Bins[0:0], binning.skipLinks[0:0], binning.order[0:0]})
+  }
+  binning.Bins      = nil
+  binning.skipLinks = nil
+  binning.skipSpare = nil
+  binning.skipHead  = nil
+  binning.order     = nil
+  binning.First     = nil
+  binning.Last      = nil
+  binning.Smallest  = nil
+  binning.Largest   = nil
+  binning.arena     = false
+}
@@ -0,0 +1,148 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math/rand"
+import   "sync"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestPool1(t *testing.T) {
+
+  x := []float64{-100,-99,1,2,3,6,8,19,21,120,300,350,355,380}
+  y := []float64{1,2,3,4,5,6,7,8,9,10,11,12,13}
+
+  binning, _ := New(x, y, BinSum, BinLessSize)
+  p := &binning.Bins[0]
+
+  binning.Reset(x[0:10], y[0:9])
+  if p != &binning.Bins[0] || len(binning.Bins) != 9 {
+    t.Error("test failed")
+  }
+  if binning.Largest.Lower != -99 || binning.Last.Upper != 120 {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+}
+
+func TestPool2(t *testing.T) {
+
+  x := []float64{-100,-99,1,2,3,6,8,19,21,120,300,350,355,380}
+  y := []float64{1,2,3,4,5,6,7,8,9,10,11,12,13}
+
+  arena := NewArena(20)
+  b1, _ := New(x, y, BinSum, BinLessSize, WithArena(arena))
+  b2, _ := New(x, y, BinSum, BinLessSize, WithArena(arena))
+
+  if &b1.Bins[0] != &arena.bins[0] {
+    t.Error("test failed")
+  }
+  if arena.n != 13 || len(b2.Bins) != 13 {
+    t.Error("test failed")
+  }
+  arena.Reset()
+  b2.Release()
+  b2, _ = New(x, y, BinSum, BinLessSize, WithArena(arena))
+  if &b2.Bins[0] != &arena.bins[0] {
+    t.Error("test failed")
+  }
+}
+
+func TestPool3(t *testing.T) {
+
+  x := []float64{-100,-99,1,2,3,6,8,19,21,120,300,350,355,380}
+  y := []float64{1,2,3,4,5,6,7,8,9,10,11,12,13}
+
+  pool := &sync.Pool{}
+  b1, _ := New(x, y, BinSum, BinLessSize, WithPool(pool))
+  b1.FilterBins(5)
+  b1.Release()
+
+  b2, _ := New(x, y, BinSum, BinLessSize, WithPool(pool))
+  if b2.Largest.Lower != 120 || len(b2.Bins) != 13 {
+    t.Error("test failed")
+  }
+  checkSkipList(t, b2)
+}
+
+func TestPool4(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 1001)
+  y := make([]float64, 1000)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(r.Intn(10))
+  }
+  binning, _ := New(x, y, BinSum, BinLessY)
+  bins := &binning.Bins[0]
+
+  // compacting bins requires no allocations
+  a1 := testing.AllocsPerRun(10, func() {
+    binning.Reset(x, y)
+    for i := 0; i < 500; i++ {
+      binning.Delete(binning.Smallest)
+    }
+  })
+  a2 := testing.AllocsPerRun(10, func() {
+    binning.Reset(x, y)
+    for i := 0; i < 500; i++ {
+      binning.Delete(binning.Smallest)
+    }
+    binning.Compact()
+  })
+  if a1 != a2 {
+    t.Error("test failed")
+  }
+  if &binning.Bins[0] != bins || len(binning.Bins) != 500 {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+}
+
+func TestPool5(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 51)
+  y := make([]float64, 50)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(r.Intn(10))
+  }
+  b1, _ := New(x, y, BinSum, BinLessY)
+  b1.FilterBins(5)
+  // memory of deleted bins is released
+  if len(b1.Bins) != 5 || cap(b1.Bins) != 5 || cap(b1.skipLinks) > 4*len(b1.skipLinks) || cap(b1.skipSpare) > 4*len(b1.skipLinks) {
+    t.Error("test failed")
+  }
+  checkSkipList(t, b1)
+
+  pool := &sync.Pool{}
+  b2, _ := New(x, y, BinSum, BinLessY, WithPool(pool))
+  b2.FilterBins(5)
+  // memory of pools is reused
+  if len(b2.Bins) != 5 || cap(b2.Bins) != 50 {
+    t.Error("test failed")
+  }
+  if b1.String() != b2.String() {
+    t.Error("test failed")
+  }
+}
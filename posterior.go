@@ -0,0 +1,67 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Posterior probability of a bin together with a credible interval
+type PosteriorBin struct {
+  Probability float64
+  Credible    Interval
+}
+
+// Compute posterior bin probabilities given the bin counts and a symmetric
+// Dirichlet prior with concentration alpha. The probability of each bin is
+// the posterior mean (Y + alpha)/(N + K alpha), which is positive also for
+// empty bins. Credible intervals with the given level are computed from the
+// marginal Beta posterior of each bin.
+func (binning *Binning) PosteriorProbabilities(alpha, level float64) ([]PosteriorBin, error) {
+  if !(alpha > 0) || math.IsInf(alpha, 1) {
+    return nil, fmt.Errorf("%w: concentration parameter `%f'", ErrInvalidArgument, alpha)
+  }
+  if !(level > 0 && level < 1) {
+    return nil, fmt.Errorf("%w: credible level `%f'", ErrInvalidArgument, level)
+  }
+  n, k := 0.0, 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if !(at.Y >= 0) || math.IsInf(at.Y, 1) {
+      return nil, fmt.Errorf("%w: bin value `%f' is not a count", ErrInvalidArgument, at.Y)
+    }
+    n += at.Y
+    k += 1
+  }
+  r := []PosteriorBin{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    a := at.Y + alpha
+    b := n - at.Y + (k-1)*alpha
+    p := PosteriorBin{Probability: a/(a+b)}
+    if b > 0 {
+      p.Credible.Lower = betaQuantile((1-level)/2, a, b)
+      p.Credible.Upper = betaQuantile((1+level)/2, a, b)
+    } else {
+      // single bin
+      p.Credible = Interval{1, 1}
+    }
+    r = append(r, p)
+  }
+  return r, nil
+}
@@ -0,0 +1,52 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestPosterior1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 3}, []float64{0, 2, 8}, BinSum, BinLessSize)
+
+  r, err := binning.PosteriorProbabilities(1, 0.95)
+  if err != nil || len(r) != 3 {
+    t.Error("test failed"); return
+  }
+  if math.Abs(r[0].Probability - 1.0/13.0) > 1e-12 || math.Abs(r[2].Probability - 9.0/13.0) > 1e-12 {
+    t.Error("test failed")
+  }
+  // the marginal posterior of the first bin is Beta(1, 12) with quantiles
+  // 1 - (1-p)^(1/12)
+  lo := 1 - math.Pow(0.975, 1.0/12.0)
+  hi := 1 - math.Pow(0.025, 1.0/12.0)
+  if math.Abs(r[0].Credible.Lower - lo) > 1e-9 || math.Abs(r[0].Credible.Upper - hi) > 1e-9 {
+    t.Error("test failed")
+  }
+  for _, p := range r {
+    if p.Credible.Lower > p.Probability || p.Credible.Upper < p.Probability {
+      t.Error("test failed")
+    }
+  }
+  if _, err := binning.PosteriorProbabilities(0, 0.95); err == nil {
+    t.Error("test failed")
+  }
+}
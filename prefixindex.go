@@ -0,0 +1,173 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+/* -------------------------------------------------------------------------- */
+
+// Maintain prefix sums of bin values in a Fenwick tree over the positions
+// of the backing slice, so that Aggregate requires O(log n) operations
+// also while bins are merged. The tree is updated with O(log n) operations
+// on each merge and value modification and rebuilt lazily if bins are moved
+// within the backing slice, e.g. by Compact. Sums may differ from sums
+// computed without index by rounding errors. Values that are NaN (see
+// WithLazyValues) are treated as zero.
+func WithPrefixIndex() Option {
+  return func(c *config) {
+    c.prefixIndex = true
+  }
+}
+
+// Fenwick tree of values at positions 0, ..., n-1
+type fenwick struct {
+  tree   []float64
+  values []float64
+}
+
+func newFenwick(n int) fenwick {
+  return fenwick{tree: make([]float64, n+1), values: make([]float64, n)}
+}
+
+// Set value at position i
+func (f fenwick) set(i int, v float64) {
+  d := v - f.values[i]
+  f.values[i] = v
+  for k := i+1; k < len(f.tree); k += k & -k {
+    f.tree[k] += d
+  }
+}
+
+// Sum of values at positions 0, ..., i-1
+func (f fenwick) sum(i int) float64 {
+  r := 0.0
+  for k := i; k > 0; k -= k & -k {
+    r += f.tree[k]
+  }
+  return r
+}
+
+// Smallest position i such that the sum of values at positions 0, ..., i
+// is larger than v, values must be non-negative
+func (f fenwick) search(v float64) int {
+  n := len(f.tree)-1
+  k := 1
+  for k*2 <= n {
+    k *= 2
+  }
+  i := 0
+  for ; k > 0; k /= 2 {
+    if i+k <= n && f.tree[i+k] <= v {
+      i += k
+      v -= f.tree[i]
+    }
+  }
+  return i
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Prefix sums of values and widths of active bins, where widths are used
+// for locating positions, since boundaries of deleted bins are not ordered
+type prefixIndex struct {
+  values fenwick
+  widths fenwick
+  valid  bool
+}
+
+// Set position i of the prefix index
+func (binning *Binning) prefixSet(i int) {
+  t := binning.prefixes
+  y, w := 0.0, 0.0
+  if bin := &binning.Bins[i]; !bin.Deleted {
+    y, w = bin.Y, bin.Size()
+    if y != y {
+      y = 0.0
+    }
+  }
+  t.values.set(i, y)
+  t.widths.set(i, w)
+}
+
+// Returns the prefix index, which is rebuilt if necessary
+func (binning *Binning) prefixIndex() *prefixIndex {
+  t := binning.prefixes
+  if !t.valid {
+    t.values = newFenwick(len(binning.Bins))
+    t.widths = newFenwick(len(binning.Bins))
+    for i := range binning.Bins {
+      binning.prefixSet(i)
+    }
+    t.valid = true
+  }
+  return t
+}
+
+// Sum of bin values below x using the prefix index
+func (binning *Binning) prefixMass(x float64) float64 {
+  t := binning.prefixIndex()
+  if binning.First == nil || x <= binning.First.Lower {
+    return 0.0
+  }
+  if x >= binning.Last.Upper {
+    return t.values.sum(len(binning.Bins))
+  }
+  i := t.widths.search(x - binning.First.Lower)
+  if i >= len(binning.Bins) {
+    i = int(binning.Last.index)
+  }
+  at := &binning.Bins[i]
+  // correct rounding errors
+  for at.Lower > x && at.prev != noBin {
+    at = binning.Prev(at)
+  }
+  for at.Upper <= x && at.next != noBin {
+    at = binning.Next(at)
+  }
+  return t.values.sum(int(at.index)) + at.Y*(x-at.Lower)/at.Size()
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Update all indices after the value or boundaries of bin were modified or
+// the bin was deleted (see WithRangeIndex and WithPrefixIndex)
+func (binning *Binning) indexUpdate(bin *Bin) {
+  binning.rangeUpdate(bin)
+  if t := binning.prefixes; t != nil && t.valid {
+    binning.prefixSet(int(bin.index))
+  }
+}
+
+// Rebuild all indices before the next query, which is required if bins are
+// moved within the backing slice
+func (binning *Binning) indexInvalidate() {
+  binning.rangeInvalidate()
+  if binning.prefixes != nil {
+    binning.prefixes.valid = false
+  }
+}
+
+// Rebuild all stale indices, so that queries do not modify the binning and
+// can be performed concurrently (see SyncBinning)
+func (binning *Binning) indexBuild() {
+  if binning.ranges != nil {
+    binning.rangeIndex()
+  }
+  if binning.prefixes != nil {
+    binning.prefixIndex()
+  }
+}
@@ -0,0 +1,62 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestPrefixIndex1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 501)
+  y := make([]float64, 500)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(r.Intn(1000))
+  }
+  b1, _ := New(x, y, BinSum, BinLessY, WithPrefixIndex())
+  b2, _ := New(x, y, BinSum, BinLessY)
+
+  check := func() {
+    for k := 0; k < 100; k++ {
+      lo := (r.Float64()*1.2 - 0.1)*x[500]
+      hi := lo + r.Float64()*100
+      if a, b := b1.Aggregate(lo, hi), b2.Aggregate(lo, hi); math.Abs(a-b) > 1e-8*math.Max(1, b) {
+        t.Error("test failed"); return
+      }
+    }
+  }
+  check()
+  // interleave merges and queries
+  for i := 0; i < 400; i++ {
+    b1.Delete(b1.Smallest)
+    b2.Delete(b2.Smallest)
+    if i % 50 == 0 {
+      check()
+    }
+  }
+  b1.AddSample(x[10], 1e6)
+  b2.AddSample(x[10], 1e6)
+  check()
+  b1.Compact()
+  check()
+}
@@ -0,0 +1,51 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Smallest proportion used in PSI, which avoids infinite contributions of
+// empty bins
+const psiEpsilon = 1e-4
+
+// Population stability index between the distributions given by both
+// binnings. The values of actual are aligned to the bins of expected (see
+// Aggregate), hence both binnings may have different boundaries. Returns the
+// total index and the contribution of each bin of expected.
+func PSI(expected, actual *Binning) (float64, []float64) {
+  ne, na := 0.0, 0.0
+  for at := expected.First; at != nil; at = expected.Next(at) {
+    ne += at.Y
+  }
+  for at := actual.First; at != nil; at = actual.Next(at) {
+    na += at.Y
+  }
+  r := []float64{}
+  s := 0.0
+  for at := expected.First; at != nil; at = expected.Next(at) {
+    e := math.Max(psiEpsilon, at.Y/ne)
+    a := math.Max(psiEpsilon, actual.Aggregate(at.Lower, at.Upper)/na)
+    v := (a - e)*math.Log(a/e)
+    r  = append(r, v)
+    s += v
+  }
+  return s, r
+}
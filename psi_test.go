@@ -0,0 +1,43 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestPSI1(t *testing.T) {
+
+  expected, _ := New([]float64{0, 1, 2}, []float64{50, 50}, BinSum, BinLessSize)
+  actual1,  _ := New([]float64{0, 1, 2}, []float64{30, 70}, BinSum, BinLessSize)
+  actual2,  _ := New([]float64{0, 0.5, 1, 2}, []float64{20, 20, 60}, BinSum, BinLessSize)
+
+  if s, r := PSI(expected, expected); s != 0 || len(r) != 2 {
+    t.Error("test failed")
+  }
+  s1, r1 := PSI(expected, actual1)
+  if v := (0.3-0.5)*math.Log(0.3/0.5) + (0.7-0.5)*math.Log(0.7/0.5); math.Abs(s1 - v) > 1e-12 || math.Abs(r1[0] + r1[1] - v) > 1e-12 {
+    t.Error("test failed")
+  }
+  // actual2 has different boundaries, which are aligned to the bins of expected
+  if s2, _ := PSI(expected, actual2); math.Abs(s2 - (0.4-0.5)*math.Log(0.4/0.5) - (0.6-0.5)*math.Log(0.6/0.5)) > 1e-12 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,95 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Pyramid is a hierarchy of progressively coarser binnings, where each
+// level is obtained by merging bins of the previous level. Level zero is the
+// finest level.
+type Pyramid struct {
+  levels []*Frozen
+}
+
+// Create a copy of all active bins of the binning with the same options
+func (binning *Binning) clone() (*Binning, error) {
+  return New(binning.AppendBoundaries(nil), binning.AppendValues(nil), binning.Sum, binning.lessFunc, binning.options...)
+}
+
+// Create a pyramid with the given numbers of bins per level, where levels
+// must be decreasing. Levels are computed by filtering a copy of the
+// binning, hence the binning is not modified.
+func (binning *Binning) Pyramid(levels []int) (*Pyramid, error) {
+  for i := range levels {
+    if levels[i] < 1 || i > 0 && levels[i] >= levels[i-1] {
+      return nil, fmt.Errorf("%w: numbers of bins must be positive and decreasing", ErrInvalidArgument)
+    }
+  }
+  tmp, err := binning.clone()
+  if err != nil {
+    return nil, err
+  }
+  p := &Pyramid{}
+  for _, n := range levels {
+    if err := tmp.FilterBins(n); err != nil {
+      return nil, err
+    }
+    p.levels = append(p.levels, tmp.Frozen())
+  }
+  return p, nil
+}
+
+// Number of levels
+func (obj *Pyramid) Len() int {
+  return len(obj.levels)
+}
+
+// Returns the i-th level
+func (obj *Pyramid) Level(i int) *Frozen {
+  return obj.levels[i]
+}
+
+// Returns the finest level with at most n bins overlapping the interval
+// [lo, hi), or the coarsest level if no such level exists. This selects the
+// resolution of a zoomable view showing [lo, hi) with at most n bins.
+func (obj *Pyramid) Select(lo, hi float64, n int) (*Frozen, int) {
+  for i, level := range obj.levels {
+    if level.count(lo, hi) <= n {
+      return level, i
+    }
+  }
+  i := len(obj.levels)-1
+  return obj.levels[i], i
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Number of bins overlapping the interval [lo, hi)
+func (obj *Frozen) count(lo, hi float64) int {
+  n := len(obj.values)
+  i := sort.Search(n, func(i int) bool { return obj.boundaries[i+1] > lo })
+  j := sort.Search(n, func(i int) bool { return obj.boundaries[i] >= hi })
+  if j < i {
+    return 0
+  }
+  return j-i
+}
@@ -0,0 +1,60 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestPyramid1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 101)
+  y := make([]float64, 100)
+  for i := 1; i < len(x); i++ {
+    x[i] = float64(i)
+    y[i-1] = float64(r.Intn(10))
+  }
+  binning, _ := New(x, y, BinSum, BinLessY)
+  p, err := binning.Pyramid([]int{50, 20, 5})
+  if err != nil || p.Len() != 3 || len(binning.Bins) != 100 {
+    t.Error("test failed"); return
+  }
+  // each level is a coarsening of the previous level
+  for i := 1; i < p.Len(); i++ {
+    for _, b := range p.Level(i).Boundaries() {
+      if j := p.Level(i-1).FindBin(b); j >= 0 && p.Level(i-1).Bin(j).Lower != b {
+        t.Error("test failed")
+      }
+    }
+  }
+  if level, i := p.Select(0, 100, 30); i != 1 || level.Len() != 20 {
+    t.Error("test failed")
+  }
+  if _, i := p.Select(0, 10, 30); i != 0 {
+    t.Error("test failed")
+  }
+  if _, i := p.Select(0, 100, 1); i != 2 {
+    t.Error("test failed")
+  }
+  if _, err := binning.Pyramid([]int{5, 20}); err == nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,257 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "container/heap"
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Rectangle is a leaf of a quadtree, i.e. the region
+// [XLower, XUpper) x [YLower, YUpper) containing Count points
+type Rectangle struct {
+  XLower float64
+  XUpper float64
+  YLower float64
+  YUpper float64
+  Count  int
+}
+
+func (r Rectangle) Area() float64 {
+  return (r.XUpper - r.XLower)*(r.YUpper - r.YLower)
+}
+
+// Number of points per unit area
+func (r Rectangle) Density() float64 {
+  return float64(r.Count)/r.Area()
+}
+
+func (r Rectangle) String() string {
+  return fmt.Sprintf("[%f, %f)x[%f, %f):%d", r.XLower, r.XUpper, r.YLower, r.YUpper, r.Count)
+}
+
+/* -------------------------------------------------------------------------- */
+
+type quadNode struct {
+  Rectangle
+  parent   *quadNode
+  children []*quadNode
+}
+
+func (node *quadNode) isLeaf() bool {
+  return node.children == nil
+}
+
+// Returns true if all children are leaves
+func (node *quadNode) isMergeable() bool {
+  if node.isLeaf() {
+    return false
+  }
+  for _, child := range node.children {
+    if !child.isLeaf() {
+      return false
+    }
+  }
+  return true
+}
+
+type quadHeap []*quadNode
+
+func (h quadHeap) Len() int {
+  return len(h)
+}
+
+func (h quadHeap) Less(i, j int) bool {
+  return h[i].Count < h[j].Count
+}
+
+func (h quadHeap) Swap(i, j int) {
+  h[i], h[j] = h[j], h[i]
+}
+
+func (h *quadHeap) Push(x interface{}) {
+  *h = append(*h, x.(*quadNode))
+}
+
+func (h *quadHeap) Pop() interface{} {
+  n := len(*h)
+  r := (*h)[n-1]
+  *h = (*h)[0:n-1]
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Quadtree is an adaptive binning of two-dimensional points. Regions with
+// many points are split into four quadrants, while regions with few points
+// are kept as single rectangles.
+type Quadtree struct {
+  root   *quadNode
+  leaves int
+}
+
+// Create a quadtree over the bounding box of all points (x[i], y[i]). A
+// rectangle is split if it contains more than maxCount points, up to a
+// depth of maxDepth.
+func NewQuadtree(x, y []float64, maxCount, maxDepth int) (*Quadtree, error) {
+  if len(x) != len(y) {
+    return nil, ErrLengthMismatch
+  }
+  if len(x) == 0 {
+    return nil, fmt.Errorf("%w: no points given", ErrInvalidArgument)
+  }
+  if maxCount < 1 {
+    return nil, fmt.Errorf("%w: maximum number of points must be positive", ErrInvalidArgument)
+  }
+  r := Rectangle{math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1), len(x)}
+  for i := range x {
+    if !isFinite(x[i]) || !isFinite(y[i]) {
+      return nil, fmt.Errorf("%w: point (%f, %f)", ErrNonFinite, x[i], y[i])
+    }
+    r.XLower = math.Min(r.XLower, x[i])
+    r.XUpper = math.Max(r.XUpper, x[i])
+    r.YLower = math.Min(r.YLower, y[i])
+    r.YUpper = math.Max(r.YUpper, y[i])
+  }
+  // include points on the upper boundaries
+  r.XUpper = math.Nextafter(r.XUpper, math.Inf(1))
+  r.YUpper = math.Nextafter(r.YUpper, math.Inf(1))
+
+  points := make([]int, len(x))
+  for i := range points {
+    points[i] = i
+  }
+  tree := Quadtree{root: &quadNode{Rectangle: r}}
+  tree.split(tree.root, x, y, points, maxCount, maxDepth)
+  return &tree, nil
+}
+
+func (tree *Quadtree) split(node *quadNode, x, y []float64, points []int, maxCount, depth int) {
+  if len(points) <= maxCount || depth <= 0 {
+    tree.leaves++
+    return
+  }
+  xm := node.XLower + (node.XUpper - node.XLower)/2
+  ym := node.YLower + (node.YUpper - node.YLower)/2
+  node.children = []*quadNode{
+    &quadNode{Rectangle: Rectangle{node.XLower, xm, node.YLower, ym, 0}, parent: node},
+    &quadNode{Rectangle: Rectangle{xm, node.XUpper, node.YLower, ym, 0}, parent: node},
+    &quadNode{Rectangle: Rectangle{node.XLower, xm, ym, node.YUpper, 0}, parent: node},
+    &quadNode{Rectangle: Rectangle{xm, node.XUpper, ym, node.YUpper, 0}, parent: node} }
+  // partition points in place by quadrant
+  quadrant := func(i int) int {
+    k := 0
+    if x[i] >= xm {
+      k += 1
+    }
+    if y[i] >= ym {
+      k += 2
+    }
+    return k
+  }
+  for i := range points {
+    node.children[quadrant(points[i])].Count++
+  }
+  offsets := [5]int{}
+  for k := 0; k < 4; k++ {
+    offsets[k+1] = offsets[k] + node.children[k].Count
+  }
+  next := offsets
+  for k := 0; k < 4; k++ {
+    for next[k] < offsets[k+1] {
+      if j := quadrant(points[next[k]]); j == k {
+        next[k]++
+      } else {
+        points[next[k]], points[next[j]] = points[next[j]], points[next[k]]
+        next[j]++
+      }
+    }
+  }
+  for k := 0; k < 4; k++ {
+    tree.split(node.children[k], x, y, points[offsets[k]:offsets[k+1]], maxCount, depth-1)
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Merge quadrants until at most n leaves remain. In each step the four
+// quadrants of the rectangle with the fewest points are merged.
+func (tree *Quadtree) FilterLeaves(n int) {
+  h := quadHeap{}
+  tree.walk(tree.root, func(node *quadNode) {
+    if node.isMergeable() {
+      h = append(h, node)
+    }
+  })
+  heap.Init(&h)
+  for tree.leaves > n && h.Len() > 0 {
+    node := heap.Pop(&h).(*quadNode)
+    node.children = nil
+    tree.leaves  -= 3
+    if node.parent != nil && node.parent.isMergeable() {
+      heap.Push(&h, node.parent)
+    }
+  }
+}
+
+func (tree *Quadtree) walk(node *quadNode, f func(*quadNode)) {
+  f(node)
+  for _, child := range node.children {
+    tree.walk(child, f)
+  }
+}
+
+// Number of leaves
+func (tree *Quadtree) Len() int {
+  return tree.leaves
+}
+
+// Returns all leaves of the quadtree
+func (tree *Quadtree) Leaves() []Rectangle {
+  r := make([]Rectangle, 0, tree.leaves)
+  tree.walk(tree.root, func(node *quadNode) {
+    if node.isLeaf() {
+      r = append(r, node.Rectangle)
+    }
+  })
+  return r
+}
+
+// Find the leaf containing (x, y). False is returned if the point is outside
+// the bounding box.
+func (tree *Quadtree) FindLeaf(x, y float64) (Rectangle, bool) {
+  node := tree.root
+  if x < node.XLower || x >= node.XUpper || y < node.YLower || y >= node.YUpper {
+    return Rectangle{}, false
+  }
+  for !node.isLeaf() {
+    xm := node.children[0].XUpper
+    ym := node.children[0].YUpper
+    k  := 0
+    if x >= xm {
+      k += 1
+    }
+    if y >= ym {
+      k += 2
+    }
+    node = node.children[k]
+  }
+  return node.Rectangle, true
+}
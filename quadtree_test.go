@@ -0,0 +1,75 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestQuadtree1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := []float64{}
+  y := []float64{}
+  // dense cluster in the lower left corner
+  for i := 0; i < 1000; i++ {
+    x = append(x, r.Float64())
+    y = append(y, r.Float64())
+  }
+  for i := 0; i < 100; i++ {
+    x = append(x, 10*r.Float64())
+    y = append(y, 10*r.Float64())
+  }
+  tree, err := NewQuadtree(x, y, 50, 10)
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  check := func(maxCount int) {
+    leaves := tree.Leaves()
+    if len(leaves) != tree.Len() {
+      t.Error("test failed")
+    }
+    n := 0
+    for _, leaf := range leaves {
+      n += leaf.Count
+      if leaf.Count > maxCount {
+        t.Error("test failed")
+      }
+    }
+    if n != len(x) {
+      t.Error("test failed")
+    }
+  }
+  check(50)
+  if leaf, ok := tree.FindLeaf(0.5, 0.5); !ok || leaf.Area() > 1 {
+    t.Error("test failed")
+  }
+  if leaf, ok := tree.FindLeaf(9, 9); !ok || leaf.Area() < 1 {
+    t.Error("test failed")
+  }
+  tree.FilterLeaves(10)
+  if tree.Len() > 10 {
+    t.Error("test failed")
+  }
+  check(len(x))
+  if _, ok := tree.FindLeaf(-1, 0); ok {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,110 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Quality metrics of a binning, where bin values are counts of samples that
+// are uniformly distributed within bins (see Quality)
+type Metrics struct {
+  Bins            int
+  // variance of samples within bins, i.e. the average variance of uniform
+  // distributions over bins, and variance of the bin centers
+  WithinVariance  float64
+  BetweenVariance float64
+  // entropy of the bin probabilities in nats
+  Entropy         float64
+  // entropy divided by its maximum log(Bins), which is one for bins with
+  // equal counts (Pielou's evenness)
+  Uniformity      float64
+  MinWidth        float64
+  MaxWidth        float64
+  // information value of a binary target if all bins have GroupCounts
+  // payloads (see NewSupervised), otherwise NaN
+  IV              float64
+}
+
+// Compute quality metrics of the binning. Metrics that depend on
+// probabilities are NaN if the total value is not positive. The missing
+// bin is only considered for the information value.
+func (binning *Binning) Quality() Metrics {
+  r := Metrics{MinWidth: math.Inf(1), MaxWidth: math.Inf(-1)}
+  n := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    r.Bins++
+    r.MinWidth = math.Min(r.MinWidth, at.Size())
+    r.MaxWidth = math.Max(r.MaxWidth, at.Size())
+    n += at.Y
+  }
+  if !(n > 0) {
+    r.WithinVariance, r.BetweenVariance, r.Entropy, r.Uniformity, r.IV =
+      math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN()
+    return r
+  }
+  mean := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    mean += at.Y/n*(at.Lower + at.Upper)/2
+  }
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    p := at.Y/n
+    c := (at.Lower + at.Upper)/2
+    r.WithinVariance  += p*at.Size()*at.Size()/12
+    r.BetweenVariance += p*(c - mean)*(c - mean)
+    if p > 0 {
+      r.Entropy -= p*math.Log(p)
+    }
+  }
+  if r.Bins > 1 {
+    r.Uniformity = r.Entropy/math.Log(float64(r.Bins))
+  } else {
+    r.Uniformity = 1
+  }
+  r.IV = binning.informationValue()
+  return r
+}
+
+// Information value of GroupCounts payloads or NaN if a bin has no such
+// payload
+func (binning *Binning) informationValue() float64 {
+  neg := []float64{}
+  pos := []float64{}
+  add := func(bin *Bin) bool {
+    c, ok := bin.Data.(GroupCounts)
+    if ok {
+      neg = append(neg, c[0])
+      pos = append(pos, c[1])
+    }
+    return ok
+  }
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if !add(at) {
+      return math.NaN()
+    }
+  }
+  if binning.Missing != nil && binning.Missing.Data != nil && !add(binning.Missing) {
+    return math.NaN()
+  }
+  _, iv, err := weightOfEvidence(neg, pos)
+  if err != nil {
+    return math.NaN()
+  }
+  return iv
+}
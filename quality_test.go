@@ -0,0 +1,56 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestQuality1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 3, 4}, []float64{1, 2, 1}, BinSum, BinLessY)
+
+  m := binning.Quality()
+  if m.Bins != 3 || m.MinWidth != 1 || m.MaxWidth != 2 || !math.IsNaN(m.IV) {
+    t.Error("test failed")
+  }
+  // p = 1/4, 1/2, 1/4 with centers 0.5, 2, 3.5
+  if math.Abs(m.WithinVariance - (0.25/12 + 0.5*4/12 + 0.25/12)) > 1e-12 {
+    t.Error("test failed")
+  }
+  if math.Abs(m.BetweenVariance - (0.25*2.25 + 0.25*2.25)) > 1e-12 {
+    t.Error("test failed")
+  }
+  if e := 1.5*math.Log(2); math.Abs(m.Entropy - e) > 1e-12 || math.Abs(m.Uniformity - e/math.Log(3)) > 1e-12 {
+    t.Error("test failed")
+  }
+}
+
+func TestQuality2(t *testing.T) {
+
+  data   := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+  target := []bool{false, false, false, true, false, true, true, true}
+
+  binning, _ := NewSupervised(data, target, 2, SeparationKS, BinLessY)
+  _, iv, _   := binning.WeightOfEvidence(data, target)
+  if m := binning.Quality(); math.Abs(m.IV - iv) > 1e-12 || m.IV <= 0 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,120 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Find the bin containing x, i.e. Lower <= x < Upper. Nil is returned if x
+// is outside the range of the binning. The search requires O(log n)
+// operations if the binning contains no deleted bins, i.e. after calling
+// Compact or Update. Positions within the tolerance of a boundary (see
+// WithEpsilon) are assigned to the bin starting at this boundary, except
+// for the upper boundary of the last bin. Positions of circular binnings
+// are first mapped to the domain of the binning.
+func (binning *Binning) FindBin(x float64) *Bin {
+  eps := binning.config.epsilon
+  switch {
+  case binning.period > 0 && binning.First != nil:
+    x = binning.wrap(x + eps)
+  case binning.Last == nil || x + eps < binning.Last.Upper:
+    x += eps
+  }
+  if binning.deleted == 0 {
+    bins := binning.Bins
+    i := sort.Search(len(bins), func(i int) bool { return bins[i].Upper > x })
+    if i < len(bins) && bins[i].Lower <= x {
+      return &bins[i]
+    }
+    return nil
+  }
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if at.Lower <= x && x < at.Upper {
+      return at
+    }
+  }
+  return nil
+}
+
+// Sum of bin values in the interval [lo, hi). Bin values are assumed to be
+// uniformly distributed within each bin, i.e. bins that only partially
+// overlap with the interval contribute proportionally to the overlap. The
+// sum requires a walk over all bins in the interval, unless the binning is
+// created with WithPrefixIndex. Frozen answers repeated queries with
+// O(log n) operations.
+func (binning *Binning) Aggregate(lo, hi float64) float64 {
+  r := 0.0
+  if lo >= hi {
+    return r
+  }
+  if binning.prefixes != nil {
+    return binning.prefixMass(hi) - binning.prefixMass(lo)
+  }
+  at := binning.FindBin(lo)
+  if at == nil && binning.First != nil && lo < binning.First.Lower {
+    at = binning.First
+  }
+  for ; at != nil && at.Lower < hi; at = binning.Next(at) {
+    a := math.Max(lo, at.Lower)
+    b := math.Min(hi, at.Upper)
+    if b > a {
+      r += at.Y*(b-a)/at.Size()
+    }
+  }
+  return r
+}
+
+// Add w to the value of the bin containing x. The bin is moved to its new
+// position in the sorted list. NaN samples are added to the missing bin if
+// present (see WithMissingBin), other non-finite samples are handled
+// according to the policy given by WithNonFinitePolicy.
+func (binning *Binning) AddSample(x, w float64) error {
+  if math.IsNaN(x) && binning.Missing != nil {
+    binning.Missing.Y += w
+    binning.emit(Event{Kind: EventUpdate, Bin: *binning.Missing})
+    return nil
+  }
+  x, ok, err := binning.filterNonFiniteSample(x, w)
+  if !ok {
+    return err
+  }
+  bin := binning.FindBin(x)
+  if bin == nil {
+    return fmt.Errorf("%w: `%f'", ErrOutOfRange, x)
+  }
+  bin.Y += w
+  binning.reposition(bin)
+  binning.undo = binning.undo[0:0]
+  binning.traceInvalidate()
+  binning.emit(Event{Kind: EventUpdate, Bin: *bin})
+  return binning.check("adding a sample")
+}
+
+// Move a modified bin to its new position in the sorted list
+func (binning *Binning) reposition(bin *Bin) {
+  binning.indexUpdate(bin)
+  if !bin.dirty {
+    binning.deleteBinSorted(bin)
+    binning.updateKey(bin)
+    binning.skipInsert(bin)
+  }
+}
@@ -0,0 +1,62 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestQuery1(t *testing.T) {
+
+  x := []float64{-100,-99,1,2,3,6,8,19,21,120,300,350,355,380}
+  y := []float64{1,2,3,4,5,6,7,8,9,10,11,12,13}
+
+  binning, _ := New(x, y, BinSum, BinLessY)
+
+  if bin := binning.FindBin(2.5); bin == nil || bin.Lower != 2 {
+    t.Error("test failed")
+  }
+  if bin := binning.FindBin(380); bin != nil {
+    t.Error("test failed")
+  }
+  if bin := binning.FindBin(-100); bin == nil || bin.Lower != -100 {
+    t.Error("test failed")
+  }
+  binning.Delete(&binning.Bins[3])
+  if bin := binning.FindBin(2.5); bin == nil || bin.Lower != 1 || bin.Upper != 3 {
+    t.Error("test failed")
+  }
+  if r := binning.Aggregate(-200, 500); r != 91 {
+    t.Error("test failed")
+  }
+  if r := binning.Aggregate(0, 4.5); r != 2.0/100.0 + 7 + 5.0/2.0 {
+    t.Error("test failed")
+  }
+  if err := binning.AddSample(360, 100); err != nil {
+    t.Error("test failed")
+  }
+  if binning.Largest.Lower != 355 {
+    t.Error("test failed")
+  }
+  if err := binning.AddSample(400, 1); err == nil {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+}
@@ -0,0 +1,200 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+/* -------------------------------------------------------------------------- */
+
+// Maintain a segment tree over the positions of the backing slice, which
+// answers range minimum and maximum queries of bin values (see RangeMax).
+// The tree is updated with O(log n) operations on each merge and value
+// modification and rebuilt lazily if bins are moved within the backing
+// slice, e.g. by Compact.
+func WithRangeIndex() Option {
+  return func(c *config) {
+    c.rangeIndex = true
+  }
+}
+
+// Segment tree storing the positions of the bins with the smallest and
+// largest values of each node, deleted bins and values that are NaN are
+// ignored. Each node also stores its first and last active bin, which are
+// required for locating intervals, since boundaries of deleted bins are not
+// ordered.
+type rangeIndex struct {
+  size  int
+  min   []int32
+  max   []int32
+  first []int32
+  last  []int32
+  valid bool
+}
+
+func (binning *Binning) rangeSelect(i, j int32, larger bool) int32 {
+  if i == noBin {
+    return j
+  }
+  if j == noBin {
+    return i
+  }
+  a, b := binning.Bins[i].Y, binning.Bins[j].Y
+  // prefer the leftmost bin on ties
+  if larger && b > a || !larger && b < a {
+    return j
+  }
+  return i
+}
+
+// Set leaf of the segment tree at position i of the backing slice
+func (binning *Binning) rangeLeaf(i int) {
+  t := binning.ranges
+  k := t.size + i
+  t.min  [k], t.max [k] = noBin, noBin
+  t.first[k], t.last[k] = noBin, noBin
+  if i < len(binning.Bins) && !binning.Bins[i].Deleted {
+    t.first[k], t.last[k] = int32(i), int32(i)
+    if y := binning.Bins[i].Y; y == y {
+      t.min[k], t.max[k] = int32(i), int32(i)
+    }
+  }
+}
+
+func (binning *Binning) rangeFix(k int) {
+  t := binning.ranges
+  t.min[k] = binning.rangeSelect(t.min[2*k], t.min[2*k+1], false)
+  t.max[k] = binning.rangeSelect(t.max[2*k], t.max[2*k+1], true)
+  t.first[k], t.last[k] = t.first[2*k], t.last[2*k+1]
+  if t.first[k] == noBin {
+    t.first[k] = t.first[2*k+1]
+  }
+  if t.last[k] == noBin {
+    t.last[k] = t.last[2*k]
+  }
+}
+
+// Returns the segment tree, which is rebuilt if necessary
+func (binning *Binning) rangeIndex() *rangeIndex {
+  t := binning.ranges
+  if t.valid {
+    return t
+  }
+  n := len(binning.Bins)
+  t.size = 1
+  for t.size < n {
+    t.size *= 2
+  }
+  t.min   = make([]int32, 2*t.size)
+  t.max   = make([]int32, 2*t.size)
+  t.first = make([]int32, 2*t.size)
+  t.last  = make([]int32, 2*t.size)
+  for i := 0; i < t.size; i++ {
+    binning.rangeLeaf(i)
+  }
+  for k := t.size-1; k > 0; k-- {
+    binning.rangeFix(k)
+  }
+  t.valid = true
+  return t
+}
+
+// Update the segment tree after the value of bin was modified or the bin
+// was deleted
+func (binning *Binning) rangeUpdate(bin *Bin) {
+  if t := binning.ranges; t != nil && t.valid {
+    binning.rangeLeaf(int(bin.index))
+    for k := (t.size + int(bin.index))/2; k > 0; k /= 2 {
+      binning.rangeFix(k)
+    }
+  }
+}
+
+// Rebuild the segment tree before the next query, which is required if
+// bins are moved within the backing slice
+func (binning *Binning) rangeInvalidate() {
+  if binning.ranges != nil {
+    binning.ranges.valid = false
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Query the segment tree for bins overlapping with [lo, hi)
+func (binning *Binning) rangeQuery(lo, hi float64, larger bool) *Bin {
+  if binning.ranges == nil || lo >= hi {
+    return nil
+  }
+  t := binning.rangeIndex()
+  // find the first active bin with an upper boundary larger than lo
+  // and the last active bin with a lower boundary smaller than hi
+  k := 1
+  for k < t.size {
+    if l := t.last[2*k]; l != noBin && binning.Bins[l].Upper > lo {
+      k = 2*k
+    } else {
+      k = 2*k+1
+    }
+  }
+  i := int(t.first[k])
+  k  = 1
+  for k < t.size {
+    if f := t.first[2*k+1]; f != noBin && binning.Bins[f].Lower < hi {
+      k = 2*k+1
+    } else {
+      k = 2*k
+    }
+  }
+  j := int(t.last[k])+1
+  if i < 0 || j <= i || binning.Bins[i].Upper <= lo || binning.Bins[j-1].Lower >= hi {
+    return nil
+  }
+  r := noBin
+  nodes := t.max
+  if !larger {
+    nodes = t.min
+  }
+  // collect nodes from left to right so that ties
+  // are resolved in favor of the leftmost bin
+  right := []int32{}
+  for a, b := i+t.size, j+t.size; a < b; a, b = a/2, b/2 {
+    if a%2 == 1 {
+      r = binning.rangeSelect(r, nodes[a], larger); a++
+    }
+    if b%2 == 1 {
+      b--; right = append(right, nodes[b])
+    }
+  }
+  for k := len(right)-1; k >= 0; k-- {
+    r = binning.rangeSelect(r, right[k], larger)
+  }
+  return binning.bin(r)
+}
+
+// Returns the bin with the largest value among all bins overlapping with
+// the interval [lo, hi) or nil if there is no such bin. The binning must be
+// created with WithRangeIndex, queries require O(log n) operations. Bins
+// with NaN values are ignored and intervals of circular binnings are not
+// wrapped.
+func (binning *Binning) RangeMax(lo, hi float64) *Bin {
+  return binning.rangeQuery(lo, hi, true)
+}
+
+// Returns the bin with the smallest value among all bins overlapping with
+// the interval [lo, hi) or nil if there is no such bin (see RangeMax)
+func (binning *Binning) RangeMin(lo, hi float64) *Bin {
+  return binning.rangeQuery(lo, hi, false)
+}
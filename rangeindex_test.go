@@ -0,0 +1,80 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func rangeIndexNaive(binning *Binning, lo, hi float64, larger bool) *Bin {
+  var r *Bin
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if at.Upper <= lo || at.Lower >= hi {
+      continue
+    }
+    if r == nil || larger && at.Y > r.Y || !larger && at.Y < r.Y {
+      r = at
+    }
+  }
+  return r
+}
+
+func TestRangeIndex1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 501)
+  y := make([]float64, 500)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(r.Intn(1000))
+  }
+  binning, _ := New(x, y, BinSum, BinLessY, WithRangeIndex())
+
+  check := func() {
+    for k := 0; k < 100; k++ {
+      lo := r.Float64()*x[500]
+      hi := lo + r.Float64()*50
+      if binning.RangeMax(lo, hi) != rangeIndexNaive(binning, lo, hi, true) {
+        t.Error("test failed"); return
+      }
+      if binning.RangeMin(lo, hi) != rangeIndexNaive(binning, lo, hi, false) {
+        t.Error("test failed"); return
+      }
+    }
+  }
+  check()
+  // interleave merges and queries
+  for i := 0; i < 300; i++ {
+    binning.Delete(binning.Smallest)
+    if i % 50 == 0 {
+      check()
+    }
+  }
+  binning.AddSample(x[10], 1e6)
+  check()
+  binning.Compact()
+  check()
+  if binning.RangeMax(x[0], x[500]).Y < 1e6 {
+    t.Error("test failed")
+  }
+  if binning.RangeMax(x[500]+1, x[500]+2) != nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,149 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "sort"
+import "sync"
+
+/* -------------------------------------------------------------------------- */
+
+// Registry of named sum, less and cost functions, so that they can be
+// selected in configuration files or on the command line. Cost functions
+// are key functions (see WithKey), where bins with smaller costs are
+// merged first.
+var registry = struct {
+  sync.RWMutex
+  sums  map[string]func(Bin, Bin) float64
+  less  map[string]func(Bin, Bin) bool
+  costs map[string]func(Bin) float64
+}{
+  sums: map[string]func(Bin, Bin) float64{
+    "sum"   : BinSum,
+    "logsum": BinLogSum,
+  },
+  less: map[string]func(Bin, Bin) bool{
+    "count": BinLessY,
+    "size" : BinLessSize,
+    "width": BinLessSize,
+    "area" : BinLessArea,
+  },
+  costs: map[string]func(Bin) float64{
+    "count"   : func(bin Bin) float64 { return bin.Y },
+    "size"    : func(bin Bin) float64 { return bin.Size() },
+    "density" : func(bin Bin) float64 { return bin.Y/bin.Size() },
+    "mass"    : func(bin Bin) float64 { return bin.Y*bin.Size() },
+    "area"    : func(bin Bin) float64 { return bin.Area() },
+    // contribution of the bin to the variance of samples within bins,
+    // assuming samples are uniformly distributed (see Metrics)
+    "variance": func(bin Bin) float64 { return bin.Y*bin.Size()*bin.Size()/12 },
+  },
+}
+
+// Register a sum function under name, an existing function with the same
+// name is replaced
+func RegisterSum(name string, f func(Bin, Bin) float64) {
+  registry.Lock()
+  defer registry.Unlock()
+  registry.sums[name] = f
+}
+
+// Register a less function under name, an existing function with the same
+// name is replaced
+func RegisterLess(name string, f func(Bin, Bin) bool) {
+  registry.Lock()
+  defer registry.Unlock()
+  registry.less[name] = f
+}
+
+// Register a cost function under name, an existing function with the same
+// name is replaced
+func RegisterCost(name string, f func(Bin) float64) {
+  registry.Lock()
+  defer registry.Unlock()
+  registry.costs[name] = f
+}
+
+// Returns the sum function registered under name
+func LookupSum(name string) (func(Bin, Bin) float64, error) {
+  registry.RLock()
+  defer registry.RUnlock()
+  if f, ok := registry.sums[name]; ok {
+    return f, nil
+  }
+  return nil, fmt.Errorf("%w: unknown sum function `%s'", ErrInvalidArgument, name)
+}
+
+// Returns the less function registered under name
+func LookupLess(name string) (func(Bin, Bin) bool, error) {
+  registry.RLock()
+  defer registry.RUnlock()
+  if f, ok := registry.less[name]; ok {
+    return f, nil
+  }
+  return nil, fmt.Errorf("%w: unknown less function `%s'", ErrInvalidArgument, name)
+}
+
+// Returns the cost function registered under name
+func LookupCost(name string) (func(Bin) float64, error) {
+  registry.RLock()
+  defer registry.RUnlock()
+  if f, ok := registry.costs[name]; ok {
+    return f, nil
+  }
+  return nil, fmt.Errorf("%w: unknown cost function `%s'", ErrInvalidArgument, name)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Names of all registered sum functions in sorted order
+func SumNames() []string {
+  registry.RLock()
+  defer registry.RUnlock()
+  r := []string{}
+  for name := range registry.sums {
+    r = append(r, name)
+  }
+  sort.Strings(r)
+  return r
+}
+
+// Names of all registered less functions in sorted order
+func LessNames() []string {
+  registry.RLock()
+  defer registry.RUnlock()
+  r := []string{}
+  for name := range registry.less {
+    r = append(r, name)
+  }
+  sort.Strings(r)
+  return r
+}
+
+// Names of all registered cost functions in sorted order
+func CostNames() []string {
+  registry.RLock()
+  defer registry.RUnlock()
+  r := []string{}
+  for name := range registry.costs {
+    r = append(r, name)
+  }
+  sort.Strings(r)
+  return r
+}
@@ -0,0 +1,90 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "errors"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestRegistry1(t *testing.T) {
+
+  x := []float64{0, 1, 2, 4, 8}
+  y := []float64{3, 1, 2, 5}
+
+  sum, err := LookupSum("sum")
+  if err != nil {
+    t.Error(err); return
+  }
+  less, err := LookupLess("size")
+  if err != nil {
+    t.Error(err); return
+  }
+  binning, _ := New(x, y, sum, less)
+  binning.FilterBins(3)
+
+  if binning.String() != "[0.000000, 2.000000):4 [2.000000, 4.000000):2 [4.000000, 8.000000):5" {
+    t.Error("test failed")
+  }
+  if _, err := LookupLess("foo"); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+}
+
+func TestRegistry2(t *testing.T) {
+
+  RegisterCost("test", func(bin Bin) float64 { return -bin.Y })
+  cost, err := LookupCost("test")
+  if err != nil {
+    t.Error(err); return
+  }
+  binning, _ := New([]float64{0, 1, 2, 3}, []float64{1, 3, 2}, BinSum, nil, WithKey(cost))
+  binning.FilterBins(2)
+
+  if r := binning.AppendValues(nil); len(r) != 2 || r[0] != 1 || r[1] != 5 {
+    t.Error("test failed")
+  }
+  found := false
+  for _, name := range CostNames() {
+    if name == "test" {
+      found = true
+    }
+  }
+  if !found {
+    t.Error("test failed")
+  }
+}
+
+func TestRegistry3(t *testing.T) {
+
+  cost, err := LookupCost("variance")
+  if err != nil {
+    t.Error(err); return
+  }
+  if r := cost(Bin{Lower: 1, Upper: 3, Y: 6}); r != 2 {
+    t.Error("test failed")
+  }
+  // wide bins are merged last
+  binning, _ := New([]float64{0, 1, 2, 4, 5}, []float64{2, 2, 2, 2}, BinSum, nil, WithKey(cost))
+  binning.FilterBins(2)
+
+  if r := binning.AppendBoundaries(nil); len(r) != 3 || r[1] != 2 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,132 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math/rand"
+
+/* -------------------------------------------------------------------------- */
+
+// Reservoir computes an approximate binning of an unbounded stream of
+// samples with fixed memory. A uniform random sample of the stream is kept
+// in a reservoir of fixed size, and the binning is refitted to the
+// reservoir after a fixed number of new samples. Samples outside the range
+// of the reservoir are not covered by the binning.
+type Reservoir struct {
+  strategy Strategy
+  samples  []float64
+  size     int
+  refit    int
+  // number of samples seen and added since the last fit
+  n        int
+  added    int
+  rng     *rand.Rand
+  binning *Binning
+}
+
+// Create a reservoir of the given size, where a new binning is fitted with
+// strategy after every refit samples. Samples are selected with a random
+// number generator initialized with seed.
+func NewReservoir(size, refit int, strategy Strategy, seed int64) (*Reservoir, error) {
+  return NewReservoirRand(size, refit, strategy, rand.New(rand.NewSource(seed)))
+}
+
+// Same as NewReservoir, but samples are selected with rng, which must not be
+// used concurrently by other goroutines
+func NewReservoirRand(size, refit int, strategy Strategy, rng *rand.Rand) (*Reservoir, error) {
+  if size < 1 {
+    return nil, fmt.Errorf("%w: reservoir size must be positive", ErrInvalidArgument)
+  }
+  if refit < 1 {
+    return nil, fmt.Errorf("%w: refit interval must be positive", ErrInvalidArgument)
+  }
+  if rng == nil {
+    return nil, fmt.Errorf("%w: no random number generator given", ErrInvalidArgument)
+  }
+  return &Reservoir{
+    strategy: strategy,
+    samples : make([]float64, 0, size),
+    size    : size,
+    refit   : refit,
+    rng     : rng }, nil
+}
+
+// Add a sample to the stream (Algorithm R). Non-finite samples are ignored.
+// An error is returned if refitting the binning fails.
+func (obj *Reservoir) Add(x float64) error {
+  if !isFinite(x) {
+    return nil
+  }
+  obj.n++
+  if len(obj.samples) < obj.size {
+    obj.samples = append(obj.samples, x)
+  } else
+  if i := obj.rng.Intn(obj.n); i < obj.size {
+    obj.samples[i] = x
+  }
+  if obj.added++; obj.added >= obj.refit {
+    return obj.fit()
+  }
+  return nil
+}
+
+// Number of samples seen
+func (obj *Reservoir) Count() int {
+  return obj.n
+}
+
+// Returns a copy of the samples in the reservoir
+func (obj *Reservoir) Samples() []float64 {
+  return append([]float64{}, obj.samples...)
+}
+
+// Fit a binning to the reservoir, where values are scaled to the number of
+// samples seen
+func (obj *Reservoir) fit() error {
+  binning, err := obj.strategy(obj.samples)
+  if err != nil {
+    return err
+  }
+  y := binning.AppendValues(nil)
+  for i := range y {
+    y[i] *= float64(obj.n)/float64(len(obj.samples))
+  }
+  if err := binning.Reset(binning.AppendBoundaries(nil), y); err != nil {
+    return err
+  }
+  obj.binning = binning
+  obj.added   = 0
+  return nil
+}
+
+// Returns the most recently fitted binning, where the value of each bin is
+// the estimated number of samples of the stream. The binning is fitted if
+// no fit is available yet. The binning is replaced, but not modified, by
+// subsequent refits.
+func (obj *Reservoir) Binning() (*Binning, error) {
+  if obj.binning == nil {
+    if len(obj.samples) == 0 {
+      return nil, fmt.Errorf("%w: no samples", ErrTooFewBoundaries)
+    }
+    if err := obj.fit(); err != nil {
+      return nil, err
+    }
+  }
+  return obj.binning, nil
+}
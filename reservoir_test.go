@@ -0,0 +1,61 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestReservoir1(t *testing.T) {
+
+  reservoir, err := NewReservoir(1000, 5000, EqualCountStrategy(4), 1)
+  if err != nil {
+    t.Error(err); return
+  }
+  if _, err := reservoir.Binning(); err == nil {
+    t.Error("test failed")
+  }
+  r := rand.New(rand.NewSource(1))
+  for i := 0; i < 100000; i++ {
+    reservoir.Add(r.Float64())
+  }
+  if reservoir.Count() != 100000 || len(reservoir.Samples()) != 1000 {
+    t.Error("test failed")
+  }
+  binning, err := reservoir.Binning()
+  if err != nil || len(binning.Bins) != 4 {
+    t.Error("test failed"); return
+  }
+  // quartiles of the uniform distribution
+  x := binning.AppendBoundaries(nil)
+  for i := 1; i < 4; i++ {
+    if math.Abs(x[i] - float64(i)/4) > 0.05 {
+      t.Error("test failed")
+    }
+  }
+  sum := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    sum += at.Y
+  }
+  if math.Abs(sum - 100000) > 1e-6 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,158 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Create a binning from raw samples, with one bin for each distinct value.
+// The value of each bin is the number of samples, the upper boundary of the
+// last bin is the next float64 after the largest sample. If all samples are
+// equal, an empty bin is appended. NaN samples are
+// counted by the missing bin if present (see WithMissingBin), all other
+// non-finite samples are ignored.
+func FromSamples(data []float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  return fromSamples(data, BinSum, less, options...)
+}
+
+// Same as FromSamples, but bin values are merged with sum
+func fromSamples(data []float64, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  v := make([]float64, 0, len(data))
+  m := 0.0
+  for _, x := range data {
+    if isFinite(x) {
+      v = append(v, x)
+    } else
+    if math.IsNaN(x) {
+      m++
+    }
+  }
+  sort.Float64s(v)
+  x := []float64{}
+  y := []float64{}
+  for i := range v {
+    if i == 0 || v[i] != v[i-1] {
+      x = append(x, v[i])
+      y = append(y, 0)
+    }
+    y[len(y)-1]++
+  }
+  if len(v) == 0 {
+    return nil, fmt.Errorf("%w: no finite samples", ErrTooFewBoundaries)
+  }
+  x = append(x, math.Nextafter(v[len(v)-1], math.Inf(1)))
+  if len(y) == 1 {
+    // constant samples
+    x = append(x, math.Nextafter(x[1], math.Inf(1)))
+    y = append(y, 0)
+  }
+  binning, err := New(x, y, sum, less, options...)
+  if err != nil {
+    return nil, err
+  }
+  if binning.Missing != nil {
+    binning.Missing.Y = m
+  }
+  return binning, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Strategy fits a binning to raw samples
+type Strategy func(data []float64) (*Binning, error)
+
+// Fit at most n bins, where the bin with the fewest samples is merged
+// first. The result has approximately equal counts.
+func EqualCountStrategy(n int, options ...Option) Strategy {
+  return func(data []float64) (*Binning, error) {
+    binning, err := FromSamples(data, BinLessY, options...)
+    if err != nil {
+      return nil, err
+    }
+    return binning, binning.FilterBins(n)
+  }
+}
+
+// Fit at most n bins, where the smallest bin is merged first. The result has
+// approximately equal widths.
+func EqualWidthStrategy(n int, options ...Option) Strategy {
+  return func(data []float64) (*Binning, error) {
+    binning, err := FromSamples(data, BinLessSize, options...)
+    if err != nil {
+      return nil, err
+    }
+    return binning, binning.FilterBins(n)
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Fit a binning to each column of data (given as rows of samples) using
+// strategy and return the matrix of bin indices together with all fitted
+// binnings. NaN values are assigned the index of the missing bin if present
+// (see WithMissingBin), all other non-finite values the index -1.
+func DiscretizeMatrix(data [][]float64, strategy Strategy) ([][]int, []*Binning, error) {
+  if len(data) == 0 {
+    return nil, nil, fmt.Errorf("%w: empty matrix", ErrInvalidArgument)
+  }
+  m := len(data[0])
+  for i := range data {
+    if len(data[i]) != m {
+      return nil, nil, fmt.Errorf("%w: row `%d'", ErrLengthMismatch, i)
+    }
+  }
+  binnings := make([]*Binning, m)
+  column   := make([]float64, len(data))
+  for j := 0; j < m; j++ {
+    for i := range data {
+      column[i] = data[i][j]
+    }
+    binning, err := strategy(column)
+    if err != nil {
+      return nil, nil, fmt.Errorf("column `%d': %w", j, err)
+    }
+    binnings[j] = binning
+  }
+  r := make([][]int, len(data))
+  for i := range data {
+    r[i] = make([]int, m)
+    for j := range data[i] {
+      r[i][j] = binnings[j].indexMissing(data[i][j])
+    }
+  }
+  return r, binnings, nil
+}
+
+// Position of the bin containing x or -1 if x is out of range
+func (binning *Binning) index(x float64) int {
+  if bin := binning.FindBin(x); bin != nil {
+    if binning.deleted == 0 {
+      return int(bin.index)
+    }
+    i := 0
+    for at := binning.First; at != bin; at = binning.Next(at) {
+      i++
+    }
+    return i
+  }
+  return -1
+}
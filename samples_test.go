@@ -0,0 +1,99 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestFromSamples1(t *testing.T) {
+
+  binning, err := FromSamples([]float64{3, 1, 2, 2, math.NaN(), 3, 3}, BinLessY)
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  if y := binning.AppendValues(nil); len(y) != 3 || y[0] != 1 || y[1] != 2 || y[2] != 3 {
+    t.Error("test failed")
+  }
+  if binning.FindBin(3) == nil {
+    t.Error("test failed")
+  }
+  if _, err := FromSamples([]float64{math.NaN()}, BinLessY); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestDiscretizeMatrix1(t *testing.T) {
+
+  data := [][]float64{}
+  for i := 0; i < 100; i++ {
+    data = append(data, []float64{float64(i), float64(i*i)})
+  }
+  data[5][1] = math.NaN()
+
+  r, binnings, err := DiscretizeMatrix(data, EqualCountStrategy(4))
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  if len(binnings) != 2 || len(binnings[0].Bins) != 4 || len(binnings[1].Bins) != 4 {
+    t.Error("test failed")
+  }
+  counts := [4]int{}
+  for i := range r {
+    counts[r[i][0]]++
+  }
+  for _, c := range counts {
+    if c < 10 || c > 40 {
+      t.Error("test failed")
+    }
+  }
+  if r[5][1] != -1 || r[0][1] != 0 || r[99][1] != 3 {
+    t.Error("test failed")
+  }
+  if _, _, err := DiscretizeMatrix([][]float64{{1, 2}, {1}}, EqualCountStrategy(4)); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestFromSamples2(t *testing.T) {
+
+  // constant samples
+  binning, err := FromSamples([]float64{2, 2, 2}, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if y := binning.AppendValues(nil); len(y) != 2 || y[0] != 3 || y[1] != 0 {
+    t.Error("test failed")
+  }
+  if bin := binning.FindBin(2); bin == nil || bin.Y != 3 {
+    t.Error("test failed")
+  }
+  data := [][]float64{}
+  for i := 0; i < 10; i++ {
+    data = append(data, []float64{float64(i), 1})
+  }
+  r, _, err := DiscretizeMatrix(data, EqualCountStrategy(4))
+  if err != nil {
+    t.Error(err); return
+  }
+  if r[0][1] != 0 || r[9][1] != 0 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,140 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package server exposes smart binning as a JSON HTTP API.
+package server
+
+/* -------------------------------------------------------------------------- */
+
+import "encoding/json"
+import "errors"
+import "fmt"
+import "net/http"
+
+import "github.com/pbenner/smartBinning"
+
+/* -------------------------------------------------------------------------- */
+
+// Request of POST /bin. Either samples or boundaries and values must be
+// given.
+type Request struct {
+  // raw samples, the initial binning has one bin per distinct value
+  Samples    []float64 `json:"samples,omitempty"`
+  // boundaries and values of an initial binning
+  Boundaries []float64 `json:"boundaries,omitempty"`
+  Values     []float64 `json:"values,omitempty"`
+  // maximum number of bins
+  Bins       int       `json:"bins"`
+  // merge bins with the smallest value (count) or width (width) first
+  Method     string    `json:"method,omitempty"`
+}
+
+type ResponseBin struct {
+  Lower float64 `json:"lower"`
+  Upper float64 `json:"upper"`
+  Y     float64 `json:"y"`
+}
+
+type Response struct {
+  Bins []ResponseBin `json:"bins"`
+}
+
+type errorResponse struct {
+  Error string `json:"error"`
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Server handles requests of the binning API
+type Server struct {
+  // maximum size of request bodies in bytes
+  MaxBytes int64
+  mux     *http.ServeMux
+}
+
+func New() *Server {
+  s := Server{MaxBytes: 32 << 20, mux: http.NewServeMux()}
+  s.mux.HandleFunc("/bin", s.handleBin)
+  return &s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+  s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleBin(w http.ResponseWriter, r *http.Request) {
+  if r.Method != http.MethodPost {
+    w.Header().Set("Allow", http.MethodPost)
+    writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"method not allowed"})
+    return
+  }
+  request := Request{}
+  decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.MaxBytes))
+  decoder.DisallowUnknownFields()
+  if err := decoder.Decode(&request); err != nil {
+    writeJSON(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("invalid request: %v", err)})
+    return
+  }
+  response, err := Bin(request)
+  if err != nil {
+    writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+    return
+  }
+  writeJSON(w, http.StatusOK, response)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+  w.Header().Set("Content-Type", "application/json")
+  w.WriteHeader(status)
+  json.NewEncoder(w).Encode(v)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the binning of a request
+func Bin(request Request) (Response, error) {
+  method := request.Method
+  if method == "" {
+    method = "count"
+  }
+  less, err := smartBinning.LookupLess(method)
+  if err != nil {
+    return Response{}, fmt.Errorf("invalid method `%s'", request.Method)
+  }
+  if request.Bins < 1 {
+    return Response{}, errors.New("number of bins must be positive")
+  }
+  var binning *smartBinning.Binning
+  switch {
+  case request.Samples != nil && request.Boundaries == nil:
+    binning, err = smartBinning.FromSamples(request.Samples, less)
+  case request.Samples == nil && request.Boundaries != nil:
+    binning, err = smartBinning.New(request.Boundaries, request.Values, smartBinning.BinSum, less)
+  default:
+    err = errors.New("either samples or boundaries must be given")
+  }
+  if err != nil {
+    return Response{}, err
+  }
+  if err := binning.FilterBins(request.Bins); err != nil {
+    return Response{}, err
+  }
+  response := Response{Bins: []ResponseBin{}}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    response.Bins = append(response.Bins, ResponseBin{at.Lower, at.Upper, at.Y})
+  }
+  return response, nil
+}
@@ -0,0 +1,78 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+/* -------------------------------------------------------------------------- */
+
+import   "encoding/json"
+import   "net/http"
+import   "net/http/httptest"
+import   "strings"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestServer1(t *testing.T) {
+
+  s := httptest.NewServer(New())
+  defer s.Close()
+
+  r, err := http.Post(s.URL + "/bin", "application/json", strings.NewReader(`{"samples": [1, 2, 2, 3, 10, 11], "bins": 2, "method": "width"}`))
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  defer r.Body.Close()
+  if r.StatusCode != http.StatusOK {
+    t.Error("test failed")
+  }
+  response := Response{}
+  if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+    t.Error("test failed")
+  }
+  if len(response.Bins) != 2 || response.Bins[0].Lower != 1 || response.Bins[0].Upper != 3 || response.Bins[0].Y != 3 {
+    t.Error("test failed")
+  }
+}
+
+func TestServer2(t *testing.T) {
+
+  s := httptest.NewServer(New())
+  defer s.Close()
+
+  for _, body := range []string{
+    `{"boundaries": [0, 1, 2], "values": [1, 2], "bins": 0}`,
+    `{"boundaries": [0, 1, 2], "samples": [1], "bins": 1}`,
+    `{"samples": [1], "bins": 1, "foo": 1}`,
+    `{"boundaries": [0, 1, 2], "values": [1, 2, 3], "bins": 1}` } {
+    r, err := http.Post(s.URL + "/bin", "application/json", strings.NewReader(body))
+    if err != nil {
+      t.Error("test failed"); return
+    }
+    r.Body.Close()
+    if r.StatusCode != http.StatusBadRequest {
+      t.Error("test failed")
+    }
+  }
+  r, err := http.Get(s.URL + "/bin")
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  r.Body.Close()
+  if r.StatusCode != http.StatusMethodNotAllowed {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,181 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "math/bits"
+
+/* -------------------------------------------------------------------------- */
+
+// Maximum number of skip list levels above the sorted linked list
+const skipMaxLevel = 32
+
+// Link of a bin at a skip list level above the sorted linked list, which
+// forms the lowest level (Smaller/Larger)
+type skipLink struct {
+  prev int32
+  next int32
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Build skip list levels from sorted bins. The structure is initially
+// perfectly balanced, i.e. the bin at position i is a member of level l if
+// i+1 is divisible by 2^l.
+func (binning *Binning) buildSkipList(bins []int32) {
+  n := len(bins)
+  m := 0
+  for i := 0; i < n; i++ {
+    m += skipHeight(i)
+  }
+  if cap(binning.skipLinks) >= m {
+    binning.skipLinks = binning.skipLinks[0:m]
+  } else {
+    binning.skipLinks = make([]skipLink, m)
+  }
+  for i, k := 0, 0; i < n; i++ {
+    binning.Bins[bins[i]].skip   = int32(k)
+    binning.Bins[bins[i]].height = int32(skipHeight(i))
+    k += skipHeight(i)
+  }
+  for i := range binning.skipLinks {
+    binning.skipLinks[i] = skipLink{noBin, noBin}
+  }
+  binning.skipHead = binning.skipHead[0:0]
+  for l, step := 0, 2; step-1 < n && l < skipMaxLevel; l, step = l+1, 2*step {
+    binning.skipHead = append(binning.skipHead, bins[step-1])
+    for i := step-1; i+step < n; i += step {
+      binning.skipLink(&binning.Bins[bins[i     ]], l).next = bins[i+step]
+      binning.skipLink(&binning.Bins[bins[i+step]], l).prev = bins[i]
+    }
+  }
+  binning.skipSeed = 0x9e3779b97f4a7c15
+}
+
+func skipHeight(i int) int {
+  h := bits.TrailingZeros(uint(i+1))
+  if h > skipMaxLevel {
+    h = skipMaxLevel
+  }
+  return h
+}
+
+// Draw the number of skip list levels for a bin (xorshift64*)
+func (binning *Binning) skipRandomHeight() int {
+  binning.skipSeed ^= binning.skipSeed >> 12
+  binning.skipSeed ^= binning.skipSeed << 25
+  binning.skipSeed ^= binning.skipSeed >> 27
+  h := bits.TrailingZeros64(binning.skipSeed * 2685821657736338717)
+  if h > len(binning.skipHead) {
+    h = len(binning.skipHead)+1
+  }
+  if h > skipMaxLevel {
+    h = skipMaxLevel
+  }
+  return h
+}
+
+func (binning *Binning) skipLink(bin *Bin, l int) *skipLink {
+  return &binning.skipLinks[int(bin.skip)+l]
+}
+
+func (binning *Binning) skipNext(at *Bin, l int) *Bin {
+  if at == nil {
+    return binning.bin(binning.skipHead[l])
+  }
+  return binning.bin(binning.skipLink(at, l).next)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (binning *Binning) skipDelete(bin *Bin) {
+  for l := 0; l < int(bin.height); l++ {
+    link := binning.skipLink(bin, l)
+    if link.prev != noBin {
+      binning.skipLink(&binning.Bins[link.prev], l).next = link.next
+    } else {
+      binning.skipHead[l] = link.next
+    }
+    if link.next != noBin {
+      binning.skipLink(&binning.Bins[link.next], l).prev = link.prev
+    }
+    *link = skipLink{noBin, noBin}
+  }
+  // drop empty levels
+  for n := len(binning.skipHead); n > 0 && binning.skipHead[n-1] == noBin; n-- {
+    binning.skipHead = binning.skipHead[0:n-1]
+  }
+}
+
+// Insert bin into the sorted list at the first position where the next
+// bin is not smaller
+func (binning *Binning) skipInsert(bin *Bin) {
+  h := binning.skipRandomHeight()
+  if int(bin.height) < h {
+    // allocate new links
+    bin.skip = int32(len(binning.skipLinks))
+    for i := 0; i < h; i++ {
+      binning.skipLinks = append(binning.skipLinks, skipLink{noBin, noBin})
+    }
+  }
+  bin.height = int32(h)
+  for len(binning.skipHead) < h {
+    binning.skipHead = append(binning.skipHead, noBin)
+  }
+  var at *Bin
+  // search position on upper levels
+  for l := len(binning.skipHead)-1; l >= 0; l-- {
+    next := binning.skipNext(at, l)
+    for next != nil && binning.less(next, bin) {
+      at, next = next, binning.skipNext(next, l)
+    }
+    if l < h {
+      link := binning.skipLink(bin, l)
+      link.prev = noBin
+      link.next = noBin
+      if at != nil {
+        link.prev = at.index
+        binning.skipLink(at, l).next = bin.index
+      } else {
+        binning.skipHead[l] = bin.index
+      }
+      if next != nil {
+        link.next = next.index
+        binning.skipLink(next, l).prev = bin.index
+      }
+    }
+  }
+  // search position on the sorted linked list
+  next := binning.Smallest
+  if at != nil {
+    next = binning.Larger(at)
+  }
+  for next != nil && binning.less(next, bin) {
+    at, next = next, binning.Larger(next)
+  }
+  switch {
+  case at != nil:
+    binning.insertBinSortedAfter(bin, at)
+  case next != nil:
+    binning.insertBinSortedBefore(bin, next)
+  default:
+    bin.smaller, bin.larger = noBin, noBin
+    binning.Smallest = bin
+    binning.Largest  = bin
+  }
+}
@@ -0,0 +1,85 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func checkSkipList(t *testing.T, binning *Binning) {
+  // position of bins in the sorted list
+  pos := make(map[*Bin]int)
+  for at := binning.Smallest; at != nil; at = at.Larger() {
+    if at.Larger() != nil && at.Larger().Smaller() != at {
+      t.Error("test failed")
+    }
+    pos[at] = len(pos)
+  }
+  for l := range binning.skipHead {
+    at := binning.skipNext(nil, l)
+    if at == nil || binning.skipLink(at, l).prev != noBin {
+      t.Error("test failed"); return
+    }
+    for ; at != nil; at = binning.skipNext(at, l) {
+      if at.Deleted {
+        t.Error("test failed")
+      }
+      if next := binning.skipNext(at, l); next != nil {
+        if binning.skipLink(next, l).prev != at.index || pos[next] <= pos[at] {
+          t.Error("test failed")
+        }
+      }
+    }
+  }
+}
+
+func TestSkipList1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 1001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+  }
+  binning, _ := New(x, nil, BinSum, BinLessSize)
+  checkSkipList(t, binning)
+
+  for i := 0; i < 900; i++ {
+    if i % 2 == 0 {
+      binning.Delete(binning.Smallest)
+    } else {
+      // delete random bin
+      at := binning.First
+      for k := r.Intn(1000-i-1); k > 0; k-- {
+        at = at.Next()
+      }
+      binning.Delete(at)
+    }
+  }
+  checkSkipList(t, binning)
+
+  binning.Update()
+  binning.FilterBins(10)
+  checkSkipList(t, binning)
+
+  if len(binning.Bins) != 10 {
+    t.Error("test failed")
+  }
+}
@@ -20,20 +20,99 @@ package smartBinning
 
 import "fmt"
 import "bytes"
+import "context"
 import "math"
 import "sort"
 
 /* -------------------------------------------------------------------------- */
 
+// Position used for links that do not point to any bin
+const noBin = int32(-1)
+
+// Number of merges after which long running operations check if their
+// context was canceled
+const checkInterval = 1024
+
+/* -------------------------------------------------------------------------- */
+
 type Bin struct {
   Y        float64
   Lower    float64
   Upper    float64
-  Next    *Bin
-  Prev    *Bin
-  Smaller *Bin
-  Larger  *Bin
   Deleted  bool
+  // position of the bin in Binning.Bins
+  index    int32
+  // links in the linked list and the sorted linked list
+  // as positions in Binning.Bins
+  next     int32
+  prev     int32
+  smaller  int32
+  larger   int32
+  // links at upper skip list levels, given as offset and
+  // number of levels in Binning.skipLinks
+  skip     int32
+  height   int32
+  // position of the bin in a binHeap
+  heapIndex int32
+  // cached value of the key function
+  key      float64
+  // number of original bins merged into this bin
+  merged   int32
+  // bin was modified and is not a member of the sorted list
+  dirty    bool
+  // value must be computed (see WithLazyValues)
+  stale    bool
+  // payload combined by the aggregator (see WithAggregator)
+  Data     interface{}
+  // binning the bin belongs to (see Bin.Next)
+  binning  *Binning
+}
+
+// Next bin in the linked list or nil if bin is the last bin.
+//
+// Deprecated: use Binning.Next
+func (bin *Bin) Next() *Bin {
+  if bin.binning == nil {
+    return nil
+  }
+  return bin.binning.Next(bin)
+}
+
+// Previous bin in the linked list or nil if bin is the first bin.
+//
+// Deprecated: use Binning.Prev
+func (bin *Bin) Prev() *Bin {
+  if bin.binning == nil {
+    return nil
+  }
+  return bin.binning.Prev(bin)
+}
+
+// Next smaller bin in the sorted linked list or nil if bin is the smallest
+// bin.
+//
+// Deprecated: use Binning.Smaller
+func (bin *Bin) Smaller() *Bin {
+  if bin.binning == nil {
+    return nil
+  }
+  return bin.binning.Smaller(bin)
+}
+
+// Next larger bin in the sorted linked list or nil if bin is the largest
+// bin.
+//
+// Deprecated: use Binning.Larger
+func (bin *Bin) Larger() *Bin {
+  if bin.binning == nil {
+    return nil
+  }
+  return bin.binning.Larger(bin)
+}
+
+// Number of original bins that were merged into this bin
+func (bin Bin) MergedCount() int {
+  return int(bin.merged)
 }
 
 func (bin Bin) Size() float64 {
@@ -63,8 +142,8 @@ func (bins binList) Swap(i, j int) {
 /* -------------------------------------------------------------------------- */
 
 type binListSorted struct {
-  bins []*Bin
-  less func(Bin, Bin) bool
+  binning *Binning
+  bins    []int32
 }
 
 func (obj binListSorted) Len() int {
@@ -72,7 +151,7 @@ func (obj binListSorted) Len() int {
 }
 
 func (obj binListSorted) Less(i, j int) bool {
-  return obj.less(*obj.bins[i], *obj.bins[j])
+  return obj.binning.less(&obj.binning.Bins[obj.bins[i]], &obj.binning.Bins[obj.bins[j]])
 }
 
 func (obj binListSorted) Swap(i, j int) {
@@ -97,7 +176,7 @@ func BinLogSum(a, b Bin) float64 {
   x, y :=  a.Y, b.Y
   if x > y {
     // swap
-    x, y = x, y
+    x, y = y, x
   }
   if math.IsInf(x, -1) {
     return y
@@ -107,278 +186,853 @@ func BinLogSum(a, b Bin) float64 {
 
 /* -------------------------------------------------------------------------- */
 
-func lessWrapper(less func(Bin, Bin) bool, a, b Bin) bool {
-  if !less(a, b) && !less(b, a) {
-    // bins are equal, check neighbors
-    c := a.Prev
-    d := b.Prev
-    if c == nil {
-      c = a.Next
-    } else {
-      if a.Next != nil {
-        if less(*a.Next, *c) {
-          c = a.Next
-        }
-      }
-    }
-    if d == nil {
-      d = b.Next
-    } else {
-      if b.Next != nil {
-        if less(*b.Next, *d) {
-          d = b.Next
-        }
-      }
-    }
-    if c != nil && d != nil {
-      return less(*c, *d)
-    }
-  }
-  return less(a, b)
-}
-
-/* -------------------------------------------------------------------------- */
-
 type Binning struct {
   Bins      binList
   Sum       func(Bin, Bin) float64
+  // less function given to New, where equal bins are ordered by their
+  // neighbors according to the tie-breaking rule (see WithTieBreaking)
   Less      func(Bin, Bin) bool
+  // less function given to New or comparison of keys (see WithKey)
+  lessFunc  func(Bin, Bin) bool
   First    *Bin
   Last     *Bin
   Smallest *Bin
   Largest  *Bin
-  Insert   *Bin
-  Verbose   bool
+  options   []Option
+  config      config
+  // buffer for sorting bins
+  order     []int32
+  // modified bins
+  dirty     []int32
+  // number of deleted bins in Bins
+  deleted     int
+  // length of the domain if the binning is circular
+  period      float64
+  // first bins at upper skip list levels
+  skipHead  []int32
+  skipLinks []skipLink
+  skipSeed    uint64
+  // buffer for skip links used by Compact
+  skipSpare []skipLink
+  // bins are allocated from an arena (see WithArena)
+  arena       bool
+  // observers of changes (see Subscribe)
+  subscribers []subscriber
+  subscriberId int
+  // clock for decaying values (see WithDecay)
+  time        float64
+  // log of merges (see WithUndo)
+  undo        []mergeRecord
+  // innermost active transaction (see Begin)
+  tx          *Tx
+  // range minimum and maximum queries (see WithRangeIndex)
+  ranges      *rangeIndex
+  // prefix sums of values (see WithPrefixIndex)
+  prefixes    *prefixIndex
+  // bin of missing (NaN) observations, which is not merged with other
+  // bins (see WithMissingBin)
+  Missing  *Bin
+  // initial bins and sequence of merges (see WithMergeTrace)
+  trace       *mergeTrace
+  // direction of the next merge if non-zero, where negative values merge
+  // with the left neighbor (see ReplayTrace)
+  force       int8
 }
 
-func New(x, y []float64, sum func(Bin, Bin) float64, less func(Bin, Bin) bool) (*Binning, error) {
+func New(x, y []float64, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  binning := Binning{}
+  binning.Sum  = sum
+  binning.Less = binning.lessWrapper
+  binning.lessFunc = less
+  binning.options  = options
+  for _, option := range options {
+    option(&binning.config)
+  }
+  if binning.config.key != nil {
+    binning.lessFunc = binLessKey
+  }
+  if binning.config.yfunc != nil {
+    binning.Sum = binSumLazy
+  }
+  if a := binning.config.aggregator; a != nil {
+    binning.Sum = func(l, r Bin) float64 {
+      return a.Merge(l, r).Y
+    }
+  }
+  if binning.config.rangeIndex {
+    binning.ranges = &rangeIndex{}
+  }
+  if binning.config.prefixIndex {
+    binning.prefixes = &prefixIndex{}
+  }
+  if binning.config.missing {
+    binning.Missing = &Bin{Lower: math.NaN(), Upper: math.NaN(), index: noBin}
+  }
+  if err := binning.Reset(x, y); err != nil {
+    return nil, err
+  }
+  return &binning, nil
+}
+
+// Initialize the binning with new boundaries x and values y. The memory of
+// the binning is reused if possible, all pointers to bins become invalid.
+func (binning *Binning) Reset(x, y []float64) error {
   n := len(x)-1
 
   if n < 2 {
-    return nil, fmt.Errorf("length of x must be greater than two")
+    return ErrTooFewBoundaries
   }
-  binning := Binning{}
-  binning.Bins = make(binList, n)
-  binning.Sum  = sum
-  binning.Less = func(a, b Bin) bool { return lessWrapper(less, a, b) }
-  bins := make([]*Bin, n)
+  if len(y) > 1 && len(y) != n {
+    return ErrLengthMismatch
+  }
+  x, y, err := binning.filterNonFinite(x, y)
+  if err != nil {
+    return err
+  }
+  n = len(x)-1
+  upper := x[n]
+  binning.allocate(n)
+  binning.indexInvalidate()
+  binning.dirty   = binning.dirty[0:0]
+  binning.undo    = binning.undo[0:0]
+  binning.deleted = 0
 
   // set lower boundaries
   for i := 0; i < n; i++ {
-    binning.Bins[i].Lower = x[i]
+    binning.Bins[i] = Bin{Lower: x[i]}
   }
   // set y
   switch len(y) {
   case 0:
+    if binning.config.yfunc != nil {
+      for i := 0; i < n; i++ {
+        binning.Bins[i].Y     = math.NaN()
+        binning.Bins[i].stale = true
+      }
+    }
   case 1:
     for i := 0; i < n; i++ {
       binning.Bins[i].Y = y[0]
     }
   default:
-    if len(y) != n {
-      return nil, fmt.Errorf("y vector has invalid length")
-    }
     for i := 0; i < n; i++ {
       binning.Bins[i].Y = y[i]
     }
   }
   // get bins in the right order
-  sort.Sort(binning.Bins)
+  if !sort.IsSorted(binning.Bins) {
+    if p := binning.config.parallelism; p > 1 {
+      parallelSortBins(binning.Bins, p)
+    } else {
+      sort.Sort(binning.Bins)
+    }
+  }
+  // detect zero-width bins
+  if binning.config.duplicates != AllowDuplicates {
+    if err := binning.coalesceDuplicates(upper); err != nil {
+      return err
+    }
+    n = len(binning.Bins)
+  }
+  binning.progress(1, constructionSteps)
   // set upper boundaries
   for i := 0; i < n-1; i++ {
     binning.Bins[i].Upper = binning.Bins[i+1].Lower
   }
-  binning.Bins[n-1].Upper = x[n]
-  // create linked lists
-  for i := 0; i < n-1; i++ {
-    binning.Bins[i].Next = &binning.Bins[i+1]
+  binning.Bins[n-1].Upper = upper
+  if binning.config.circular {
+    binning.period = upper - binning.Bins[0].Lower
   }
-  for i := 1; i < n; i++ {
-    binning.Bins[i].Prev = &binning.Bins[i-1]
+  binning.link()
+  binning.traceReset()
+  binning.log("construct", "bins", len(binning.Bins))
+  binning.emit(Event{Kind: EventReset, Bins: len(binning.Bins)})
+
+  return binning.check("construction")
+}
+
+// Compute cached keys and create the linked and sorted lists of all bins,
+// which must be ordered by their boundaries
+func (binning *Binning) link() {
+  n    := len(binning.Bins)
+  bins := binning.order[0:n]
+  // compute cached keys
+  if binning.config.key != nil {
+    parallelFor(n, binning.config.parallelism, func(i int) {
+      binning.updateKey(&binning.Bins[i])
+    })
   }
+  // create linked lists
+  parallelFor(n, binning.config.parallelism, func(i int) {
+    binning.Bins[i].binning = binning
+    binning.Bins[i].index = int32(i)
+    binning.Bins[i].next  = int32(i+1)
+    binning.Bins[i].prev  = int32(i-1)
+    bins[i] = int32(i)
+  })
+  binning.Bins[n-1].next = noBin
   binning.First = &binning.Bins[0]
   binning.Last  = &binning.Bins[n-1]
-  // create a binList and sort the elements
-  for i := 0; i < n; i++ {
-    bins[i] = &binning.Bins[i]
+  binning.progress(2, constructionSteps)
+  // sort bins using the less function
+  if p := binning.config.parallelism; p > 1 {
+    parallelSort(binListSorted{binning, bins}, p)
+  } else {
+    sort.Sort(binListSorted{binning, bins})
   }
-  sort.Sort(binListSorted{bins, binning.Less})
+  parallelFor(n, binning.config.parallelism, func(i int) {
+    binning.Bins[bins[i]].smaller = noBin
+    binning.Bins[bins[i]].larger  = noBin
+    if i > 0 {
+      binning.Bins[bins[i]].smaller = bins[i-1]
+    }
+    if i < n-1 {
+      binning.Bins[bins[i]].larger  = bins[i+1]
+    }
+  })
+  binning.Smallest = &binning.Bins[bins[0]]
+  binning.Largest  = &binning.Bins[bins[n-1]]
+  binning.progress(3, constructionSteps)
+  binning.buildSkipList(bins)
+  binning.progress(4, constructionSteps)
+}
 
-  for i := 0; i < len(bins)-1; i++ {
-    bins[i].Larger = bins[i+1]
+/* -------------------------------------------------------------------------- */
+
+func (binning *Binning) bin(i int32) *Bin {
+  if i == noBin {
+    return nil
   }
-  for i := 1; i < len(bins); i++ {
-    bins[i].Smaller = bins[i-1]
+  return &binning.Bins[i]
+}
+
+// Next bin in the linked list or nil if bin is the last bin
+func (binning *Binning) Next(bin *Bin) *Bin {
+  return binning.bin(bin.next)
+}
+
+// Previous bin in the linked list or nil if bin is the first bin
+func (binning *Binning) Prev(bin *Bin) *Bin {
+  return binning.bin(bin.prev)
+}
+
+// Next smaller bin in the sorted linked list or nil if bin is the smallest
+// bin
+func (binning *Binning) Smaller(bin *Bin) *Bin {
+  return binning.bin(bin.smaller)
+}
+
+// Next larger bin in the sorted linked list or nil if bin is the largest
+// bin
+func (binning *Binning) Larger(bin *Bin) *Bin {
+  return binning.bin(bin.larger)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compare bins using the less function of the binning. Ties are resolved
+// according to the tie-breaking rule (see WithTieBreaking), which defines
+// a total order on all bins.
+func (binning *Binning) less(a, b *Bin) bool {
+  if r := binning.compare(a, b); r != 0 {
+    return r < 0
+  }
+  if binning.config.ties == TieBreakRightmost {
+    return a.index > b.index
   }
-  binning.Smallest = bins[0]
-  binning.Largest  = bins[n-1]
+  return a.index < b.index
+}
 
-  return &binning, nil
+// Compare copies of bins, where equal bins are ordered by their neighbors
+// (see compare)
+func (binning *Binning) lessWrapper(a, b Bin) bool {
+  return binning.compare(&a, &b) < 0
 }
 
-func (binning *Binning) deleteBin(bin *Bin) *Bin {
+// Compare bins using the less function of the binning and return -1 if a
+// is smaller than b, +1 if b is smaller than a and zero otherwise. Bins
+// that are equal are compared by their smallest neighbors if the default
+// tie-breaking rule is used.
+func (binning *Binning) compare(a, b *Bin) int {
+  less := binning.lessFunc
+  if less(*a, *b) {
+    return -1
+  }
+  if less(*b, *a) {
+    return 1
+  }
+  if binning.config.ties != TieBreakNeighbors {
+    return 0
+  }
+  // bins are equal, check neighbors
+  c := binning.Prev(a)
+  d := binning.Prev(b)
+  if c == nil {
+    c = binning.Next(a)
+  } else {
+    if next := binning.Next(a); next != nil {
+      if less(*next, *c) {
+        c = next
+      }
+    }
+  }
+  if d == nil {
+    d = binning.Next(b)
+  } else {
+    if next := binning.Next(b); next != nil {
+      if less(*next, *d) {
+        d = next
+      }
+    }
+  }
+  if c != nil && d != nil {
+    if less(*c, *d) {
+      return -1
+    }
+    if less(*d, *c) {
+      return 1
+    }
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (binning *Binning) mergeBin(bin *Bin) *Bin {
+  prev := binning.Prev(bin)
+  next := binning.Next(bin)
   // delete from linked list
-  if bin.Prev != nil && bin.Next != nil {
-    bin.Prev.Next = bin.Next
-    bin.Next.Prev = bin.Prev
+  if prev != nil && next != nil {
+    prev.next = bin.next
+    next.prev = bin.prev
   } else {
-    if bin.Prev != nil {
+    if prev != nil {
       // deleting last bin
-      bin.Prev.Next = nil
-      binning.Last = bin.Prev
+      prev.next = noBin
+      binning.Last = prev
     }
-    if bin.Next != nil {
+    if next != nil {
       // deleting first bin
-      bin.Next.Prev = nil
-      binning.First = bin.Next
+      next.prev = noBin
+      binning.First = next
     }
   }
-  // delete from sorted linked list
-  binning.deleteBinSorted(bin)
   // mark bin as deleted
   bin.Deleted = true
+  binning.deleted++
+  deleted := bin
+  // remember state of neighbors for undoing the merge
+  var left, right *Bin
+  var l, r, d Bin
+  if binning.config.undo > 0 {
+    left, right = binning.undoCandidates(prev, next)
+    if left != nil {
+      l = *left
+      l.Data = cloneData(l.Data)
+    }
+    if right != nil {
+      r = *right
+      r.Data = cloneData(r.Data)
+    }
+    d = *deleted
+    d.Data = cloneData(d.Data)
+  }
   // merge bin data
-  if bin.Prev == nil {
+  if binning.period > 0 && (prev == nil || next == nil) {
+    // first and last bins are neighbors on a circular domain
+    bin = binning.mergeCircular(bin, prev, next)
+  } else
+  if prev == nil {
     // there is no bin to the left, merge
     // with bin on the right
-    bin.Next.Y     = binning.Sum(*bin.Next, *bin)
-    bin.Next.Lower = bin.Lower
-    bin = bin.Next
+    binning.absorb(next, bin, false)
+    next.Lower = bin.Lower
+    bin = next
   } else
-  if bin.Next == nil {
+  if next == nil {
     // there is no bin to the right, merge
     // with bin on the left
-    bin.Prev.Y     = binning.Sum(*bin.Prev, *bin)
-    bin.Prev.Upper = bin.Upper
-    bin = bin.Prev
+    binning.absorb(prev, bin, true)
+    prev.Upper = bin.Upper
+    bin = prev
   } else {
     // merge bin with smaller bin around
-    if binning.Less(*bin.Prev, *bin.Next) {
+    if binning.mergeLeft(prev, next) {
       // merge with bin to the left
-      bin.Prev.Y     = binning.Sum(*bin.Prev, *bin)
-      bin.Prev.Upper = bin.Upper
-      bin = bin.Prev
+      binning.absorb(prev, bin, true)
+      prev.Upper = bin.Upper
+      bin = prev
     } else {
       // merge with bin to the right
-      bin.Next.Y     = binning.Sum(*bin.Next, *bin)
-      bin.Next.Lower = bin.Lower
-      bin = bin.Next
+      binning.absorb(next, bin, false)
+      next.Lower = bin.Lower
+      bin = next
     }
   }
+  bin.merged += deleted.merged + 1
+  if binning.config.yfunc != nil {
+    bin.Y     = math.NaN()
+    bin.stale = true
+  }
+  binning.updateKey(bin)
+  binning.indexUpdate(deleted)
+  binning.indexUpdate(bin)
+  if binning.config.undo > 0 {
+    binning.recordMerge(&d, bin, prev, next, left, right, l, r)
+  }
+  if binning.trace != nil {
+    binning.traceMerge(deleted, bin, prev, next)
+  }
+  binning.notifyMerge(deleted, bin)
+  return bin
+}
+
+// Log the merge of deleted into bin and notify subscribers
+func (binning *Binning) notifyMerge(deleted, bin *Bin) {
+  if binning.config.logger != nil {
+    binning.log("merge",
+      "lower", deleted.Lower, "upper", deleted.Upper, "y", deleted.Y,
+      "into_lower", bin.Lower, "into_upper", bin.Upper, "into_y", bin.Y)
+  }
+  if len(binning.subscribers) > 0 {
+    binning.emit(Event{Kind: EventMerge, Bin: *bin, Deleted: *deleted})
+  }
+}
+
+func (binning *Binning) deleteBin(bin *Bin) *Bin {
+  // delete from sorted linked list
+  binning.deleteBinSorted(bin)
+  // delete from linked list and merge with neighbor
+  bin = binning.mergeBin(bin)
   binning.deleteBinSorted(bin)
   return bin
 }
 
 func (binning *Binning) deleteBinSorted(bin *Bin) {
-  if bin.Smaller != nil && bin.Larger != nil {
-    bin.Smaller.Larger = bin.Larger
-    bin.Larger.Smaller = bin.Smaller
+  if bin.dirty {
+    // bin is not a member of the sorted list
+    return
+  }
+  binning.skipDelete(bin)
+  smaller := binning.Smaller(bin)
+  larger  := binning.Larger (bin)
+  if smaller != nil && larger != nil {
+    smaller.larger = bin.larger
+    larger.smaller = bin.smaller
   } else {
-    if bin.Smaller != nil {
+    if smaller != nil {
       // deleting largest bin
-      bin.Smaller.Larger = nil
-      binning.Largest = bin.Smaller
+      smaller.larger = noBin
+      binning.Largest = smaller
     }
-    if bin.Larger != nil {
+    if larger != nil {
       // deleting smallest bin
-      bin.Larger.Smaller = nil
-      binning.Smallest = bin.Larger
+      larger.smaller = noBin
+      binning.Smallest = larger
+    }
+    if smaller == nil && larger == nil {
+      // deleting the only bin
+      binning.Smallest = nil
+      binning.Largest  = nil
     }
   }
+  bin.smaller = noBin
+  bin.larger  = noBin
 }
 
 func (binning *Binning) insertBinSortedBefore(bin, at *Bin) {
-  if at.Smaller == nil {
+  if at.smaller == noBin {
     binning.Smallest = bin
   } else {
-    at.Smaller.Larger = bin
+    binning.Bins[at.smaller].larger = bin.index
   }
-  bin.Smaller = at.Smaller
-  bin.Larger  = at
-  at.Smaller  = bin
+  bin.smaller = at.smaller
+  bin.larger  = at.index
+  at.smaller  = bin.index
 }
 
 func (binning *Binning) insertBinSortedAfter(bin, at *Bin) {
-  if at.Larger == nil {
+  if at.larger == noBin {
     binning.Largest = bin
   } else {
-    at.Larger.Smaller = bin
+    binning.Bins[at.larger].smaller = bin.index
   }
-  bin.Smaller = at
-  bin.Larger  = at.Larger
-  at.Larger   = bin
+  bin.smaller = at.index
+  bin.larger  = at.larger
+  at.larger   = bin.index
 }
 
-func (binning *Binning) Delete(bin *Bin) {
-  if bin.Prev == nil && bin.Next == nil {
-    return
+// Delete a bin by merging it with one of its neighbors (see WithMergeRule)
+// and return the resulting bin. An error is returned if the bin does not
+// belong to this binning, if it is already deleted or if it is the only
+// bin.
+func (binning *Binning) Delete(bin *Bin) (*Bin, error) {
+  if !binning.owns(bin) {
+    return nil, ErrForeignBin
+  }
+  if bin.Deleted {
+    return nil, ErrBinDeleted
+  }
+  if bin.prev == noBin && bin.next == noBin {
+    return nil, fmt.Errorf("%w: cannot delete the only bin", ErrInvalidArgument)
   }
   // delete bin from linked list
   bin = binning.deleteBin(bin)
-  // save next largest bin as current position
-  at := bin.Larger
   // insert bin into sorted list
-  if at == nil {
-    // there is no larger bin, insert after largest
-    binning.insertBinSortedAfter(bin, binning.Largest)
-  } else {
-    // check if the last insert position is feasible
-    if binning.Insert != nil && binning.Less(*binning.Insert, *bin) {
-      at = binning.Insert
+  if !bin.dirty {
+    binning.skipInsert(bin)
+  }
+  if err := binning.check("delete"); err != nil {
+    return nil, err
+  }
+  return bin, nil
+}
+
+// Returns true if bin is an active (not deleted) bin of this binning. Compact
+// (and hence Update and FilterBins) moves bins within the backing slice, so
+// that a pointer obtained before compaction is either reported as foreign or
+// refers to the bin that now occupies the same position.
+func (binning *Binning) Contains(bin *Bin) bool {
+  return binning.owns(bin) && !bin.Deleted
+}
+
+// Returns true if bin is an element of the backing slice
+func (binning *Binning) owns(bin *Bin) bool {
+  return bin != nil && bin.index >= 0 && int(bin.index) < len(binning.Bins) && &binning.Bins[bin.index] == bin
+}
+
+// Delete a set of bins. All bins are first merged with their neighbors
+// and afterwards the resulting bins are inserted into the sorted list,
+// which saves repositioning bins that are merged several times. An error
+// is returned and no bin is deleted if any bin does not belong to this
+// binning, if it is already deleted or given twice, or if all bins would
+// be deleted.
+func (binning *Binning) DeleteAll(bins []*Bin) error {
+  seen := make(map[int32]bool)
+  for _, bin := range bins {
+    if !binning.owns(bin) {
+      return ErrForeignBin
     }
-    // find new position for the bin
-    for at.Larger != nil && binning.Less(*at, *bin) {
-      at = at.Larger
+    if bin.Deleted || seen[bin.index] {
+      return ErrBinDeleted
     }
-    if binning.Less(*bin, *at) {
-      binning.insertBinSortedBefore(bin, at)
-    } else {
-      binning.insertBinSortedAfter(bin, at)
+    seen[bin.index] = true
+  }
+  if len(bins) > 0 && len(seen) >= len(binning.Bins) - binning.deleted {
+    return fmt.Errorf("%w: cannot delete all bins", ErrInvalidArgument)
+  }
+  binning.deleteAll(bins)
+  return binning.check("delete")
+}
+
+func (binning *Binning) deleteAll(bins []*Bin) {
+  detached  := make(map[int32]bool)
+  survivors := []*Bin{}
+  detach := func(bin *Bin) {
+    if !detached[bin.index] && !bin.dirty {
+      binning.deleteBinSorted(bin)
+      detached[bin.index] = true
+    }
+  }
+  for _, bin := range bins {
+    if bin.Deleted || (bin.prev == noBin && bin.next == noBin) {
+      continue
+    }
+    detach(bin)
+    bin = binning.mergeBin(bin)
+    if !detached[bin.index] {
+      detach(bin)
+      survivors = append(survivors, bin)
+    }
+  }
+  // insert resulting bins into sorted list
+  for _, bin := range survivors {
+    if !bin.Deleted && !bin.dirty {
+      binning.skipInsert(bin)
     }
-    binning.Insert = at
   }
 }
 
+// Insert all modified bins into the sorted list and remove deleted bins
+// from the backing slice. Only positions of modified bins are updated,
+// which requires O(m log n) operations for m modified bins. All pointers to
+// bins become invalid.
 func (binning *Binning) Update() error {
-  // get new values
-  x := []float64{}
-  y := []float64{}
-  for t := binning.First; t != nil; t = t.Next {
-    if t.Deleted {
-      // this shouldn't happen
-      panic("internal error")
+  binning.reinsert()
+  binning.Compact()
+  return binning.check("update")
+}
+
+// Notify the binning that the value of a bin was modified. The bin is
+// removed from the sorted list until the next call to Update. An error is
+// returned if the bin does not belong to this binning or if it is deleted.
+func (binning *Binning) Modified(bin *Bin) error {
+  if !binning.owns(bin) {
+    return ErrForeignBin
+  }
+  if bin.Deleted {
+    return ErrBinDeleted
+  }
+  binning.modified(bin)
+  binning.undo = binning.undo[0:0]
+  binning.traceInvalidate()
+  binning.emit(Event{Kind: EventUpdate, Bin: *bin})
+  return binning.check("modification")
+}
+
+// Move a bin to its position in the sorted list after its value was
+// modified. Contrary to Modified, the bin is reinserted immediately.
+//
+// Deprecated: use Modified and Update
+func (binning *Binning) Insert(bin *Bin) error {
+  if !binning.owns(bin) {
+    return ErrForeignBin
+  }
+  if bin.Deleted {
+    return ErrBinDeleted
+  }
+  binning.reposition(bin)
+  binning.undo = binning.undo[0:0]
+  binning.traceInvalidate()
+  binning.emit(Event{Kind: EventUpdate, Bin: *bin})
+  return binning.check("insertion")
+}
+
+func (binning *Binning) modified(bin *Bin) {
+  binning.indexUpdate(bin)
+  if !bin.dirty {
+    binning.deleteBinSorted(bin)
+    bin.dirty = true
+    binning.dirty = append(binning.dirty, bin.index)
+  }
+}
+
+// Insert all modified bins into the sorted list
+func (binning *Binning) reinsert() {
+  for _, i := range binning.dirty {
+    if bin := &binning.Bins[i]; !bin.Deleted && bin.dirty {
+      bin.dirty = false
+      binning.updateKey(bin)
+      binning.skipInsert(bin)
     }
-    x = append(x, t.Lower)
-    y = append(y, t.Y)
   }
-  x = append(x, binning.Bins[len(binning.Bins)-1].Upper)
+  binning.dirty = binning.dirty[0:0]
+}
 
-  if tmp, err := New(x, y, binning.Sum, binning.Less); err != nil {
-    return err
-  } else {
-    *binning = *tmp
+// Remove deleted bins from the backing slice. Contrary to Update, the
+// order of the sorted list is retained and no sorting is required. The
+// memory of deleted bins is released if most bins are deleted, unless it
+// is taken from a pool (see WithPool). All pointers to bins become invalid.
+func (binning *Binning) Compact() {
+  // assign new positions to active bins, which are
+  // stored in the same order as in the linked list
+  n := int32(0)
+  m := int32(0)
+  for i := 0; i < len(binning.Bins); i++ {
+    if !binning.Bins[i].Deleted {
+      binning.Bins[i].index = n; n++
+      m += binning.Bins[i].height
+    }
+  }
+  position := func(i int32) int32 {
+    if i == noBin {
+      return noBin
+    }
+    return binning.Bins[i].index
   }
-  return nil
+  // translate links before bins are moved, skip links are copied to the
+  // spare buffer, since they are not ordered like bins
+  links := binning.skipSpare[0:0]
+  if cap(links) < int(m) {
+    links = make([]skipLink, 0, m)
+  }
+  for i := 0; i < len(binning.Bins); i++ {
+    bin := &binning.Bins[i]
+    if bin.Deleted {
+      continue
+    }
+    bin.next    = position(bin.next)
+    bin.prev    = position(bin.prev)
+    bin.smaller = position(bin.smaller)
+    bin.larger  = position(bin.larger)
+    skip := int32(len(links))
+    for l := int32(0); l < bin.height; l++ {
+      link := binning.skipLinks[bin.skip+l]
+      links = append(links, skipLink{prev: position(link.prev), next: position(link.next)})
+    }
+    bin.skip = skip
+  }
+  for l, i := range binning.skipHead {
+    binning.skipHead[l] = position(i)
+  }
+  dirty := binning.dirty[0:0]
+  for _, i := range binning.dirty {
+    if !binning.Bins[i].Deleted {
+      dirty = append(dirty, position(i))
+    }
+  }
+  smallest, largest := noBin, noBin
+  if binning.Smallest != nil {
+    smallest, largest = binning.Smallest.index, binning.Largest.index
+  }
+  // move active bins to the front of the backing slice
+  j := 0
+  for i := 0; i < len(binning.Bins); i++ {
+    if !binning.Bins[i].Deleted {
+      if i != j {
+        binning.Bins[j] = binning.Bins[i]
+      }
+      j++
+    }
+  }
+  for i := j; i < len(binning.Bins); i++ {
+    // release payloads
+    binning.Bins[i] = Bin{}
+  }
+  binning.dirty     = dirty
+  binning.indexInvalidate()
+  binning.Bins      = binning.Bins[0:n]
+  binning.deleted   = 0
+  binning.skipSpare = binning.skipLinks[0:0]
+  binning.skipLinks = links
+  // memory of pools is reused
+  shrink := binning.config.pool == nil
+  if binning.arena || shrink && 4*len(binning.Bins) < cap(binning.Bins) {
+    // move bins to the heap, so that the arena can be released, or
+    // release memory of deleted bins
+    binning.Bins  = append(binList(nil), binning.Bins...)
+    binning.order = nil
+    binning.arena = false
+  }
+  if shrink && (4*len(links) < cap(links) || 4*len(links) < cap(binning.skipSpare)) {
+    binning.skipLinks = append([]skipLink(nil), links...)
+    binning.skipSpare = nil
+  }
+  binning.First     = &binning.Bins[0]
+  binning.Last      = &binning.Bins[n-1]
+  binning.Smallest  = binning.bin(smallest)
+  binning.Largest   = binning.bin(largest)
 }
 
 func (binning *Binning) FilterBins(n int) error {
+  return binning.FilterBinsContext(context.Background(), n)
+}
+
+// Same as FilterBins, but stops merging bins when the context is canceled.
+// In this case the binning is left in a consistent state with all merges
+// performed so far and the error of the context is returned.
+func (binning *Binning) FilterBinsContext(ctx context.Context, n int) error {
   if len(binning.Bins) == 0 || len(binning.Bins) < n {
     return nil
   }
-  m := len(binning.Bins) - n
+  // reinsert modified bins, which are not in the sorted list
+  binning.reinsert()
+  k := len(binning.Bins) - binning.deleted
+  m := k - n
   for i := 0; i < m; i++ {
-    binning.Delete(binning.Smallest)
+    if i % checkInterval == 0 {
+      if ctx.Err() != nil {
+        break
+      }
+      binning.progress(i, m)
+    }
+    if binning.Smallest == nil {
+      break
+    }
+    if _, err := binning.Delete(binning.Smallest); err != nil {
+      return err
+    }
+  }
+  binning.Compact()
+  binning.log("filter", "method", "FilterBins", "bins", len(binning.Bins), "merges", k-len(binning.Bins))
+  binning.emit(Event{Kind: EventFilter, Bins: len(binning.Bins)})
+  if ctx.Err() == nil {
+    binning.progress(m, m)
+  }
+  return ctx.Err()
+}
+
+// Same as FilterBins, but in each pass the k smallest bins that are not
+// neighbors of each other are deleted at once, before the order of the
+// resulting bins is updated. The result is in general not identical to
+// FilterBins.
+func (binning *Binning) FilterBinsBatched(n, k int) error {
+  return binning.FilterBinsBatchedContext(context.Background(), n, k)
+}
+
+// Same as FilterBinsBatched, but stops merging bins when the context is
+// canceled (see FilterBinsContext)
+func (binning *Binning) FilterBinsBatchedContext(ctx context.Context, n, k int) error {
+  if k < 1 {
+    return fmt.Errorf("%w: batch size must be positive", ErrInvalidArgument)
+  }
+  m := 0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    m++
+  }
+  if m <= n {
+    return nil
+  }
+  binning.reinsert()
+  total := m-n
+  for m > n && m > 1 && ctx.Err() == nil {
+    binning.progress(total-(m-n), total)
+    selected := make(map[int32]bool)
+    bins     := []*Bin{}
+    for at := binning.Smallest; at != nil && len(bins) < k && len(bins) < m-n; at = binning.Larger(at) {
+      if selected[at.prev] || selected[at.next] {
+        continue
+      }
+      selected[at.index] = true
+      bins = append(bins, at)
+    }
+    if len(bins) == 0 {
+      break
+    }
+    binning.deleteAll(bins)
+    if err := binning.check("delete"); err != nil {
+      return err
+    }
+    m -= len(bins)
+  }
+  binning.Compact()
+  binning.log("filter", "method", "FilterBinsBatched", "bins", len(binning.Bins), "merges", total-(m-n))
+  binning.emit(Event{Kind: EventFilter, Bins: len(binning.Bins)})
+  if ctx.Err() == nil {
+    binning.progress(total, total)
+  }
+  return ctx.Err()
+}
+
+// Append the boundaries of all bins to dst, i.e. the lower boundaries of
+// all bins followed by the upper boundary of the last bin
+func (binning *Binning) AppendBoundaries(dst []float64) []float64 {
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    dst = append(dst, at.Lower)
+  }
+  if binning.Last != nil {
+    dst = append(dst, binning.Last.Upper)
+  }
+  return dst
+}
+
+// Append the values of all bins to dst. Values are computed if necessary
+// (see WithLazyValues).
+func (binning *Binning) AppendValues(dst []float64) []float64 {
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    dst = append(dst, binning.Value(at))
   }
-  return binning.Update()
+  return dst
 }
 
 func (binning *Binning) String() string {
   var buffer bytes.Buffer
-  for at := binning.First; at != nil; at = at.Next {
+  for at := binning.First; at != nil; at = binning.Next(at) {
     if at != binning.First {
       fmt.Fprintf(&buffer, " ")
     }
     if !at.Deleted {
+      binning.Value(at)
       fmt.Fprintf(&buffer, "%v", at)
     }
   }
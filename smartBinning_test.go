@@ -19,6 +19,10 @@ package smartBinning
 /* -------------------------------------------------------------------------- */
 
 //import   "fmt"
+import   "context"
+import   "errors"
+import   "math"
+import   "math/rand"
 import   "testing"
 
 /* -------------------------------------------------------------------------- */
@@ -87,3 +91,399 @@ func Test1(t *testing.T) {
     t.Error("test failed")
   }
 }
+
+func Test2(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 1001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+  }
+  binning, _ := New(x, nil, BinSum, BinLessSize)
+  binning.FilterBins(20)
+
+  if len(binning.Bins) != 20 {
+    t.Error("test failed")
+  }
+  n := 0
+  for at := binning.Smallest; at != nil; at = at.Larger() {
+    if at.Larger() != nil && at.Larger().Size() < at.Size() {
+      t.Error("test failed")
+    }
+    if at.Deleted {
+      t.Error("test failed")
+    }
+    n++
+  }
+  if n != 20 || binning.Largest != &binning.Bins[binning.Largest.index] {
+    t.Error("test failed")
+  }
+  for i := 1; i < len(binning.Bins); i++ {
+    if binning.Bins[i].Prev() != &binning.Bins[i-1] || binning.Bins[i].Lower != binning.Bins[i-1].Upper {
+      t.Error("test failed")
+    }
+  }
+}
+
+func Test3(t *testing.T) {
+
+  x := []float64{-100,-99,1,2,3,6,8,19,21,120,300,350,355,380}
+  y := []float64{1,2,3,4,5,6,7,8,9,10,11,12,13}
+
+  binning, _ := New(x, y, BinSum, BinLessSize)
+  binning.Delete(&binning.Bins[0])
+  binning.Delete(&binning.Bins[len(binning.Bins)-1])
+  binning.Delete(&binning.Bins[5])
+  binning.Compact()
+
+  if len(binning.Bins) != 10 {
+    t.Error("test failed")
+  }
+  if binning.First.Lower != -100 || binning.First.Upper != 1 || binning.First.Y != 3 {
+    t.Error("test failed")
+  }
+  if binning.Last.Lower != 350 || binning.Last.Upper != 380 || binning.Last.Y != 25 {
+    t.Error("test failed")
+  }
+  if binning.Largest.Lower != 120 {
+    t.Error("test failed")
+  }
+  if binning.Smallest.Lower != 1 {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+}
+
+func Test4(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 1001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+  }
+  b1, _ := New(x, nil, BinSum, BinLessSize)
+  b2, _ := New(x, nil, BinSum, BinLessSize)
+
+  bins := []*Bin{}
+  for i := 0; i < len(b1.Bins); i++ {
+    if b1.Bins[i].Size() < 0.5 {
+      bins = append(bins, &b1.Bins[i])
+    }
+  }
+  if err := b1.DeleteAll(bins); err != nil {
+    t.Error(err); return
+  }
+
+  for _, bin := range bins {
+    if bin := &b2.Bins[bin.index]; !bin.Deleted {
+      b2.Delete(bin)
+    }
+  }
+  checkSkipList(t, b1)
+
+  for at1, at2 := b1.First, b2.First; at1 != nil || at2 != nil; at1, at2 = b1.Next(at1), b2.Next(at2) {
+    if at1 == nil || at2 == nil || at1.Lower != at2.Lower || at1.Upper != at2.Upper {
+      t.Error("test failed"); break
+    }
+  }
+  n := 0
+  for at := b1.Smallest; at != nil; at = b1.Larger(at) {
+    n++
+  }
+  b1.Compact()
+  if n != len(b1.Bins) {
+    t.Error("test failed")
+  }
+}
+
+func Test5(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 1001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+  }
+  b1, _ := New(x, nil, BinSum, BinLessSize)
+  b2, _ := New(x, nil, BinSum, BinLessSize)
+  b1.FilterBins(20)
+  b2.FilterBinsBatched(20, 10)
+
+  if len(b2.Bins) != 20 {
+    t.Error("test failed")
+  }
+  if b2.First.Lower != 0 || b2.Last.Upper != x[1000] {
+    t.Error("test failed")
+  }
+  checkSkipList(t, b2)
+
+  // compare smallest bins
+  if r := b2.Smallest.Size()/b1.Smallest.Size(); r < 0.5 || r > 2.0 {
+    t.Error("test failed")
+  }
+}
+
+func Test6(t *testing.T) {
+
+  x := []float64{-100,-99,1,2,3,6,8,19,21,120,300,350,355,380}
+  y := []float64{1,2,3,4,5,6,7,8,9,10,11,12,13}
+
+  binning, _ := New(x, y, BinSum, BinLessY)
+
+  binning.Bins[3].Y = 100
+  binning.Modified(&binning.Bins[3])
+  binning.Bins[7].Y = 0
+  binning.Modified(&binning.Bins[7])
+  // merge with modified neighbor
+  binning.Delete(&binning.Bins[8])
+  binning.Update()
+
+  if len(binning.Bins) != 12 {
+    t.Error("test failed")
+  }
+  if binning.Largest.Lower != 2 || binning.Largest.Y != 100 {
+    t.Error("test failed")
+  }
+  if binning.Smallest.Lower != -100 {
+    t.Error("test failed")
+  }
+  for at := binning.Smallest; binning.Larger(at) != nil; at = binning.Larger(at) {
+    if binning.Larger(at).Y < at.Y {
+      t.Error("test failed")
+    }
+  }
+  checkSkipList(t, binning)
+}
+
+func Test7(t *testing.T) {
+
+  x := []float64{-100,-99,1,2,3,6,8,19,21,120,300,350,355,380}
+  y := []float64{1,2,3,4,5,6,7,8,9,10,11,12,13}
+
+  binning, _ := New(x, y, BinSum, BinLessSize)
+  binning.FilterBins(5)
+
+  bx := make([]float64, 0, 6)
+  by := make([]float64, 0, 5)
+  n  := testing.AllocsPerRun(10, func() {
+    bx = binning.AppendBoundaries(bx[0:0])
+    by = binning.AppendValues    (by[0:0])
+  })
+  if n != 0 {
+    t.Error("test failed")
+  }
+  if len(bx) != 6 || bx[0] != -100 || bx[5] != 380 {
+    t.Error("test failed")
+  }
+  if len(by) != 5 || by[0] != 3 {
+    t.Error("test failed")
+  }
+}
+
+func Test8(t *testing.T) {
+
+  x := make([]float64, 10001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + float64(i % 7 + 1)
+  }
+  ctx, cancel := context.WithCancel(context.Background())
+  cancel()
+
+  binning, _ := New(x, nil, BinSum, BinLessSize)
+  if err := binning.FilterBinsContext(ctx, 10); err != context.Canceled {
+    t.Error("test failed")
+  }
+  if len(binning.Bins) != 10000 {
+    t.Error("test failed")
+  }
+  if err := binning.FilterBinsHeapContext(ctx, 10); err != context.Canceled {
+    t.Error("test failed")
+  }
+  if err := binning.FilterBinsHeapContext(context.Background(), 10); err != nil {
+    t.Error("test failed")
+  }
+  if len(binning.Bins) != 10 {
+    t.Error("test failed")
+  }
+}
+
+func Test9(t *testing.T) {
+
+  x := []float64{-100, 0, 80, 120, 220, 380}
+  y := []float64{3, 4, 7, 1, 2}
+
+  binning1, _ := New(x, y, BinSum, BinLessSize)
+  binning2, _ := New(x, y, BinSum, BinLessSize)
+
+  r, err := binning1.Delete(&binning1.Bins[1])
+  if err != nil || r != &binning1.Bins[2] || r.Lower != 0 || r.Upper != 120 || r.Y != 11 {
+    t.Error("test failed")
+  }
+  if _, err := binning1.Delete(&binning1.Bins[1]); !errors.Is(err, ErrBinDeleted) {
+    t.Error("test failed")
+  }
+  if _, err := binning1.Delete(&binning2.Bins[1]); !errors.Is(err, ErrForeignBin) {
+    t.Error("test failed")
+  }
+  if _, err := binning1.Delete(&Bin{}); !errors.Is(err, ErrForeignBin) {
+    t.Error("test failed")
+  }
+  if err := binning1.FilterBins(1); err != nil {
+    t.Error("test failed")
+  }
+  if _, err := binning1.Delete(binning1.First); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+}
+
+func Test10(t *testing.T) {
+
+  x := []float64{-100, 0, 80, 120, 220, 380}
+  y := []float64{3, 4, 7, 1, 2}
+
+  binning1, _ := New(x, y, BinSum, BinLessSize)
+  binning2, _ := New(x, y, BinSum, BinLessSize)
+
+  bin := &binning1.Bins[4]
+  if !binning1.Contains(bin) || binning2.Contains(bin) || binning1.Contains(nil) {
+    t.Error("test failed")
+  }
+  binning1.Delete(&binning1.Bins[1])
+  if binning1.Contains(&binning1.Bins[1]) || !binning1.Contains(bin) {
+    t.Error("test failed")
+  }
+  binning1.Update()
+  if binning1.Contains(bin) {
+    t.Error("test failed")
+  }
+}
+
+func Test11(t *testing.T) {
+
+  x := make([]float64, 1001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + float64(i % 7 + 1)
+  }
+  binning, _ := New(x, nil, BinSum, BinLessSize)
+  binning.FilterBins(10)
+
+  n := 0
+  for _, bin := range binning.Bins {
+    n += bin.MergedCount() + 1
+  }
+  if n != 1000 {
+    t.Error("test failed")
+  }
+  binning.FilterBins(1)
+  if binning.Bins[0].MergedCount() != 999 {
+    t.Error("test failed")
+  }
+}
+
+func Test12(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 3, 4}, []float64{1, 3, 1, 2}, BinSum, BinLessY)
+
+  // equal bins are ordered by their smallest neighbors
+  a, b := binning.Bins[0], binning.Bins[2]
+  if binning.Less(a, b) || !binning.Less(b, a) {
+    t.Error("test failed")
+  }
+  if binning.Smallest != &binning.Bins[2] || binning.Smallest.Larger() != &binning.Bins[0] {
+    t.Error("test failed")
+  }
+  if binning.First.Prev() != nil || binning.First.Next() != &binning.Bins[1] || binning.Last.Next() != nil {
+    t.Error("test failed")
+  }
+  // reinsert modified bin
+  binning.Bins[1].Y = 0
+  if err := binning.Insert(&binning.Bins[1]); err != nil {
+    t.Error(err); return
+  }
+  if binning.Smallest != &binning.Bins[1] || binning.Smallest.Smaller() != nil {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+  other, _ := New([]float64{0, 1, 2}, nil, BinSum, BinLessY)
+  if err := binning.Insert(other.First); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func Test13(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4}
+  y := []float64{1, 3, 1, 2}
+
+  b1, _ := New(x, y, BinSum, BinLessY)
+  b2, _ := New(x, y, BinSum, BinLessY)
+  s := b1.String()
+
+  if err := b1.DeleteAll([]*Bin{&b1.Bins[0], &b2.Bins[2]}); !errors.Is(err, ErrForeignBin) {
+    t.Error("test failed")
+  }
+  if err := b1.DeleteAll([]*Bin{&b1.Bins[0], &b1.Bins[0]}); !errors.Is(err, ErrBinDeleted) {
+    t.Error("test failed")
+  }
+  if err := b1.DeleteAll([]*Bin{&b1.Bins[0], &b1.Bins[1], &b1.Bins[2], &b1.Bins[3]}); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+  if b1.String() != s {
+    t.Error("test failed")
+  }
+  checkSkipList(t, b1)
+  bin := &b1.Bins[0]
+  if err := b1.DeleteAll([]*Bin{bin}); err != nil {
+    t.Error(err); return
+  }
+  if err := b1.DeleteAll([]*Bin{bin}); !errors.Is(err, ErrBinDeleted) {
+    t.Error("test failed")
+  }
+  checkSkipList(t, b1)
+  checkSkipList(t, b2)
+}
+
+func Test14(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4, 5}
+  y := []float64{5, 4, 3, 2, 1}
+  z := []float64{1, 2, 3, 4, 5}
+
+  for _, filter := range []func(*Binning) error {
+    func(b *Binning) error { return b.FilterBins(2) },
+    func(b *Binning) error { return b.FilterBinsBatched(2, 2) } } {
+    b1, _ := New(x, y, BinSum, BinLessY)
+    b2, _ := New(x, z, BinSum, BinLessY)
+    // modify all bins without calling Update
+    for i := range b1.Bins {
+      b1.Bins[i].Y = z[i]
+      if err := b1.Modified(&b1.Bins[i]); err != nil {
+        t.Error(err); return
+      }
+    }
+    if err := filter(b1); err != nil {
+      t.Error(err); continue
+    }
+    filter(b2)
+    if v1, v2 := b1.AppendValues(nil), b2.AppendValues(nil); len(v1) != 2 || v1[0] != v2[0] || v1[1] != v2[1] {
+      t.Error("test failed")
+    }
+    checkSkipList(t, b1)
+  }
+}
+
+func TestLogSum1(t *testing.T) {
+
+  a := Bin{Y: math.Log(2.0)}
+  b := Bin{Y: math.Log(3.0)}
+  c := Bin{Y: math.Inf(-1)}
+
+  if math.Abs(BinLogSum(a, b) - math.Log(5.0)) > 1e-12 {
+    t.Error("test failed")
+  }
+  if math.Abs(BinLogSum(b, a) - math.Log(5.0)) > 1e-12 {
+    t.Error("test failed")
+  }
+  if BinLogSum(b, c) != b.Y || BinLogSum(c, b) != b.Y {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,133 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "container/heap"
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// BinSource returns bins as lower boundaries x with values y in increasing
+// order of x. After the last bin ok is false.
+type BinSource func() (x, y float64, ok bool, err error)
+
+// Source that returns the bins given by lower boundaries x and values y
+func SliceSource(x, y []float64) BinSource {
+  i := 0
+  return func() (float64, float64, bool, error) {
+    if i >= len(x) {
+      return 0, 0, false, nil
+    }
+    if len(y) != len(x) {
+      return 0, 0, false, ErrLengthMismatch
+    }
+    i++
+    return x[i-1], y[i-1], true, nil
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+type sourceHead struct {
+  source BinSource
+  x      float64
+  y      float64
+}
+
+// Heap of sources ordered by their next lower boundary
+type sourceHeap []sourceHead
+
+func (h sourceHeap) Len() int {
+  return len(h)
+}
+
+func (h sourceHeap) Less(i, j int) bool {
+  return h[i].x < h[j].x
+}
+
+func (h sourceHeap) Swap(i, j int) {
+  h[i], h[j] = h[j], h[i]
+}
+
+func (h *sourceHeap) Push(x interface{}) {
+  *h = append(*h, x.(sourceHead))
+}
+
+func (h *sourceHeap) Pop() interface{} {
+  n := len(*h)
+  r := (*h)[n-1]
+  *h = (*h)[0:n-1]
+  return r
+}
+
+// Advance the source at the top of the heap and return its current head
+func (h *sourceHeap) advance() (sourceHead, error) {
+  head := (*h)[0]
+  x, y, ok, err := head.source()
+  if err != nil {
+    return head, err
+  }
+  if !ok {
+    heap.Pop(h)
+    return head, nil
+  }
+  if x < head.x {
+    return head, fmt.Errorf("%w: source returned `%f' after `%f'", ErrUnsorted, x, head.x)
+  }
+  (*h)[0].x = x
+  (*h)[0].y = y
+  heap.Fix(h, 0)
+  return head, nil
+}
+
+// Create a binning from several sorted sources in a single pass, e.g. from
+// the outputs of several shards. Lower boundaries of all sources are merged,
+// where values of equal boundaries are combined with sum. The upper boundary
+// of the last bin is given by upper.
+func MergeSources(sources []BinSource, upper float64, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  h := sourceHeap{}
+  for _, source := range sources {
+    x, y, ok, err := source()
+    if err != nil {
+      return nil, err
+    }
+    if ok {
+      h = append(h, sourceHead{source, x, y})
+    }
+  }
+  heap.Init(&h)
+  x := []float64{}
+  y := []float64{}
+  for h.Len() > 0 {
+    head, err := h.advance()
+    if err != nil {
+      return nil, err
+    }
+    if n := len(x); n > 0 && x[n-1] == head.x {
+      y[n-1] = sum(Bin{Y: y[n-1]}, Bin{Y: head.y})
+    } else {
+      x = append(x, head.x)
+      y = append(y, head.y)
+    }
+  }
+  if n := len(x); n > 0 && x[n-1] >= upper {
+    return nil, fmt.Errorf("%w: upper boundary `%f'", ErrUnsorted, upper)
+  }
+  return New(append(x, upper), y, sum, less, options...)
+}
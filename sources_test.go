@@ -0,0 +1,46 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "errors"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestMergeSources1(t *testing.T) {
+
+  sources := []BinSource{
+    SliceSource([]float64{0, 3, 6}, []float64{1, 1, 1}),
+    SliceSource([]float64{1, 3, 7}, []float64{2, 2, 2}),
+    SliceSource(nil, nil) }
+
+  binning, err := MergeSources(sources, 10, BinSum, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if binning.String() != "[0.000000, 1.000000):1 [1.000000, 3.000000):2 [3.000000, 6.000000):3 [6.000000, 7.000000):1 [7.000000, 10.000000):2" {
+    t.Error("test failed")
+  }
+  sources = []BinSource{
+    SliceSource([]float64{0, 3, 2}, []float64{1, 1, 1}),
+    SliceSource([]float64{1, 3, 7}, []float64{2, 2, 2}) }
+  if _, err := MergeSources(sources, 10, BinSum, BinLessY); !errors.Is(err, ErrUnsorted) {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,200 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "encoding/csv"
+import "encoding/json"
+import "fmt"
+import "io"
+import "strconv"
+
+/* -------------------------------------------------------------------------- */
+
+// Declarative description of a binning step, e.g. loaded from an experiment
+// configuration file with LoadConfig or a YAML decoder. Functions are
+// selected by their names in the registry (see RegisterLess).
+type Config struct {
+  // less function, defaults to "count"
+  Method    string       `json:"method,omitempty"    yaml:"method,omitempty"`
+  // cost function (see WithKey), overrides the less function if given
+  Cost      string       `json:"cost,omitempty"      yaml:"cost,omitempty"`
+  // sum function, defaults to "sum"
+  Sum       string       `json:"sum,omitempty"       yaml:"sum,omitempty"`
+  // target number of bins
+  Bins      int          `json:"bins,omitempty"      yaml:"bins,omitempty"`
+  // minimum value of each bin
+  MinValue  float64      `json:"min_value,omitempty" yaml:"min_value,omitempty"`
+  // relative tolerance of bins with equal values (see FilterBinsBalanced)
+  Tolerance float64      `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+  // binning of a circular domain (see WithCircular)
+  Circular  bool         `json:"circular,omitempty"  yaml:"circular,omitempty"`
+  Output    OutputConfig `json:"output,omitempty"    yaml:"output,omitempty"`
+}
+
+// Output options of a pipeline
+type OutputConfig struct {
+  // "csv" (default) or "json"
+  Format string `json:"format,omitempty" yaml:"format,omitempty"`
+  // "y" (default) writes bin values, "normalized" values divided by their
+  // total and "density" normalized values divided by bin widths
+  Values string `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+// Read a JSON configuration, unknown fields are rejected
+func LoadConfig(reader io.Reader) (Config, error) {
+  spec    := Config{}
+  decoder := json.NewDecoder(reader)
+  decoder.DisallowUnknownFields()
+  if err := decoder.Decode(&spec); err != nil {
+    return Config{}, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+  }
+  return spec, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Pipeline is a binning step created from a configuration
+type Pipeline struct {
+  Config      Config
+  sum         func(Bin, Bin) float64
+  less        func(Bin, Bin) bool
+  options     []Option
+}
+
+// Create a pipeline from a configuration, all names and options are
+// validated
+func FromSpec(spec Config) (*Pipeline, error) {
+  p := Pipeline{Config: spec}
+  if spec.Method == "" {
+    spec.Method = "count"
+  }
+  if spec.Sum == "" {
+    spec.Sum = "sum"
+  }
+  var err error
+  if p.sum, err = LookupSum(spec.Sum); err != nil {
+    return nil, err
+  }
+  if p.less, err = LookupLess(spec.Method); err != nil {
+    return nil, err
+  }
+  if spec.Cost != "" {
+    cost, err := LookupCost(spec.Cost)
+    if err != nil {
+      return nil, err
+    }
+    p.options = append(p.options, WithKey(cost))
+  }
+  if spec.Circular {
+    p.options = append(p.options, WithCircular())
+  }
+  if spec.Bins < 0 {
+    return nil, fmt.Errorf("%w: number of bins `%d'", ErrInvalidArgument, spec.Bins)
+  }
+  if spec.Tolerance < 0 || (spec.Tolerance > 0 && spec.Bins == 0) {
+    return nil, fmt.Errorf("%w: tolerance `%f'", ErrInvalidArgument, spec.Tolerance)
+  }
+  switch spec.Output.Format {
+  case "", "csv", "json":
+  default:
+    return nil, fmt.Errorf("%w: output format `%s'", ErrInvalidArgument, spec.Output.Format)
+  }
+  switch spec.Output.Values {
+  case "", "y", "normalized", "density":
+  default:
+    return nil, fmt.Errorf("%w: output values `%s'", ErrInvalidArgument, spec.Output.Values)
+  }
+  return &p, nil
+}
+
+// Create a binning from bin boundaries x and values y and merge bins until
+// all constraints are satisfied
+func (p *Pipeline) Run(x, y []float64) (*Binning, error) {
+  return NewBuilder().
+    Boundaries(x).
+    Values(y).
+    Strategy(p.sum, p.less).
+    Constraints(p.constraints()).
+    Options(p.options...).
+    Build()
+}
+
+// Same as Run, but the binning is created from raw samples (see
+// FromSamples)
+func (p *Pipeline) Fit(data []float64) (*Binning, error) {
+  binning, err := fromSamples(data, p.sum, p.less, p.options...)
+  if err != nil {
+    return nil, err
+  }
+  if err := binning.constrain(p.constraints()); err != nil {
+    return nil, err
+  }
+  return binning, nil
+}
+
+func (p *Pipeline) constraints() Constraints {
+  return Constraints{MaxBins: p.Config.Bins, MinValue: p.Config.MinValue, Tolerance: p.Config.Tolerance}
+}
+
+// Returns the pipeline as a strategy for raw samples
+func (p *Pipeline) Strategy() Strategy {
+  return p.Fit
+}
+
+/* -------------------------------------------------------------------------- */
+
+type outputBin struct {
+  Lower float64 `json:"lower"`
+  Upper float64 `json:"upper"`
+  Y     float64 `json:"y"`
+}
+
+// Write bins of a binning according to the output options
+func (p *Pipeline) Write(writer io.Writer, binning *Binning) error {
+  total := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    total += at.Y
+  }
+  bins := []outputBin{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    y := at.Y
+    switch p.Config.Output.Values {
+    case "normalized":
+      y /= total
+    case "density":
+      y /= total*at.Size()
+    }
+    bins = append(bins, outputBin{at.Lower, at.Upper, y})
+  }
+  switch p.Config.Output.Format {
+  case "json":
+    return json.NewEncoder(writer).Encode(bins)
+  default:
+    w := csv.NewWriter(writer)
+    w.Write([]string{"lower", "upper", "y"})
+    for _, bin := range bins {
+      w.Write([]string{
+        strconv.FormatFloat(bin.Lower, 'g', -1, 64),
+        strconv.FormatFloat(bin.Upper, 'g', -1, 64),
+        strconv.FormatFloat(bin.Y,     'g', -1, 64) })
+    }
+    w.Flush()
+    return w.Error()
+  }
+}
@@ -0,0 +1,99 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "bytes"
+import   "errors"
+import   "strings"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestSpec1(t *testing.T) {
+
+  spec, err := LoadConfig(strings.NewReader(`{"method": "size", "bins": 3, "output": {"values": "normalized"}}`))
+  if err != nil {
+    t.Error(err); return
+  }
+  p, err := FromSpec(spec)
+  if err != nil {
+    t.Error(err); return
+  }
+  binning, err := p.Run([]float64{0, 1, 2, 4, 8}, []float64{3, 1, 2, 2})
+  if err != nil {
+    t.Error(err); return
+  }
+  var buffer bytes.Buffer
+  if err := p.Write(&buffer, binning); err != nil {
+    t.Error(err); return
+  }
+  if buffer.String() != "lower,upper,y\n0,2,0.5\n2,4,0.25\n4,8,0.25\n" {
+    t.Error("test failed")
+  }
+}
+
+func TestSpec2(t *testing.T) {
+
+  p, err := FromSpec(Config{Bins: 2})
+  if err != nil {
+    t.Error(err); return
+  }
+  binning, err := p.Strategy()([]float64{1, 1, 2, 3, 3, 3})
+  if err != nil {
+    t.Error(err); return
+  }
+  if r := binning.AppendValues(nil); len(r) != 2 || r[0]+r[1] != 6 {
+    t.Error("test failed")
+  }
+  if _, err := FromSpec(Config{Method: "foo"}); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+  if _, err := LoadConfig(strings.NewReader(`{"foo": 1}`)); !errors.Is(err, ErrInvalidFormat) {
+    t.Error("test failed")
+  }
+}
+
+type maxAggregator struct{}
+
+func (maxAggregator) Merge(a, b Bin) Bin {
+  if a.Y > b.Y {
+    return Bin{Y: a.Y}
+  }
+  return Bin{Y: b.Y}
+}
+
+func TestSpec3(t *testing.T) {
+
+  p, err := FromSpec(Config{Bins: 2})
+  if err != nil {
+    t.Error(err); return
+  }
+  // the sum function is derived from the aggregator
+  p.options = append(p.options, WithAggregator(maxAggregator{}))
+  binning, err := p.Fit([]float64{1, 1, 2, 3, 3, 3})
+  if err != nil {
+    t.Error(err); return
+  }
+  if binning.Sum(Bin{Y: 1}, Bin{Y: 2}) != 2 {
+    t.Error("test failed")
+  }
+  if r := binning.AppendValues(nil); len(r) != 2 || r[0] != 2 || r[1] != 3 {
+    t.Error("test failed")
+  }
+}
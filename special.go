@@ -0,0 +1,219 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Regularized lower incomplete gamma function P(a, x)
+func gammaP(a, x float64) float64 {
+  switch {
+  case x <= 0:
+    return 0.0
+  case math.IsInf(x, 1):
+    return 1.0
+  case x < a+1:
+    return gammaSeries(a, x)
+  default:
+    return 1.0 - gammaContinuedFraction(a, x)
+  }
+}
+
+// Series representation of P(a, x)
+func gammaSeries(a, x float64) float64 {
+  lg, _ := math.Lgamma(a)
+  s := 1.0/a
+  t := s
+  for n := 1; n < 1000; n++ {
+    t *= x/(a+float64(n))
+    s += t
+    if math.Abs(t) < math.Abs(s)*1e-16 {
+      break
+    }
+  }
+  return s*math.Exp(-x + a*math.Log(x) - lg)
+}
+
+// Continued fraction representation of Q(a, x) = 1 - P(a, x) (modified
+// Lentz's method)
+func gammaContinuedFraction(a, x float64) float64 {
+  const tiny = 1e-300
+  lg, _ := math.Lgamma(a)
+  b := x + 1 - a
+  c := 1.0/tiny
+  d := 1.0/b
+  h := d
+  for n := 1; n < 1000; n++ {
+    an := -float64(n)*(float64(n)-a)
+    b += 2
+    d  = an*d + b
+    if math.Abs(d) < tiny {
+      d = tiny
+    }
+    c = b + an/c
+    if math.Abs(c) < tiny {
+      c = tiny
+    }
+    d  = 1.0/d
+    h *= d*c
+    if math.Abs(d*c-1) < 1e-16 {
+      break
+    }
+  }
+  return h*math.Exp(-x + a*math.Log(x) - lg)
+}
+
+// Quantile of the gamma distribution with shape a and unit scale
+func gammaQuantile(p, a float64) float64 {
+  if p <= 0 {
+    return 0.0
+  }
+  if p >= 1 {
+    return math.Inf(1)
+  }
+  lo, hi := 0.0, math.Max(1, a)
+  for gammaP(a, hi) < p {
+    lo, hi = hi, 2*hi
+  }
+  for i := 0; i < 200 && hi-lo > 1e-14*hi; i++ {
+    if m := lo + (hi-lo)/2; gammaP(a, m) < p {
+      lo = m
+    } else {
+      hi = m
+    }
+  }
+  return lo + (hi-lo)/2
+}
+
+// Quantile of the standard normal distribution
+func normalQuantile(p float64) float64 {
+  return math.Sqrt2*math.Erfinv(2*p-1)
+}
+
+// Distribution function of the standard normal distribution
+func normalCdf(x float64) float64 {
+  return 0.5*math.Erfc(-x/math.Sqrt2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Regularized incomplete beta function I_x(a, b)
+func betaI(a, b, x float64) float64 {
+  if x <= 0 {
+    return 0.0
+  }
+  if x >= 1 {
+    return 1.0
+  }
+  la, _ := math.Lgamma(a)
+  lb, _ := math.Lgamma(b)
+  lc, _ := math.Lgamma(a+b)
+  f := math.Exp(lc - la - lb + a*math.Log(x) + b*math.Log1p(-x))
+  // use the continued fraction where it converges rapidly
+  if x < (a+1)/(a+b+2) {
+    return f*betaContinuedFraction(a, b, x)/a
+  }
+  return 1.0 - f*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// Continued fraction for the incomplete beta function (modified Lentz's
+// method)
+func betaContinuedFraction(a, b, x float64) float64 {
+  const tiny = 1e-300
+  c := 1.0
+  d := 1.0 - (a+b)*x/(a+1)
+  if math.Abs(d) < tiny {
+    d = tiny
+  }
+  d = 1.0/d
+  h := d
+  for m := 1; m < 1000; m++ {
+    fm := float64(m)
+    for k := 0; k < 2; k++ {
+      var an float64
+      if k == 0 {
+        an = fm*(b-fm)*x/((a+2*fm-1)*(a+2*fm))
+      } else {
+        an = -(a+fm)*(a+b+fm)*x/((a+2*fm)*(a+2*fm+1))
+      }
+      d = 1.0 + an*d
+      if math.Abs(d) < tiny {
+        d = tiny
+      }
+      c = 1.0 + an/c
+      if math.Abs(c) < tiny {
+        c = tiny
+      }
+      d  = 1.0/d
+      h *= d*c
+    }
+    if math.Abs(d*c-1) < 1e-16 {
+      break
+    }
+  }
+  return h
+}
+
+// Quantile of the beta distribution with parameters a and b
+func betaQuantile(p, a, b float64) float64 {
+  lo, hi := 0.0, 1.0
+  for i := 0; i < 200 && hi-lo > 1e-15; i++ {
+    if m := lo + (hi-lo)/2; betaI(a, b, m) < p {
+      lo = m
+    } else {
+      hi = m
+    }
+  }
+  return lo + (hi-lo)/2
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Survival function of the Kolmogorov distribution P(K > x)
+func kolmogorovQ(x float64) float64 {
+  if x <= 0 {
+    return 1.0
+  }
+  if x < 1.18 {
+    // alternating series converges slowly, use the series of the
+    // distribution function instead
+    p := 0.0
+    for j := 1; j <= 100; j++ {
+      t := float64(2*j-1)*math.Pi/x
+      v := math.Exp(-t*t/8)
+      p += v
+      if v < 1e-16*p {
+        break
+      }
+    }
+    return 1.0 - math.Sqrt(2*math.Pi)/x*p
+  }
+  q := 0.0
+  s := 1.0
+  for j := 1; j <= 100; j++ {
+    v := math.Exp(-2*float64(j*j)*x*x)
+    q += s*v
+    s  = -s
+    if v < 1e-16*q {
+      break
+    }
+  }
+  return math.Max(0, math.Min(1, 2*q))
+}
@@ -0,0 +1,150 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// Split a bin at x into two bins [Lower, x) and [x, Upper) and return both
+// bins. The value of the bin is divided proportionally to the widths of
+// both bins, i.e. values are assumed to be uniformly distributed within the
+// bin (see Aggregate). The new bin is inserted into the backing slice, which
+// requires O(n) operations. All pointers to bins become invalid.
+func (binning *Binning) Split(bin *Bin, x float64) (*Bin, *Bin, error) {
+  if !binning.owns(bin) {
+    return nil, nil, ErrForeignBin
+  }
+  if bin.Deleted {
+    return nil, nil, ErrBinDeleted
+  }
+  if !(bin.Lower < x && x < bin.Upper) {
+    return nil, nil, fmt.Errorf("%w: `%f' is not inside bin %v", ErrOutOfRange, x, bin)
+  }
+  i := bin.index
+  y := bin.Y*(bin.Upper-x)/bin.Size()
+  if !bin.dirty {
+    binning.deleteBinSorted(bin)
+  }
+  right := binning.insertBin(i, Bin{Lower: x, Upper: bin.Upper, Y: y})
+  left  := &binning.Bins[i]
+  left.Upper = x
+  left.Y    -= y
+  binning.insertSplit(left, right)
+  binning.undo = binning.undo[0:0]
+  binning.traceInvalidate()
+  if binning.config.logger != nil {
+    binning.log("split", "lower", left.Lower, "upper", right.Upper, "at", x, "left_y", left.Y, "right_y", right.Y)
+  }
+  binning.emit(Event{Kind: EventSplit, Bin: *left, Created: *right})
+  if err := binning.check("split"); err != nil {
+    return nil, nil, err
+  }
+  return left, right, nil
+}
+
+// Insert both bins of a split into the sorted list, where a is the bin that
+// existed before and b is the new bin
+func (binning *Binning) insertSplit(a, b *Bin) {
+  binning.updateKey(a)
+  binning.updateKey(b)
+  if a.dirty {
+    b.dirty = true
+    binning.dirty = append(binning.dirty, b.index)
+  } else {
+    binning.skipInsert(a)
+    binning.skipInsert(b)
+  }
+}
+
+// Insert a new bin into the linked list after the bin at position i, or as
+// first bin if i is noBin. The bin is stored in the backing slice right
+// after position i, so that active bins remain ordered, and it is not a
+// member of the sorted list. All pointers to bins become invalid.
+func (binning *Binning) insertBin(i int32, bin Bin) *Bin {
+  position := func(b *Bin) int32 {
+    if b == nil {
+      return noBin
+    }
+    return b.index
+  }
+  shift := func(j int32) int32 {
+    if j != noBin && j > i {
+      return j+1
+    }
+    return j
+  }
+  first, last       := position(binning.First), position(binning.Last)
+  smallest, largest := position(binning.Smallest), position(binning.Largest)
+  // make room at position i+1
+  binning.indexInvalidate()
+  binning.Bins = append(binning.Bins, Bin{})
+  copy(binning.Bins[i+2:], binning.Bins[i+1:])
+  for j := range binning.Bins {
+    b := &binning.Bins[j]
+    b.index   = int32(j)
+    b.next    = shift(b.next)
+    b.prev    = shift(b.prev)
+    b.smaller = shift(b.smaller)
+    b.larger  = shift(b.larger)
+  }
+  for j := range binning.skipLinks {
+    binning.skipLinks[j].prev = shift(binning.skipLinks[j].prev)
+    binning.skipLinks[j].next = shift(binning.skipLinks[j].next)
+  }
+  for l := range binning.skipHead {
+    binning.skipHead[l] = shift(binning.skipHead[l])
+  }
+  for j := range binning.dirty {
+    binning.dirty[j] = shift(binning.dirty[j])
+  }
+  binning.First    = binning.bin(shift(first))
+  binning.Last     = binning.bin(shift(last))
+  binning.Smallest = binning.bin(shift(smallest))
+  binning.Largest  = binning.bin(shift(largest))
+  // link new bin
+  j := i+1
+  b := &binning.Bins[j]
+  *b = bin
+  b.binning = binning
+  b.index   = j
+  b.smaller = noBin
+  b.larger  = noBin
+  b.skip    = 0
+  b.height  = 0
+  b.dirty   = false
+  b.Deleted = false
+  if i == noBin {
+    b.prev = noBin
+    b.next = position(binning.First)
+  } else {
+    b.prev = i
+    b.next = binning.Bins[i].next
+    binning.Bins[i].next = j
+  }
+  if b.next != noBin {
+    binning.Bins[b.next].prev = j
+  } else {
+    binning.Last = b
+  }
+  if b.prev == noBin {
+    binning.First = b
+  }
+  return b
+}
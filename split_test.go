@@ -0,0 +1,50 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestSplit1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 4, 5}, []float64{1, 5, 2, 8}, BinSum, BinLessY)
+
+  left, right, err := binning.Split(binning.FindBin(2), 3)
+  if err != nil || left.Y != 1 || right.Y != 1 || left.Upper != 3 || right.Lower != 3 {
+    t.Error("test failed"); return
+  }
+  if binning.String() != "[0.000000, 1.000000):1 [1.000000, 2.000000):5 [2.000000, 3.000000):1 [3.000000, 4.000000):1 [4.000000, 5.000000):8" {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+  if bin := binning.FindBin(3.5); bin != right {
+    t.Error("test failed")
+  }
+  if _, _, err := binning.Split(right, 4); err == nil {
+    t.Error("test failed")
+  }
+  if err := binning.FilterBins(3); err != nil {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+  if binning.String() != "[0.000000, 2.000000):6 [2.000000, 4.000000):2 [4.000000, 5.000000):8" {
+    t.Error("test failed")
+  }
+}
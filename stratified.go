@@ -0,0 +1,128 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Number of samples of each group within a bin
+type GroupCounts map[int]float64
+
+// Aggregator that keeps GroupCounts as payload. The value of a merged bin is
+// the sum of all values.
+type GroupAggregator struct{}
+
+func (GroupAggregator) Merge(l, r Bin) Bin {
+  c := GroupCounts{}
+  for _, bin := range [2]Bin{l, r} {
+    if d, ok := bin.Data.(GroupCounts); ok {
+      for g, n := range d {
+        c[g] += n
+      }
+    }
+  }
+  return Bin{Y: l.Y + r.Y, Data: c}
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Fit at most n bins to samples data, where groups[i] is the group of
+// data[i]. Boundaries are shared by all groups, but each bin contains at
+// least minCount samples of every group, so that the same bins can be used
+// for all groups. Bins are first merged with less until n bins remain, and
+// afterwards the bin with the smallest count of any group is deleted until
+// the constraint is satisfied. Non-finite samples are ignored. The value of
+// each bin is the total number of samples and Bin.Data holds the
+// GroupCounts of the bin.
+func NewStratified(data []float64, groups []int, n int, minCount float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  if len(data) != len(groups) {
+    return nil, ErrLengthMismatch
+  }
+  if n < 1 {
+    return nil, fmt.Errorf("%w: number of bins must be positive", ErrInvalidArgument)
+  }
+  index := []int{}
+  total := GroupCounts{}
+  for i := range data {
+    if isFinite(data[i]) {
+      index = append(index, i)
+      total[groups[i]]++
+    }
+  }
+  if len(index) == 0 {
+    return nil, fmt.Errorf("%w: no finite samples", ErrTooFewBoundaries)
+  }
+  for g, m := range total {
+    if m < minCount {
+      return nil, fmt.Errorf("%w: group `%d' has only %v samples", ErrInvalidArgument, g, m)
+    }
+  }
+  sort.Slice(index, func(i, j int) bool { return data[index[i]] < data[index[j]] })
+  x := []float64{}
+  y := []float64{}
+  c := []GroupCounts{}
+  for k, i := range index {
+    if k == 0 || data[i] != x[len(x)-1] {
+      x = append(x, data[i])
+      y = append(y, 0)
+      c = append(c, GroupCounts{})
+    }
+    y[len(y)-1]++
+    c[len(c)-1][groups[i]]++
+  }
+  x = append(x, math.Nextafter(x[len(x)-1], math.Inf(1)))
+  binning, err := New(x, y, nil, less, append(append([]Option{}, options...), WithAggregator(GroupAggregator{}))...)
+  if err != nil {
+    return nil, err
+  }
+  for i := range c {
+    binning.Bins[i].Data = c[i]
+  }
+  if err := binning.FilterBins(n); err != nil {
+    return nil, err
+  }
+  // smallest count of any group within a bin
+  smallest := func(bin *Bin) float64 {
+    r := math.Inf(1)
+    d := bin.Data.(GroupCounts)
+    for g := range total {
+      r = math.Min(r, d[g])
+    }
+    return r
+  }
+  for binning.First != binning.Last {
+    bin := binning.First
+    for at := binning.First; at != nil; at = binning.Next(at) {
+      if smallest(at) < smallest(bin) {
+        bin = at
+      }
+    }
+    if smallest(bin) >= minCount {
+      break
+    }
+    if _, err := binning.Delete(bin); err != nil {
+      return nil, err
+    }
+  }
+  binning.Compact()
+  return binning, nil
+}
@@ -0,0 +1,65 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "errors"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestStratified1(t *testing.T) {
+
+  data   := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+  groups := []int    {0, 0, 0, 0, 1, 1, 0, 1, 0, 1, 1, 1}
+
+  binning, err := NewStratified(data, groups, 6, 2, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  n := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    c := at.Data.(GroupCounts)
+    if c[0] < 2 || c[1] < 2 || c[0] + c[1] != at.Y {
+      t.Error("test failed")
+    }
+    n += at.Y
+  }
+  if n != 12 || len(binning.Bins) < 2 {
+    t.Error("test failed")
+  }
+  if _, err := NewStratified(data, groups, 6, 7, BinLessY); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+}
+
+func TestStratified2(t *testing.T) {
+
+  data   := []float64{1, 2, 3, 4, 5, 6}
+  groups := []int    {0, 1, 0, 1, 0, 1}
+
+  // options of the caller are not modified
+  options := make([]Option, 1, 4)
+  options[0] = WithEpsilon(0)
+  if _, err := NewStratified(data, groups, 2, 1, BinLessY, options...); err != nil {
+    t.Error(err); return
+  }
+  if options[0:2][1] != nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,151 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "bufio"
+import "fmt"
+import "io"
+import "strconv"
+import "strings"
+
+/* -------------------------------------------------------------------------- */
+
+// Streamer constructs a binning from a stream of sorted bin boundaries and
+// values with bounded memory. At most 2K bins are kept in memory, whenever
+// this limit is reached bins are merged until K bins remain. Bins of
+// different batches are merged only in subsequent batches, hence the result
+// may differ from filtering all bins at once.
+type Streamer struct {
+  K     int
+  Sum   func(Bin, Bin) float64
+  Less  func(Bin, Bin) bool
+  // lower boundaries and values of all bins
+  x   []float64
+  y   []float64
+}
+
+func NewStreamer(k int, sum func(Bin, Bin) float64, less func(Bin, Bin) bool) (*Streamer, error) {
+  if k < 2 {
+    return nil, fmt.Errorf("%w: number of bins must be at least two", ErrInvalidArgument)
+  }
+  return &Streamer{K: k, Sum: sum, Less: less}, nil
+}
+
+// Add a new bin with lower boundary x and value y. The upper boundary of the
+// previous bin is set to x.
+func (s *Streamer) Push(x, y float64) error {
+  if n := len(s.x); n > 0 && s.x[n-1] >= x {
+    return ErrUnsorted
+  }
+  s.x = append(s.x, x)
+  s.y = append(s.y, y)
+  if len(s.y) > 2*s.K {
+    return s.filter()
+  }
+  return nil
+}
+
+// Set the upper boundary of the last bin and return the resulting binning
+// with at most K bins
+func (s *Streamer) Finish(x float64) (*Binning, error) {
+  if n := len(s.x); n > 0 && s.x[n-1] >= x {
+    return nil, ErrUnsorted
+  }
+  binning, err := New(append(s.x, x), s.y, s.Sum, s.Less)
+  if err != nil {
+    return nil, err
+  }
+  if err := binning.FilterBins(s.K); err != nil {
+    return nil, err
+  }
+  s.x = nil
+  s.y = nil
+  return binning, nil
+}
+
+// Merge all bins except the most recent one, for which the upper boundary
+// is not yet known
+func (s *Streamer) filter() error {
+  n := len(s.y)-1
+  binning, err := New(s.x, s.y[0:n], s.Sum, s.Less)
+  if err != nil {
+    return err
+  }
+  if err := binning.FilterBins(s.K); err != nil {
+    return err
+  }
+  y := s.y[n]
+  // the upper boundary of the last bin is the
+  // lower boundary of the most recent bin
+  s.x = binning.AppendBoundaries(s.x[0:0])
+  s.y = binning.AppendValues    (s.y[0:0])
+  s.y = append(s.y, y)
+  return nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Read bins from a text stream and merge them on the fly to at most k bins.
+// Each line contains the lower boundary and the value of a bin, the last
+// line contains only the upper boundary of the last bin. Empty lines and
+// lines starting with # are ignored.
+func ReadStream(reader io.Reader, k int, sum func(Bin, Bin) float64, less func(Bin, Bin) bool) (*Binning, error) {
+  s, err := NewStreamer(k, sum, less)
+  if err != nil {
+    return nil, err
+  }
+  scanner := bufio.NewScanner(reader)
+  for scanner.Scan() {
+    line := strings.TrimSpace(scanner.Text())
+    if line == "" || strings.HasPrefix(line, "#") {
+      continue
+    }
+    fields := strings.Fields(line)
+    x, err := strconv.ParseFloat(fields[0], 64)
+    if err != nil {
+      return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+    }
+    switch len(fields) {
+    case 1:
+      for scanner.Scan() {
+        if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+          return nil, fmt.Errorf("%w: upper boundary must be given on the last line", ErrInvalidFormat)
+        }
+      }
+      if err := scanner.Err(); err != nil {
+        return nil, err
+      }
+      return s.Finish(x)
+    case 2:
+      y, err := strconv.ParseFloat(fields[1], 64)
+      if err != nil {
+        return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+      }
+      if err := s.Push(x, y); err != nil {
+        return nil, err
+      }
+    default:
+      return nil, fmt.Errorf("%w: line `%s'", ErrInvalidFormat, line)
+    }
+  }
+  if err := scanner.Err(); err != nil {
+    return nil, err
+  }
+  return nil, fmt.Errorf("%w: upper boundary of last bin is missing", ErrInvalidFormat)
+}
@@ -0,0 +1,78 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "bytes"
+import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestStream1(t *testing.T) {
+
+  var buffer bytes.Buffer
+  for i := 0; i < 1000; i++ {
+    fmt.Fprintf(&buffer, "%d %d\n", i*i, 1)
+  }
+  fmt.Fprintf(&buffer, "%d\n", 1000*1000)
+
+  binning, err := ReadStream(&buffer, 10, BinSum, BinLessSize)
+  if err != nil {
+    t.Error(err); return
+  }
+  if len(binning.Bins) != 10 {
+    t.Error("test failed")
+  }
+  if binning.First.Lower != 0 || binning.Last.Upper != 1000*1000 {
+    t.Error("test failed")
+  }
+  sum := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    sum += at.Y
+  }
+  if sum != 1000 {
+    t.Error("test failed")
+  }
+}
+
+func TestStream2(t *testing.T) {
+
+  s, _ := NewStreamer(2, BinSum, BinLessSize)
+  s.Push(1, 1)
+  if err := s.Push(0, 1); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestStream3(t *testing.T) {
+
+  s := "0 1\n1 2\n2 3\n3\n\n# end of stream\n"
+
+  binning, err := ReadStream(bytes.NewBufferString(s), 2, BinSum, BinLessSize)
+  if err != nil {
+    t.Error(err); return
+  }
+  if len(binning.Bins) != 2 || binning.Last.Upper != 3 {
+    t.Error("test failed")
+  }
+  if _, err := ReadStream(bytes.NewBufferString("0 1\n1 2\n2\n\n3 1\n"), 2, BinSum, BinLessSize); err == nil {
+    t.Error("test failed")
+  }
+}
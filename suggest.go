@@ -0,0 +1,176 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Number of bins suggested by a rule
+type Suggestion struct {
+  Rule  string
+  Bins  int
+  // negative cross-validation risk of the equal-width histogram with this
+  // number of bins, larger scores are better
+  Score float64
+}
+
+// Report of SuggestBins
+type Report struct {
+  Suggestions []Suggestion
+  // index of the suggestion with the largest score or -1 if there are no
+  // suggestions
+  Best        int
+  lo, hi      float64
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Boundaries of an equal-width histogram with m bins over [lo, hi], where
+// the last boundary is the next float64 after hi
+func equalWidthBoundaries(lo, hi float64, m int) []float64 {
+  x := make([]float64, m+1)
+  for i := range x {
+    x[i] = lo + float64(i)*(hi - lo)/float64(m)
+  }
+  x[m] = math.Nextafter(hi, math.Inf(1))
+  return x
+}
+
+// Counts of an equal-width histogram with m bins over [lo, hi] of sorted
+// values v, which requires O(m log n) operations
+func equalWidthCounts(v []float64, lo, hi float64, m int) []float64 {
+  x := equalWidthBoundaries(lo, hi, m)
+  r := make([]float64, m)
+  for i := range r {
+    r[i] = float64(sort.SearchFloat64s(v, x[i+1]) - sort.SearchFloat64s(v, x[i]))
+  }
+  return r
+}
+
+// Unbiased cross-validation risk of an equal-width histogram with m bins
+// (Rudemo, 1982), which is scaled by the range of the data
+func crossValidationRisk(v []float64, lo, hi float64, m int) float64 {
+  n := float64(len(v))
+  h := 1/float64(m)
+  s := 0.0
+  for _, c := range equalWidthCounts(v, lo, hi, m) {
+    s += (c/n)*(c/n)
+  }
+  return 2/((n-1)*h) - (n+1)/((n-1)*h)*s
+}
+
+// Log posterior of an equal-width histogram with m bins (Knuth, 2006)
+func knuthPosterior(v []float64, lo, hi float64, m int) float64 {
+  n    := float64(len(v))
+  a, _ := math.Lgamma(float64(m)/2)
+  b, _ := math.Lgamma(0.5)
+  c, _ := math.Lgamma(n + float64(m)/2)
+  r    := n*math.Log(float64(m)) + a - float64(m)*b - c
+  for _, k := range equalWidthCounts(v, lo, hi, m) {
+    d, _ := math.Lgamma(k + 0.5)
+    r += d
+  }
+  return r
+}
+
+// Evaluate rules for the number of bins of an equal-width histogram of
+// data, i.e. the rules of Sturges, Scott, Freedman-Diaconis, minimization of
+// the cross-validation risk (CV) and maximization of Knuth's posterior. All
+// suggestions are scored by their cross-validation risk. Non-finite values
+// are ignored.
+func SuggestBins(data []float64) Report {
+  v := []float64{}
+  for _, x := range data {
+    if isFinite(x) {
+      v = append(v, x)
+    }
+  }
+  sort.Float64s(v)
+  r := Report{Best: -1}
+  if len(v) < 2 || v[0] == v[len(v)-1] {
+    return r
+  }
+  n   := float64(len(v))
+  r.lo = v[0]
+  r.hi = v[len(v)-1]
+  // number of bins of width h
+  bins := func(h float64) int {
+    if !(h > 0) {
+      return 1
+    }
+    return int(math.Max(1, math.Ceil((r.hi - r.lo)/h)))
+  }
+  mean, sd := 0.0, 0.0
+  for _, x := range v {
+    mean += x/n
+  }
+  for _, x := range v {
+    sd += (x - mean)*(x - mean)/(n-1)
+  }
+  sd   = math.Sqrt(sd)
+  iqr := sortedQuantile(v, 0.75) - sortedQuantile(v, 0.25)
+  // largest number of bins considered by CV and Knuth
+  k   := int(math.Min(n, 1000))
+  cv, knuth := 1, 1
+  cvBest    := crossValidationRisk(v, r.lo, r.hi, 1)
+  knuthBest := knuthPosterior     (v, r.lo, r.hi, 1)
+  for m := 2; m <= k; m++ {
+    if risk := crossValidationRisk(v, r.lo, r.hi, m); risk < cvBest {
+      cv, cvBest = m, risk
+    }
+    if p := knuthPosterior(v, r.lo, r.hi, m); p > knuthBest {
+      knuth, knuthBest = m, p
+    }
+  }
+  r.Suggestions = []Suggestion{
+    {Rule: "sturges", Bins: int(math.Ceil(math.Log2(n))) + 1},
+    {Rule: "scott",   Bins: bins(3.49*sd*math.Pow(n, -1.0/3))},
+    {Rule: "fd",      Bins: bins(2*iqr*math.Pow(n, -1.0/3))},
+    {Rule: "cv",      Bins: cv},
+    {Rule: "knuth",   Bins: knuth},
+  }
+  for i := range r.Suggestions {
+    s := &r.Suggestions[i]
+    s.Score = -crossValidationRisk(v, r.lo, r.hi, s.Bins)
+    if r.Best < 0 || s.Score > r.Suggestions[r.Best].Score {
+      r.Best = i
+    }
+  }
+  return r
+}
+
+// Create the equal-width binning of the best suggestion, where the value of
+// each bin is the number of samples in data
+func (r Report) Binning(data []float64, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  if r.Best < 0 {
+    return nil, fmt.Errorf("%w: no suggestion", ErrInvalidArgument)
+  }
+  v := []float64{}
+  for _, x := range data {
+    if isFinite(x) {
+      v = append(v, x)
+    }
+  }
+  sort.Float64s(v)
+  m := r.Suggestions[r.Best].Bins
+  return New(equalWidthBoundaries(r.lo, r.hi, m), equalWidthCounts(v, r.lo, r.hi, m), BinSum, less, options...)
+}
@@ -0,0 +1,57 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestSuggest1(t *testing.T) {
+
+  r    := rand.New(rand.NewSource(1))
+  data := make([]float64, 1000)
+  for i := range data {
+    data[i] = r.NormFloat64()
+  }
+  report := SuggestBins(data)
+  if len(report.Suggestions) != 5 || report.Best < 0 {
+    t.Error("test failed"); return
+  }
+  // Sturges: ceil(log2(1000)) + 1
+  if s := report.Suggestions[0]; s.Rule != "sturges" || s.Bins != 11 {
+    t.Error("test failed")
+  }
+  for _, s := range report.Suggestions {
+    if s.Bins < 5 || s.Bins > 100 || s.Score > report.Suggestions[report.Best].Score {
+      t.Error("test failed")
+    }
+  }
+  binning, err := report.Binning(data, BinLessY)
+  if err != nil {
+    t.Error(err); return
+  }
+  if len(binning.Bins) != report.Suggestions[report.Best].Bins || binning.Aggregate(math.Inf(-1), math.Inf(1)) != 1000 {
+    t.Error("test failed")
+  }
+  if report := SuggestBins([]float64{1, 1}); report.Best != -1 {
+    t.Error("test failed")
+  }
+}
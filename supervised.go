@@ -0,0 +1,221 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Metric for the separation of a binary target by bins
+type SeparationMetric int
+
+const (
+  // Kolmogorov-Smirnov statistic, i.e. the largest difference between the
+  // distribution functions of both classes at the bin boundaries
+  SeparationKS SeparationMetric = iota
+  // Area under the ROC curve of the bin index as predictor, where samples
+  // within a bin are tied. Values below 0.5 are mirrored, so that the
+  // metric is in [0.5, 1].
+  SeparationAUC
+)
+
+func (m SeparationMetric) String() string {
+  switch m {
+  case SeparationKS:  return "ks"
+  case SeparationAUC: return "auc"
+  }
+  return fmt.Sprintf("SeparationMetric(%d)", int(m))
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Separation of classes given the counts of negative and positive samples
+// in each bin
+func separation(m SeparationMetric, neg, pos []float64) float64 {
+  n, p := 0.0, 0.0
+  for i := range neg {
+    n += neg[i]
+    p += pos[i]
+  }
+  if n <= 0 || p <= 0 {
+    return 0
+  }
+  switch m {
+  case SeparationKS:
+    r, cn, cp := 0.0, 0.0, 0.0
+    for i := range neg {
+      cn += neg[i]
+      cp += pos[i]
+      r   = math.Max(r, math.Abs(cp/p - cn/n))
+    }
+    return r
+  case SeparationAUC:
+    a, cn := 0.0, 0.0
+    for i := range neg {
+      a  += pos[i]*(cn + neg[i]/2)
+      cn += neg[i]
+    }
+    return math.Max(a/(n*p), 1 - a/(n*p))
+  }
+  return math.NaN()
+}
+
+// Separation of a binary target by the bins of a binning created with
+// NewSupervised. Bins without GroupCounts payload are ignored.
+func (binning *Binning) Separation(m SeparationMetric) float64 {
+  neg := []float64{}
+  pos := []float64{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if c, ok := at.Data.(GroupCounts); ok {
+      neg = append(neg, c[0])
+      pos = append(pos, c[1])
+    }
+  }
+  return separation(m, neg, pos)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Fit at most n bins to samples data with binary target, where neighboring
+// bins are merged greedily such that the separation metric m of the
+// remaining bins is maximized. Non-finite samples are ignored. The value of
+// each bin is its number of samples and Bin.Data holds the GroupCounts of
+// negative (group 0) and positive (group 1) samples. The binning uses the
+// GroupAggregator and less for all further merges.
+func NewSupervised(data []float64, target []bool, n int, m SeparationMetric, less func(Bin, Bin) bool, options ...Option) (*Binning, error) {
+  if len(data) != len(target) {
+    return nil, ErrLengthMismatch
+  }
+  if n < 1 {
+    return nil, fmt.Errorf("%w: number of bins must be positive", ErrInvalidArgument)
+  }
+  if m != SeparationKS && m != SeparationAUC {
+    return nil, fmt.Errorf("%w: invalid separation metric `%v'", ErrInvalidArgument, m)
+  }
+  index := []int{}
+  for i := range data {
+    if isFinite(data[i]) {
+      index = append(index, i)
+    }
+  }
+  if len(index) == 0 {
+    return nil, fmt.Errorf("%w: no finite samples", ErrTooFewBoundaries)
+  }
+  sort.Slice(index, func(i, j int) bool { return data[index[i]] < data[index[j]] })
+  x   := []float64{}
+  neg := []float64{}
+  pos := []float64{}
+  for k, i := range index {
+    if k == 0 || data[i] != x[len(x)-1] {
+      x   = append(x, data[i])
+      neg = append(neg, 0)
+      pos = append(pos, 0)
+    }
+    if target[i] {
+      pos[len(pos)-1]++
+    } else {
+      neg[len(neg)-1]++
+    }
+  }
+  x = append(x, math.Nextafter(x[len(x)-1], math.Inf(1)))
+  // totals and (unnormalized) AUC of the initial bins
+  tn, tp, a := 0.0, 0.0, 0.0
+  for i := range neg {
+    a  += pos[i]*(tn + neg[i]/2)
+    tn += neg[i]
+    tp += pos[i]
+  }
+  for len(neg) > n {
+    k := 0
+    switch {
+    case tn <= 0 || tp <= 0:
+      // separation is undefined, merge the smallest pair of bins
+      for i := 1; i+1 < len(neg); i++ {
+        if neg[i]+pos[i]+neg[i+1]+pos[i+1] < neg[k]+pos[k]+neg[k+1]+pos[k+1] {
+          k = i
+        }
+      }
+    case m == SeparationKS:
+      // merging bins k and k+1 removes the difference d[k], so that the
+      // largest remaining difference is kept unless d[k] is the largest
+      // one. Among all equivalent merges the smallest difference is removed.
+      d := make([]float64, len(neg)-1)
+      cn, cp := 0.0, 0.0
+      first, second := -1, -1
+      for i := range d {
+        cn  += neg[i]
+        cp  += pos[i]
+        d[i] = math.Abs(cp/tp - cn/tn)
+        if first < 0 || d[i] > d[first] {
+          first, second = i, first
+        } else if second < 0 || d[i] > d[second] {
+          second = i
+        }
+      }
+      score := func(i int) float64 {
+        if i != first {
+          return d[first]
+        }
+        if second < 0 {
+          return 0
+        }
+        return d[second]
+      }
+      for i := 1; i < len(d); i++ {
+        if s, t := score(i), score(k); s > t || (s == t && d[i] < d[k]) {
+          k = i
+        }
+      }
+    case m == SeparationAUC:
+      // merging bins k and k+1 ties all pairs of samples between both bins
+      delta := func(i int) float64 {
+        return (pos[i+1]*neg[i] - pos[i]*neg[i+1])/2
+      }
+      score := func(i int) float64 {
+        r := (a - delta(i))/(tn*tp)
+        return math.Max(r, 1-r)
+      }
+      for i := 1; i+1 < len(neg); i++ {
+        if score(i) > score(k) {
+          k = i
+        }
+      }
+      a -= delta(k)
+    }
+    neg[k] += neg[k+1]
+    pos[k] += pos[k+1]
+    neg = append(neg[0:k+1], neg[k+2:]...)
+    pos = append(pos[0:k+1], pos[k+2:]...)
+    x   = append(x  [0:k+1], x  [k+2:]...)
+  }
+  y := make([]float64, len(neg))
+  for i := range y {
+    y[i] = neg[i] + pos[i]
+  }
+  binning, err := New(x, y, nil, less, append(append([]Option{}, options...), WithAggregator(GroupAggregator{}))...)
+  if err != nil {
+    return nil, err
+  }
+  for i := range y {
+    binning.Bins[i].Data = GroupCounts{0: neg[i], 1: pos[i]}
+  }
+  return binning, nil
+}
@@ -0,0 +1,72 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestSupervised1(t *testing.T) {
+
+  data   := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+  target := []bool{false, false, true, false, false, true, true, false, true, true}
+
+  for _, m := range []SeparationMetric{SeparationKS, SeparationAUC} {
+    full, err := NewSupervised(data, target, 10, m, BinLessY)
+    if err != nil {
+      t.Error(err); return
+    }
+    binning, err := NewSupervised(data, target, 3, m, BinLessY)
+    if err != nil {
+      t.Error(err); return
+    }
+    if len(binning.Bins) != 3 || (m == SeparationKS && binning.Separation(m) > full.Separation(m) + 1e-12) {
+      t.Error("test failed")
+    }
+    // the best split with two bins is at 5.5
+    binning, _ = NewSupervised(data, target, 2, m, BinLessY)
+    if x := binning.AppendBoundaries(nil); x[1] != 6 {
+      t.Error("test failed")
+    }
+  }
+  binning, _ := NewSupervised(data, target, 2, SeparationKS, BinLessY)
+  if r := binning.Separation(SeparationKS); math.Abs(r - 0.6) > 1e-12 {
+    t.Error("test failed")
+  }
+  if _, err := NewSupervised(data, target[1:], 2, SeparationKS, BinLessY); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestSupervised2(t *testing.T) {
+
+  data   := []float64{1, 2, 3, 4, 5, 6}
+  target := []bool{false, true, false, true, true, true}
+
+  // options of the caller are not modified
+  options := make([]Option, 1, 4)
+  options[0] = WithEpsilon(0)
+  if _, err := NewSupervised(data, target, 2, SeparationKS, BinLessY, options...); err != nil {
+    t.Error(err); return
+  }
+  if options[0:2][1] != nil {
+    t.Error("test failed")
+  }
+}
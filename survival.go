@@ -0,0 +1,63 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+/* -------------------------------------------------------------------------- */
+
+// Survival function P(X >= x) of the distribution given by the bin values,
+// where values are assumed to be uniformly distributed within bins. Tail
+// probabilities are summed starting with the last bin, so that they are
+// accurate also in the deep tail.
+func (binning *Binning) Survival(x float64) float64 {
+  n, s := 0.0, 0.0
+  for at := binning.Last; at != nil; at = binning.Prev(at) {
+    n += at.Y
+    switch {
+    case x <= at.Lower:
+      s += at.Y
+    case x < at.Upper:
+      s += at.Y*(at.Upper-x)/at.Size()
+    }
+  }
+  if n <= 0 {
+    return 0.0
+  }
+  return s/n
+}
+
+// Returns the boundaries x of all bins together with the survival function
+// s[i] = P(X >= x[i]), see Survival
+func (binning *Binning) CCDF() ([]float64, []float64) {
+  x := binning.AppendBoundaries(nil)
+  s := make([]float64, len(x))
+  if len(x) == 0 {
+    return x, s
+  }
+  i := len(x)-1
+  for at := binning.Last; at != nil; at = binning.Prev(at) {
+    s[i-1] = s[i] + at.Y
+    i--
+  }
+  if n := s[0]; n > 0 {
+    for i := range s {
+      s[i] /= n
+    }
+  }
+  return x, s
+}
@@ -0,0 +1,42 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestSurvival1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 4}, []float64{6, 3, 1e-12}, BinSum, BinLessSize)
+
+  n := 9 + 1e-12
+  if binning.Survival(-1) != 1 || binning.Survival(0.5) != (3+3+1e-12)/n || binning.Survival(5) != 0 {
+    t.Error("test failed")
+  }
+  // deep tail
+  if math.Abs(binning.Survival(3) - 0.5e-12/n) > 1e-25 {
+    t.Error("test failed")
+  }
+  x, s := binning.CCDF()
+  if len(x) != 4 || len(s) != 4 || s[0] != 1 || s[3] != 0 || math.Abs(s[2] - 1e-12/n) > 1e-25 {
+    t.Error("test failed")
+  }
+}
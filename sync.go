@@ -0,0 +1,152 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "context"
+import "sync"
+import "sync/atomic"
+
+/* -------------------------------------------------------------------------- */
+
+// SyncBinning wraps a binning for concurrent use. Queries are performed
+// under a read lock, modifications under a write lock. In addition, a
+// frozen snapshot is published after each batch of modifications, which
+// is read without any locking (see Snapshot).
+type SyncBinning struct {
+  mutex    sync.RWMutex
+  binning  *Binning
+  snapshot atomic.Value
+}
+
+func NewSyncBinning(binning *Binning) *SyncBinning {
+  obj := SyncBinning{binning: binning}
+  obj.publish()
+  return &obj
+}
+
+// Publish a new snapshot, the write lock must be held. Indices are rebuilt
+// as well, since queries under a read lock must not modify the binning.
+func (obj *SyncBinning) publish() {
+  obj.binning.indexBuild()
+  obj.snapshot.Store(obj.binning.Frozen())
+}
+
+// Returns the most recently published snapshot without locking. Snapshots
+// are published by Write, FilterBins, Update and Reset. Single samples
+// added with AddSample become visible with the next batch or by calling
+// Publish.
+func (obj *SyncBinning) Snapshot() *Frozen {
+  return obj.snapshot.Load().(*Frozen)
+}
+
+// Publish a snapshot of the current state (see Snapshot)
+func (obj *SyncBinning) Publish() {
+  obj.mutex.Lock()
+  defer obj.mutex.Unlock()
+  obj.publish()
+}
+
+// Call f with the binning under a read lock. The binning must not be
+// modified by f and pointers to bins must not be retained.
+func (obj *SyncBinning) Read(f func(*Binning)) {
+  obj.mutex.RLock()
+  defer obj.mutex.RUnlock()
+  f(obj.binning)
+}
+
+// Call f with the binning under a write lock and publish a new snapshot
+func (obj *SyncBinning) Write(f func(*Binning)) {
+  obj.mutex.Lock()
+  defer obj.mutex.Unlock()
+  f(obj.binning)
+  obj.publish()
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns a copy of the bin containing x and false if x is out of range
+func (obj *SyncBinning) FindBin(x float64) (Bin, bool) {
+  obj.mutex.RLock()
+  defer obj.mutex.RUnlock()
+  if bin := obj.binning.FindBin(x); bin != nil {
+    return *bin, true
+  }
+  return Bin{}, false
+}
+
+func (obj *SyncBinning) Aggregate(lo, hi float64) float64 {
+  obj.mutex.RLock()
+  defer obj.mutex.RUnlock()
+  return obj.binning.Aggregate(lo, hi)
+}
+
+func (obj *SyncBinning) Boundaries() []float64 {
+  obj.mutex.RLock()
+  defer obj.mutex.RUnlock()
+  return obj.binning.AppendBoundaries(nil)
+}
+
+func (obj *SyncBinning) Values() []float64 {
+  obj.mutex.RLock()
+  defer obj.mutex.RUnlock()
+  return obj.binning.AppendValues(nil)
+}
+
+func (obj *SyncBinning) String() string {
+  obj.mutex.RLock()
+  defer obj.mutex.RUnlock()
+  return obj.binning.String()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (obj *SyncBinning) AddSample(x, w float64) error {
+  obj.mutex.Lock()
+  defer obj.mutex.Unlock()
+  defer obj.binning.indexBuild()
+  return obj.binning.AddSample(x, w)
+}
+
+func (obj *SyncBinning) FilterBins(n int) error {
+  obj.mutex.Lock()
+  defer obj.mutex.Unlock()
+  defer obj.publish()
+  return obj.binning.FilterBins(n)
+}
+
+func (obj *SyncBinning) FilterBinsContext(ctx context.Context, n int) error {
+  obj.mutex.Lock()
+  defer obj.mutex.Unlock()
+  defer obj.publish()
+  return obj.binning.FilterBinsContext(ctx, n)
+}
+
+func (obj *SyncBinning) Update() error {
+  obj.mutex.Lock()
+  defer obj.mutex.Unlock()
+  defer obj.publish()
+  return obj.binning.Update()
+}
+
+func (obj *SyncBinning) Reset(x, y []float64) error {
+  obj.mutex.Lock()
+  defer obj.mutex.Unlock()
+  defer obj.publish()
+  return obj.binning.Reset(x, y)
+}
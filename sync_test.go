@@ -0,0 +1,131 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "sync"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestSync1(t *testing.T) {
+
+  x := make([]float64, 1001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + float64(i % 7 + 1)
+  }
+  binning, _ := New(x, nil, BinSum, BinLessY)
+  s := NewSyncBinning(binning)
+
+  var wg sync.WaitGroup
+  for k := 0; k < 4; k++ {
+    wg.Add(1)
+    go func(k int) {
+      defer wg.Done()
+      for i := 0; i < 100; i++ {
+        s.AddSample(float64(i*k), 1)
+        s.FindBin(float64(i))
+      }
+    }(k)
+  }
+  wg.Wait()
+  s.FilterBins(10)
+
+  if r := s.Aggregate(x[0], x[1000]); r != 400 {
+    t.Error("test failed")
+  }
+  if len(s.Values()) != 10 {
+    t.Error("test failed")
+  }
+}
+
+func TestSync2(t *testing.T) {
+
+  x := make([]float64, 1001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + float64(i % 7 + 1)
+  }
+  binning, _ := New(x, []float64{1}, BinSum, BinLessY)
+  s := NewSyncBinning(binning)
+
+  if s.Snapshot().Len() != 1000 {
+    t.Error("test failed")
+  }
+  var wg sync.WaitGroup
+  for k := 0; k < 4; k++ {
+    wg.Add(1)
+    go func() {
+      defer wg.Done()
+      for i := 0; i < 100; i++ {
+        if r := s.Snapshot().Total(); r != 1000 {
+          t.Error("test failed"); return
+        }
+      }
+    }()
+  }
+  for n := 500; n >= 10; n /= 2 {
+    s.FilterBins(n)
+  }
+  wg.Wait()
+
+  if s.Snapshot().Len() != 15 {
+    t.Error("test failed")
+  }
+  s.AddSample(x[0], 1)
+  if s.Snapshot().Total() != 1000 {
+    t.Error("test failed")
+  }
+  s.Publish()
+  if s.Snapshot().Total() != 1001 {
+    t.Error("test failed")
+  }
+}
+
+func TestSync3(t *testing.T) {
+
+  x := make([]float64, 1001)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + float64(i % 7 + 1)
+  }
+  binning, _ := New(x, []float64{1}, BinSum, BinLessY, WithPrefixIndex(), WithRangeIndex())
+  s := NewSyncBinning(binning)
+
+  for n := 500; n >= 10; n /= 2 {
+    // indices are stale after compacting bins, readers must not rebuild
+    // them concurrently (run with -race)
+    s.FilterBins(n)
+    s.Update()
+    var wg sync.WaitGroup
+    for k := 0; k < 4; k++ {
+      wg.Add(1)
+      go func() {
+        defer wg.Done()
+        if r := s.Aggregate(x[0], x[1000]); r != 1000 {
+          t.Error("test failed")
+        }
+        s.Read(func(binning *Binning) {
+          if binning.RangeMin(x[0], x[1000]) == nil {
+            t.Error("test failed")
+          }
+        })
+      }()
+    }
+    wg.Wait()
+  }
+}
@@ -0,0 +1,130 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Centroid of a t-digest quantile sketch
+type Centroid struct {
+  Mean   float64
+  Weight float64
+}
+
+type centroidList []Centroid
+
+func (c centroidList) Len() int {
+  return len(c)
+}
+
+func (c centroidList) Less(i, j int) bool {
+  return c[i].Mean < c[j].Mean
+}
+
+func (c centroidList) Swap(i, j int) {
+  c[i], c[j] = c[j], c[i]
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Aggregator that keeps the centroid of a bin as payload, such that merged
+// bins retain the weighted mean of their centroids. Bins without payload
+// are treated as a centroid at the bin center with weight Y. The value of
+// a merged bin is the sum of both weights.
+type CentroidAggregator struct{}
+
+func (CentroidAggregator) centroid(bin Bin) Centroid {
+  if c, ok := bin.Data.(Centroid); ok {
+    return c
+  }
+  return Centroid{Mean: (bin.Lower + bin.Upper)/2.0, Weight: bin.Y}
+}
+
+func (a CentroidAggregator) Merge(l, r Bin) Bin {
+  s := a.centroid(l)
+  t := a.centroid(r)
+  if w := s.Weight + t.Weight; w != 0.0 {
+    s.Mean = (s.Mean*s.Weight + t.Mean*t.Weight)/w
+  }
+  s.Weight += t.Weight
+  return Bin{Y: s.Weight, Data: s}
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Create a binning from t-digest centroids. Bin boundaries are placed half
+// way between neighboring centroids, the outer boundaries are extrapolated
+// by the same distance. Bin values are the centroid weights, which are
+// summed when bins are merged. Centroids are kept as payload (see
+// CentroidAggregator).
+func FromTDigest(centroids []Centroid) (*Binning, error) {
+  c := append(centroidList{}, centroids...)
+  sort.Sort(c)
+  // coalesce centroids with identical means
+  n := 0
+  for i := 0; i < len(c); i++ {
+    if n > 0 && c[n-1].Mean == c[i].Mean {
+      c[n-1].Weight += c[i].Weight
+    } else {
+      c[n] = c[i]; n++
+    }
+  }
+  c = c[0:n]
+  if n < 2 {
+    return nil, fmt.Errorf("%w: t-digest must have at least two distinct centroids", ErrTooFewBoundaries)
+  }
+  x := make([]float64, n+1)
+  y := make([]float64, n)
+  for i := 1; i < n; i++ {
+    x[i] = (c[i-1].Mean + c[i].Mean)/2.0
+  }
+  x[0] = c[0  ].Mean - (x[1  ] - c[0  ].Mean)
+  x[n] = c[n-1].Mean + (c[n-1].Mean - x[n-1])
+  for i := 0; i < n; i++ {
+    y[i] = c[i].Weight
+  }
+  binning, err := New(x, y, nil, BinLessY, WithAggregator(CentroidAggregator{}))
+  if err != nil {
+    return nil, err
+  }
+  for i := range binning.Bins {
+    binning.Bins[i].Data = c[i]
+  }
+  return binning, nil
+}
+
+// Convert the binning to t-digest centroids. The weight of each centroid is
+// the value of the corresponding bin. Its mean is taken from a Centroid
+// payload if present, which keeps the weighted mean of merged centroids,
+// and is the center of the bin otherwise.
+func (binning *Binning) ToTDigest() []Centroid {
+  r := []Centroid{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if !at.Deleted {
+      m := (at.Lower + at.Upper)/2.0
+      if c, ok := at.Data.(Centroid); ok {
+        m = c.Mean
+      }
+      r = append(r, Centroid{Mean: m, Weight: binning.Value(at)})
+    }
+  }
+  return r
+}
@@ -0,0 +1,79 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestTDigest1(t *testing.T) {
+
+  c := []Centroid{{3, 4}, {1, 1}, {2, 2}, {4, 1}, {2, 1}}
+
+  binning, err := FromTDigest(c)
+  if err != nil {
+    t.Error(err); return
+  }
+  if len(binning.Bins) != 4 {
+    t.Error("test failed")
+  }
+  if binning.First.Lower != 0.5 || binning.Last.Upper != 4.5 {
+    t.Error("test failed")
+  }
+  r := binning.ToTDigest()
+  if len(r) != 4 {
+    t.Error("test failed")
+  }
+  if r[1].Mean != 2 || r[1].Weight != 3 {
+    t.Error("test failed")
+  }
+  binning.FilterBins(2)
+
+  r = binning.ToTDigest()
+  if len(r) != 2 {
+    t.Error("test failed")
+  }
+  if r[0].Weight + r[1].Weight != 9 {
+    t.Error("test failed")
+  }
+}
+
+func TestTDigest2(t *testing.T) {
+
+  c := []Centroid{{1, 1}, {2, 2}, {10, 1}}
+
+  binning, err := FromTDigest(c)
+  if err != nil {
+    t.Error(err); return
+  }
+  r := binning.ToTDigest()
+  if len(r) != 3 || r[0] != c[0] || r[1] != c[1] || r[2] != c[2] {
+    t.Error("test failed")
+  }
+  binning.FilterBins(2)
+
+  r = binning.ToTDigest()
+  if len(r) != 2 {
+    t.Error("test failed"); return
+  }
+  if r[0].Mean != 5.0/3.0 || r[0].Weight != 3 || r[1] != c[2] {
+    t.Error("test failed")
+  }
+}
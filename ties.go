@@ -0,0 +1,90 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+/* -------------------------------------------------------------------------- */
+
+// Rule for ordering bins that are equal with respect to the less function
+type TieBreaking int
+
+const (
+  // Compare the smallest neighbors of both bins, if they are also equal
+  // the leftmost bin is smaller (default)
+  TieBreakNeighbors TieBreaking = iota
+  // The leftmost bin is smaller
+  TieBreakLeftmost
+  // The rightmost bin is smaller
+  TieBreakRightmost
+)
+
+// Rule for selecting the neighbor a deleted bin is merged with
+type MergeRule int
+
+const (
+  // Merge with the smaller neighbor, or with the right neighbor if both
+  // neighbors are equal (default)
+  MergeSmallerRight MergeRule = iota
+  // Merge with the smaller neighbor, or with the left neighbor if both
+  // neighbors are equal
+  MergeSmallerLeft
+  // Always merge with the left neighbor, unless the bin is the first bin
+  MergeLeft
+  // Always merge with the right neighbor, unless the bin is the last bin
+  MergeRight
+)
+
+/* -------------------------------------------------------------------------- */
+
+// Set the rule for ordering bins that are equal with respect to the less
+// function
+func WithTieBreaking(t TieBreaking) Option {
+  return func(c *config) {
+    c.ties = t
+  }
+}
+
+// Set the rule for selecting the neighbor a deleted bin is merged with
+func WithMergeRule(r MergeRule) Option {
+  return func(c *config) {
+    c.merge = r
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns true if a bin with neighbors prev and next should be merged with
+// prev. Neighbors are compared without the final positional tie-breaking.
+func (binning *Binning) mergeLeft(prev, next *Bin) bool {
+  if binning.force != 0 {
+    return binning.force < 0
+  }
+  switch binning.config.merge {
+  case MergeLeft:
+    return true
+  case MergeRight:
+    return false
+  }
+  switch binning.compare(prev, next) {
+  case -1:
+    return true
+  case  1:
+    return false
+  }
+  return binning.config.merge == MergeSmallerLeft
+}
@@ -0,0 +1,69 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestTies1(t *testing.T) {
+
+  x := []float64{0,1,2,3,4,5,6,7,8}
+
+  b1, _ := New(x, nil, BinSum, BinLessSize, WithTieBreaking(TieBreakLeftmost))
+  if b1.Smallest.Lower != 0 || b1.Largest.Lower != 7 {
+    t.Error("test failed")
+  }
+  b2, _ := New(x, nil, BinSum, BinLessSize, WithTieBreaking(TieBreakRightmost))
+  if b2.Smallest.Lower != 7 || b2.Largest.Lower != 0 {
+    t.Error("test failed")
+  }
+  b3, _ := New(x, nil, BinSum, BinLessSize, WithTieBreaking(TieBreakLeftmost), WithMergeRule(MergeSmallerLeft))
+  b3.Delete(&b3.Bins[3])
+  if b3.Bins[2].Upper != 4 {
+    t.Error("test failed")
+  }
+  b4, _ := New(x, nil, BinSum, BinLessSize, WithTieBreaking(TieBreakLeftmost))
+  b4.Delete(&b4.Bins[3])
+  if b4.Bins[4].Lower != 3 {
+    t.Error("test failed")
+  }
+  b5, _ := New(x, nil, BinSum, BinLessSize, WithMergeRule(MergeLeft))
+  b5.Delete(&b5.Bins[3])
+  b5.Delete(&b5.Bins[2])
+  if b5.Bins[1].Upper != 4 {
+    t.Error("test failed")
+  }
+}
+
+func TestTies2(t *testing.T) {
+
+  // results must not depend on the order of boundaries
+  x1 := []float64{0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16}
+  x2 := []float64{15,3,8,1,0,12,7,4,14,2,9,6,11,13,5,10,16}
+
+  b1, _ := New(x1, nil, BinSum, BinLessSize)
+  b2, _ := New(x2, nil, BinSum, BinLessSize)
+  b1.FilterBins(5)
+  b2.FilterBins(5)
+  if b1.String() != b2.String() {
+    t.Error("test failed")
+  }
+}
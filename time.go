@@ -0,0 +1,118 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "time"
+
+/* -------------------------------------------------------------------------- */
+
+// TimeBinning is a binning with time.Time boundaries. The underlying binning
+// measures positions in nanoseconds relative to the first boundary, hence
+// Bin.Size returns durations in nanoseconds. Boundaries are converted back
+// exactly, including the location and monotonic clock reading of the
+// original time stamps.
+type TimeBinning struct {
+  Binning *Binning
+  axis    *AxisBinning
+}
+
+// Create a new binning with sorted boundaries t, see New
+func NewTimeBinning(t []time.Time, y []float64, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*TimeBinning, error) {
+  x := make([]Coordinate, len(t))
+  for i := range t {
+    x[i] = TimeCoordinate(t[i])
+  }
+  axis, err := NewAxisBinning(x, y, sum, less, options...)
+  if err != nil {
+    return nil, err
+  }
+  return &TimeBinning{Binning: axis.Binning, axis: axis}, nil
+}
+
+// Create a new binning with n bins of duration d starting at time start
+func NewTimeBinningFixed(start time.Time, d time.Duration, n int, y []float64, sum func(Bin, Bin) float64, less func(Bin, Bin) bool, options ...Option) (*TimeBinning, error) {
+  if d <= 0 {
+    return nil, fmt.Errorf("%w: duration must be positive", ErrInvalidArgument)
+  }
+  if n < 1 {
+    return nil, ErrTooFewBoundaries
+  }
+  t := make([]time.Time, n+1)
+  for i := range t {
+    t[i] = start.Add(time.Duration(i)*d)
+  }
+  return NewTimeBinning(t, y, sum, less, options...)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// First boundary of the binning, which is the origin of the
+// underlying binning
+func (obj *TimeBinning) Origin() time.Time {
+  return time.Time(obj.axis.coordinates[0].(TimeCoordinate))
+}
+
+// Convert a time stamp to a position of the underlying binning
+func (obj *TimeBinning) ToFloat(t time.Time) float64 {
+  return TimeCoordinate(t).Sub(obj.axis.coordinates[0])
+}
+
+// Convert a position of the underlying binning to a time stamp with
+// nanosecond resolution
+func (obj *TimeBinning) ToTime(x float64) time.Time {
+  return obj.Origin().Add(time.Duration(math.Round(x)))
+}
+
+// Lower boundary of bin
+func (obj *TimeBinning) Lower(bin *Bin) time.Time {
+  return time.Time(obj.axis.Lower(bin).(TimeCoordinate))
+}
+
+// Upper boundary of bin
+func (obj *TimeBinning) Upper(bin *Bin) time.Time {
+  return time.Time(obj.axis.Upper(bin).(TimeCoordinate))
+}
+
+// Returns the boundaries of all bins
+func (obj *TimeBinning) Boundaries() []time.Time {
+  x := obj.axis.Boundaries()
+  r := make([]time.Time, len(x))
+  for i := range x {
+    r[i] = time.Time(x[i].(TimeCoordinate))
+  }
+  return r
+}
+
+// Find the bin containing t, see Binning.FindBin
+func (obj *TimeBinning) FindBin(t time.Time) *Bin {
+  return obj.Binning.FindBin(obj.ToFloat(t))
+}
+
+func (obj *TimeBinning) String() string {
+  r := ""
+  for at := obj.Binning.First; at != nil; at = obj.Binning.Next(at) {
+    if at != obj.Binning.First {
+      r += " "
+    }
+    r += fmt.Sprintf("[%s, %s):%f", obj.Lower(at).Format(time.RFC3339Nano), obj.Upper(at).Format(time.RFC3339Nano), at.Y)
+  }
+  return r
+}
@@ -0,0 +1,53 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "time"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestTimeBinning1(t *testing.T) {
+
+  t0 := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+  y  := []float64{1, 2, 3, 4, 5, 6}
+
+  binning, err := NewTimeBinningFixed(t0, time.Minute, 6, y, BinSum, BinLessY)
+  if err != nil {
+    t.Error("test failed"); return
+  }
+  if bin := binning.FindBin(t0.Add(90*time.Second)); bin == nil || bin.Y != 2 || !binning.Lower(bin).Equal(t0.Add(time.Minute)) {
+    t.Error("test failed")
+  }
+  binning.Binning.FilterBins(3)
+
+  r := binning.Boundaries()
+  if len(r) != 4 || !r[0].Equal(t0) || !r[3].Equal(t0.Add(6*time.Minute)) {
+    t.Error("test failed")
+  }
+  if !binning.ToTime(binning.ToFloat(t0.Add(time.Hour))).Equal(t0.Add(time.Hour)) {
+    t.Error("test failed")
+  }
+  if binning.String() == "" {
+    t.Error("test failed")
+  }
+  if _, err := NewTimeBinningFixed(t0, 0, 6, y, BinSum, BinLessY); err == nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,284 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "bufio"
+import "fmt"
+import "io"
+import "math"
+import "sort"
+import "strconv"
+import "strings"
+
+/* -------------------------------------------------------------------------- */
+
+// Record of a single merge in the trace
+type traceRecord struct {
+  // index of the deleted bin in the initial binning or -1 if its lower
+  // boundary is not an initial boundary
+  bin   int
+  // lower boundary of the deleted bin
+  lower float64
+  // deleted bin was merged with its left neighbor
+  left  bool
+  // key (see WithKey) or value of the deleted bin
+  cost  float64
+}
+
+// Initial state of a binning and all subsequent merges
+type mergeTrace struct {
+  boundaries []float64
+  values     []float64
+  merges     []traceRecord
+  // bins were modified other than by merging
+  invalid    bool
+}
+
+// Header of exported traces
+const traceHeader = "# smartBinning merge trace"
+
+/* -------------------------------------------------------------------------- */
+
+// Record the initial binning and the exact sequence of merges, so that the
+// result can be exported with ExportTrace and reproduced with ReplayTrace.
+// The trace is started anew by Reset and becomes invalid if bin values are
+// modified other than by merging, i.e. by AddSample, Modified or Split.
+func WithMergeTrace() Option {
+  return func(c *config) {
+    c.trace = true
+  }
+}
+
+// Start a new trace from the current bins
+func (binning *Binning) traceReset() {
+  if !binning.config.trace {
+    return
+  }
+  t := &mergeTrace{}
+  for i := range binning.Bins {
+    t.boundaries = append(t.boundaries, binning.Bins[i].Lower)
+    t.values     = append(t.values,     binning.Bins[i].Y)
+  }
+  t.boundaries = append(t.boundaries, binning.Last.Upper)
+  binning.trace = t
+}
+
+// Append the merge of deleted into bin to the trace, where prev and next
+// were the neighbors of deleted
+func (binning *Binning) traceMerge(deleted, bin, prev, next *Bin) {
+  t := binning.trace
+  r := traceRecord{bin: -1, lower: deleted.Lower, cost: deleted.Y}
+  if i := sort.SearchFloat64s(t.boundaries, deleted.Lower); i < len(t.boundaries)-1 && t.boundaries[i] == deleted.Lower {
+    r.bin = i
+  }
+  r.left = bin != next && (prev == nil || bin == prev)
+  if binning.config.key != nil {
+    r.cost = deleted.key
+  }
+  t.merges = append(t.merges, r)
+}
+
+func (binning *Binning) traceInvalidate() {
+  if binning.trace != nil {
+    binning.trace.invalid = true
+  }
+}
+
+// Remove the last merge from the trace
+func (binning *Binning) traceUndo() {
+  if t := binning.trace; t != nil && len(t.merges) > 0 {
+    t.merges = t.merges[0:len(t.merges)-1]
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func formatTraceFloat(x float64) string {
+  // hexadecimal representation is exact
+  return strconv.FormatFloat(x, 'x', -1, 64)
+}
+
+// Write the initial binning and the sequence of merges to w (see
+// WithMergeTrace). Floating point numbers are written in an exact
+// hexadecimal representation, so that ReplayTrace reproduces the binning
+// bit-for-bit.
+func (binning *Binning) ExportTrace(w io.Writer) error {
+  t := binning.trace
+  if t == nil {
+    return fmt.Errorf("%w: binning has no merge trace", ErrInvalidArgument)
+  }
+  if t.invalid {
+    return fmt.Errorf("%w: bins were modified other than by merging", ErrInvalidArgument)
+  }
+  writer := bufio.NewWriter(w)
+  fmt.Fprintln(writer, traceHeader)
+  fmt.Fprint(writer, "boundaries")
+  for _, x := range t.boundaries {
+    fmt.Fprint(writer, " ", formatTraceFloat(x))
+  }
+  fmt.Fprint(writer, "\nvalues")
+  for _, y := range t.values {
+    fmt.Fprint(writer, " ", formatTraceFloat(y))
+  }
+  fmt.Fprintln(writer)
+  for _, r := range t.merges {
+    direction := "right"
+    if r.left {
+      direction = "left"
+    }
+    fmt.Fprintf(writer, "merge %d %s %s %s\n", r.bin, formatTraceFloat(r.lower), direction, formatTraceFloat(r.cost))
+  }
+  return writer.Flush()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func parseTraceFloats(fields []string) ([]float64, error) {
+  r := make([]float64, len(fields))
+  for i := range fields {
+    v, err := strconv.ParseFloat(fields[i], 64)
+    if err != nil {
+      return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+    }
+    r[i] = v
+  }
+  return r, nil
+}
+
+func equalTraceFloats(a, b []float64) bool {
+  if len(a) != len(b) {
+    return false
+  }
+  for i := range a {
+    if math.Float64bits(a[i]) != math.Float64bits(b[i]) {
+      return false
+    }
+  }
+  return true
+}
+
+// Active bin with the given lower boundary or nil
+func (binning *Binning) traceBin(lower float64) *Bin {
+  if bin := binning.FindBin(lower); bin != nil && bin.Lower == lower {
+    return bin
+  }
+  // lower boundary of the first bin may be shifted on a circular domain
+  for _, bin := range []*Bin{binning.First, binning.Last} {
+    if bin != nil && bin.Lower == lower {
+      return bin
+    }
+  }
+  return nil
+}
+
+// Reproduce a binning from a trace written by ExportTrace. The binning must
+// have been created from the same input, i.e. its bins must be identical to
+// the initial bins of the trace. Merges are replayed in their original
+// order and in their original direction, independent of the less function
+// and merge rule of the binning. If the trace cannot be replayed, all
+// merges are reverted.
+func (binning *Binning) ReplayTrace(r io.Reader) error {
+  scanner := bufio.NewScanner(r)
+  scanner.Buffer(nil, math.MaxInt32)
+  var x, y []float64
+  var merges []traceRecord
+  for line := 1; scanner.Scan(); line++ {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+      continue
+    }
+    var err error
+    switch {
+    case fields[0] == "boundaries" && x == nil:
+      x, err = parseTraceFloats(fields[1:])
+    case fields[0] == "values" && y == nil:
+      y, err = parseTraceFloats(fields[1:])
+    case fields[0] == "merge" && len(fields) == 5:
+      record := traceRecord{}
+      if record.bin, err = strconv.Atoi(fields[1]); err != nil {
+        err = fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+        break
+      }
+      switch fields[3] {
+      case "left":
+        record.left = true
+      case "right":
+      default:
+        err = fmt.Errorf("%w: invalid direction `%s'", ErrInvalidFormat, fields[3])
+      }
+      if err != nil {
+        break
+      }
+      var v []float64
+      if v, err = parseTraceFloats([]string{fields[2], fields[4]}); err == nil {
+        record.lower, record.cost = v[0], v[1]
+        merges = append(merges, record)
+      }
+    default:
+      err = fmt.Errorf("%w: unexpected `%s'", ErrInvalidFormat, fields[0])
+    }
+    if err != nil {
+      return fmt.Errorf("line `%d': %w", line, err)
+    }
+  }
+  if err := scanner.Err(); err != nil {
+    return err
+  }
+  if x == nil || y == nil {
+    return fmt.Errorf("%w: trace has no initial bins", ErrInvalidFormat)
+  }
+  // check that the binning matches the initial bins of the trace
+  u := []float64{}
+  v := []float64{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    u = append(u, at.Lower)
+    v = append(v, at.Y)
+  }
+  if binning.Last != nil {
+    u = append(u, binning.Last.Upper)
+  }
+  if !equalTraceFloats(x, u) || !equalTraceFloats(y, v) {
+    return fmt.Errorf("%w: binning does not match the initial bins of the trace", ErrInvalidArgument)
+  }
+  tx := binning.Begin()
+  for i, record := range merges {
+    bin := binning.traceBin(record.lower)
+    if bin == nil {
+      tx.Rollback()
+      return fmt.Errorf("%w: merge `%d': no bin with lower boundary `%f'", ErrInvalidArgument, i, record.lower)
+    }
+    if record.left {
+      binning.force = -1
+    } else {
+      binning.force =  1
+    }
+    _, err := tx.Delete(bin)
+    binning.force = 0
+    if err != nil {
+      tx.Rollback()
+      return fmt.Errorf("merge `%d': %w", i, err)
+    }
+  }
+  if err := tx.Commit(); err != nil {
+    return err
+  }
+  binning.Compact()
+  return nil
+}
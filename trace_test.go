@@ -0,0 +1,92 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "bytes"
+import   "errors"
+import   "math/rand"
+import   "reflect"
+import   "strings"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestTrace1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 101)
+  y := make([]float64, 100)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(r.Intn(10))
+  }
+  for _, options := range [][]Option{{WithMergeTrace()}, {WithMergeTrace(), WithCircular()}} {
+    binning, _ := New(x, y, BinSum, BinLessY, options...)
+    binning.FilterBins(10)
+
+    buffer := bytes.Buffer{}
+    if err := binning.ExportTrace(&buffer); err != nil {
+      t.Error(err); return
+    }
+    // replay with a different less function
+    replay, _ := New(x, y, BinSum, BinLessSize, options[1:]...)
+    if err := replay.ReplayTrace(bytes.NewReader(buffer.Bytes())); err != nil {
+      t.Error(err); return
+    }
+    if !reflect.DeepEqual(binning.AppendBoundaries(nil), replay.AppendBoundaries(nil)) {
+      t.Error("test failed")
+    }
+    if !reflect.DeepEqual(binning.AppendValues(nil), replay.AppendValues(nil)) {
+      t.Error("test failed")
+    }
+    checkSkipList(t, replay)
+  }
+}
+
+func TestTrace2(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4, 5}
+  y := []float64{5, 1, 3, 2, 4}
+
+  binning, _ := New(x, y, BinSum, BinLessY, WithMergeTrace(), WithUndo(10))
+  binning.FilterBins(3)
+  binning.Undo(1)
+
+  buffer := bytes.Buffer{}
+  if err := binning.ExportTrace(&buffer); err != nil {
+    t.Error(err); return
+  }
+  if n := strings.Count(buffer.String(), "\nmerge "); n != 1 {
+    t.Error("test failed")
+  }
+  // input does not match the trace
+  other, _ := New(x, []float64{5, 1, 3, 2, 5}, BinSum, BinLessY)
+  if err := other.ReplayTrace(bytes.NewReader(buffer.Bytes())); !errors.Is(err, ErrInvalidArgument) {
+    t.Error("test failed")
+  }
+  other, _ = New(x, y, BinSum, BinLessY)
+  if err := other.ReplayTrace(strings.NewReader("boundaries 0 1\nfoo\n")); !errors.Is(err, ErrInvalidFormat) {
+    t.Error("test failed")
+  }
+  // trace becomes invalid if bins are modified
+  binning.Modified(binning.First)
+  if err := binning.ExportTrace(&buffer); err == nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,167 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Result of a statistical test
+type TestResult struct {
+  Statistic float64
+  PValue    float64
+}
+
+// Critical values of the standardized two-sample Anderson-Darling statistic
+// at the significance levels andersonDarlingLevels (Scholz and Stephens, 1987)
+var andersonDarlingCritical = []float64{0.325, 1.226, 1.961, 2.718, 3.752, 4.592, 6.546}
+var andersonDarlingLevels   = []float64{0.25, 0.1, 0.05, 0.025, 0.01, 0.005, 0.001}
+
+/* -------------------------------------------------------------------------- */
+
+// Check that both binnings have the same boundaries and return the counts of
+// both samples in each bin together with the sample sizes
+func alignedCounts(a, b *Binning) ([]float64, []float64, float64, float64, error) {
+  if !a.EqualBoundaries(b) {
+    return nil, nil, 0, 0, fmt.Errorf("%w: binnings are not aligned", ErrInvalidArgument)
+  }
+  f := a.AppendValues(nil)
+  g := b.AppendValues(nil)
+  n, m := 0.0, 0.0
+  for i := range f {
+    if !(f[i] >= 0) || !(g[i] >= 0) || math.IsInf(f[i], 1) || math.IsInf(g[i], 1) {
+      return nil, nil, 0, 0, fmt.Errorf("%w: bin values must be counts", ErrInvalidArgument)
+    }
+    n += f[i]
+    m += g[i]
+  }
+  if n <= 0 || m <= 0 {
+    return nil, nil, 0, 0, fmt.Errorf("%w: empty sample", ErrInvalidArgument)
+  }
+  return f, g, n, m, nil
+}
+
+// Two-sample Kolmogorov-Smirnov test of the samples given by the counts of
+// two binnings with identical boundaries. The statistic is the largest
+// difference between both empirical distribution functions at the bin
+// boundaries. The p-value is computed from the asymptotic Kolmogorov
+// distribution, which is conservative for binned data.
+func KolmogorovSmirnov(a, b *Binning) (TestResult, error) {
+  f, g, n, m, err := alignedCounts(a, b)
+  if err != nil {
+    return TestResult{}, err
+  }
+  d := 0.0
+  s := 0.0
+  t := 0.0
+  for i := range f {
+    s += f[i]/n
+    t += g[i]/m
+    d  = math.Max(d, math.Abs(s-t))
+  }
+  // effective sample size with the correction of Stephens (1970)
+  e := math.Sqrt(n*m/(n+m))
+  p := kolmogorovQ((e + 0.12 + 0.11/e)*d)
+  return TestResult{Statistic: d, PValue: p}, nil
+}
+
+// Two-sample Anderson-Darling test of the samples given by the counts of two
+// binnings with identical boundaries. The statistic is the version of Scholz
+// and Stephens (1987) for data with ties, where all samples in a bin are
+// tied, standardized to zero mean and unit variance under the null
+// hypothesis. The p-value is interpolated from the table of critical values
+// and hence restricted to the range [0.001, 0.25].
+func AndersonDarling(a, b *Binning) (TestResult, error) {
+  f, g, n, m, err := alignedCounts(a, b)
+  if err != nil {
+    return TestResult{}, err
+  }
+  N := n+m
+  if N < 4 {
+    return TestResult{}, fmt.Errorf("%w: at least four samples are required", ErrInvalidArgument)
+  }
+  A := 0.0
+  B := 0.0
+  M := [2]float64{}
+  for i := range f {
+    l := f[i] + g[i]
+    if l == 0 {
+      continue
+    }
+    // mid-ranks of tied samples
+    r := B + l/2
+    q := r*(N-r) - N*l/4
+    if q > 0 {
+      for j, v := range [2][2]float64{{f[i], n}, {g[i], m}} {
+        x := M[j] + v[0]/2
+        A += l/N*(N*x - r*v[1])*(N*x - r*v[1])/(q*v[1])
+      }
+    }
+    M[0] += f[i]
+    M[1] += g[i]
+    B    += l
+  }
+  A *= (N-1)/N
+  T := (A - 1)/math.Sqrt(andersonDarlingVariance(N, 1/n + 1/m))
+  return TestResult{Statistic: T, PValue: andersonDarlingPValue(T)}, nil
+}
+
+// Variance of the two-sample Anderson-Darling statistic for N samples, where
+// H is the sum of the inverse sample sizes
+func andersonDarlingVariance(N, H float64) float64 {
+  const k = 2.0
+  var h, g float64
+  if n := int(math.Round(N)); n <= 10000000 {
+    // h = sum_{i=1}^{N-1} 1/i, g = sum_{i<j<N} 1/((N-i) j)
+    s := 0.0
+    for j := 2; j < n; j++ {
+      s += 1/float64(n-j+1)
+      g += s/float64(j)
+    }
+    for i := 1; i < n; i++ {
+      h += 1/float64(i)
+    }
+  } else {
+    h = math.Log(N-1) + 0.5772156649015329 + 1/(2*(N-1))
+    g = math.Pi*math.Pi/6
+  }
+  a := (4*g - 6)*(k - 1) + (10 - 6*g)*H
+  b := (2*g - 4)*k*k + 8*h*k + (2*g - 14*h - 4)*H - 8*h + 4*g - 6
+  c := (6*h + 2*g - 2)*k*k + (4*h - 4*g + 6)*k + (2*h - 6)*H + 4*h
+  d := (2*h + 6)*k*k - 4*h*k
+  return (a*N*N*N + b*N*N + c*N + d)/((N - 1)*(N - 2)*(N - 3))
+}
+
+// Interpolate the logarithm of the significance level linearly between
+// critical values
+func andersonDarlingPValue(t float64) float64 {
+  x := andersonDarlingCritical
+  y := andersonDarlingLevels
+  if t <= x[0] {
+    return y[0]
+  }
+  for i := 1; i < len(x); i++ {
+    if t <= x[i] {
+      w := (t - x[i-1])/(x[i] - x[i-1])
+      return math.Exp((1-w)*math.Log(y[i-1]) + w*math.Log(y[i]))
+    }
+  }
+  return y[len(y)-1]
+}
@@ -0,0 +1,62 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestTwoSample1(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3, 4, 5}
+  a, _ := New(x, []float64{10, 20, 40, 20, 10}, BinSum, BinLessSize)
+  b, _ := New(x, []float64{10, 20, 40, 20, 10}, BinSum, BinLessSize)
+  c, _ := New(x, []float64{40, 30, 20,  5,  5}, BinSum, BinLessSize)
+  d, _ := New([]float64{0, 1, 2, 3, 5}, []float64{10, 20, 40, 30}, BinSum, BinLessSize)
+
+  if r, err := KolmogorovSmirnov(a, b); err != nil || r.Statistic != 0 || r.PValue != 1 {
+    t.Error("test failed")
+  }
+  if r, err := KolmogorovSmirnov(a, c); err != nil || math.Abs(r.Statistic - 0.4) > 1e-12 || r.PValue > 1e-5 {
+    t.Error("test failed")
+  }
+  if r, err := AndersonDarling(a, b); err != nil || r.Statistic > 0 || r.PValue != 0.25 {
+    t.Error("test failed")
+  }
+  if r, err := AndersonDarling(a, c); err != nil || r.Statistic < 6.546 || r.PValue != 0.001 {
+    t.Error("test failed")
+  }
+  if _, err := KolmogorovSmirnov(a, d); err == nil {
+    t.Error("test failed")
+  }
+  if _, err := AndersonDarling(a, d); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestTwoSample2(t *testing.T) {
+  // continuity of both series
+  if math.Abs(kolmogorovQ(1.18 - 1e-12) - kolmogorovQ(1.18)) > 1e-10 {
+    t.Error("test failed")
+  }
+  if math.Abs(kolmogorovQ(1.0) - 0.26999967) > 1e-7 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,151 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+/* -------------------------------------------------------------------------- */
+
+// Tx groups modifications of a binning, which are either committed or
+// rolled back as a whole. Events of modifications (see Subscribe) and log
+// entries (see WithLogger) are delivered when the transaction is committed
+// and dropped on rollback.
+// Transactions may be nested, but must be finished in reverse order.
+type Tx struct {
+  binning   *Binning
+  parent    *Tx
+  events    []Event
+  logs      []logRecord
+  done      bool
+  // state of the binning at the beginning of the transaction
+  bins      binList
+  skipHead  []int32
+  skipLinks []skipLink
+  skipSeed  uint64
+  dirty     []int32
+  undo      []mergeRecord
+  trace     *mergeTrace
+  traceLen  int
+  invalid   bool
+  deleted   int
+  first     int32
+  last      int32
+  smallest  int32
+  largest   int32
+}
+
+// Start a transaction. The state of the binning is copied, which requires
+// O(n) operations and memory.
+func (binning *Binning) Begin() *Tx {
+  position := func(b *Bin) int32 {
+    if b == nil {
+      return noBin
+    }
+    return b.index
+  }
+  tx := &Tx{
+    binning  : binning,
+    parent   : binning.tx,
+    bins     : append(binList  (nil), binning.Bins...),
+    skipHead : append([]int32   (nil), binning.skipHead...),
+    skipLinks: append([]skipLink(nil), binning.skipLinks...),
+    skipSeed : binning.skipSeed,
+    dirty    : append([]int32   (nil), binning.dirty...),
+    undo     : append([]mergeRecord(nil), binning.undo...),
+    deleted  : binning.deleted,
+    first    : position(binning.First),
+    last     : position(binning.Last),
+    smallest : position(binning.Smallest),
+    largest  : position(binning.Largest) }
+  if t := binning.trace; t != nil {
+    tx.trace    = t
+    tx.traceLen = len(t.merges)
+    tx.invalid  = t.invalid
+  }
+  binning.tx = tx
+  return tx
+}
+
+func (tx *Tx) active() bool {
+  return !tx.done && tx.binning.tx == tx
+}
+
+// Delete a bin within the transaction (see Binning.Delete)
+func (tx *Tx) Delete(bin *Bin) (*Bin, error) {
+  if !tx.active() {
+    return nil, ErrTxDone
+  }
+  return tx.binning.Delete(bin)
+}
+
+// Split a bin within the transaction (see Binning.Split)
+func (tx *Tx) Split(bin *Bin, x float64) (*Bin, *Bin, error) {
+  if !tx.active() {
+    return nil, nil, ErrTxDone
+  }
+  return tx.binning.Split(bin, x)
+}
+
+// Keep all modifications and deliver their events
+func (tx *Tx) Commit() error {
+  if !tx.active() {
+    return ErrTxDone
+  }
+  tx.done = true
+  tx.binning.tx = tx.parent
+  for _, event := range tx.events {
+    tx.binning.emit(event)
+  }
+  for _, r := range tx.logs {
+    tx.binning.log(r.event, r.keyvals...)
+  }
+  tx.events = nil
+  tx.logs   = nil
+  return nil
+}
+
+// Restore the state of the binning at the beginning of the transaction. All
+// pointers to bins become invalid.
+func (tx *Tx) Rollback() error {
+  if !tx.active() {
+    return ErrTxDone
+  }
+  binning := tx.binning
+  binning.Bins      = append(binning.Bins     [0:0], tx.bins...)
+  binning.skipHead  = append(binning.skipHead [0:0], tx.skipHead...)
+  binning.skipLinks = append(binning.skipLinks[0:0], tx.skipLinks...)
+  binning.dirty     = append(binning.dirty    [0:0], tx.dirty...)
+  binning.undo      = append(binning.undo     [0:0], tx.undo...)
+  binning.skipSeed  = tx.skipSeed
+  binning.deleted   = tx.deleted
+  binning.First     = binning.bin(tx.first)
+  binning.Last      = binning.bin(tx.last)
+  binning.Smallest  = binning.bin(tx.smallest)
+  binning.Largest   = binning.bin(tx.largest)
+  binning.tx        = tx.parent
+  if t := tx.trace; t != nil {
+    t.merges  = t.merges[0:tx.traceLen]
+    t.invalid = tx.invalid
+  }
+  binning.trace     = tx.trace
+  binning.indexInvalidate()
+  tx.done   = true
+  tx.events = nil
+  tx.logs   = nil
+  binning.log("rollback", "bins", len(binning.Bins) - binning.deleted)
+  return binning.check("rollback")
+}
@@ -0,0 +1,84 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestTx1(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 4, 5}, []float64{1, 5, 2, 8}, BinSum, BinLessY)
+  s := binning.String()
+
+  events := 0
+  binning.Subscribe(func(Event) { events++ })
+
+  tx := binning.Begin()
+  if _, err := tx.Delete(binning.Smallest); err != nil {
+    t.Error("test failed")
+  }
+  if _, _, err := tx.Split(binning.FindBin(3), 3); err != nil {
+    t.Error("test failed")
+  }
+  if events != 0 {
+    t.Error("test failed")
+  }
+  if err := tx.Rollback(); err != nil {
+    t.Error("test failed")
+  }
+  if binning.String() != s || events != 0 {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+  if _, err := tx.Delete(binning.Smallest); err != ErrTxDone {
+    t.Error("test failed")
+  }
+
+  tx = binning.Begin()
+  tx.Delete(binning.Smallest)
+  if err := tx.Commit(); err != nil || events != 1 {
+    t.Error("test failed")
+  }
+  if binning.String() != "[0.000000, 2.000000):6 [2.000000, 4.000000):2 [4.000000, 5.000000):8" {
+    t.Error("test failed")
+  }
+  checkSkipList(t, binning)
+}
+
+func TestTx2(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 4, 5}, []float64{1, 5, 2, 8}, BinSum, BinLessY)
+
+  outer := binning.Begin()
+  outer.Delete(binning.Smallest)
+  inner := binning.Begin()
+  inner.Delete(binning.Smallest)
+  if err := outer.Commit(); err != ErrTxDone {
+    t.Error("test failed")
+  }
+  inner.Rollback()
+  if binning.String() != "[0.000000, 2.000000):6 [2.000000, 4.000000):2 [4.000000, 5.000000):8" {
+    t.Error("test failed")
+  }
+  outer.Rollback()
+  if binning.String() != "[0.000000, 1.000000):1 [1.000000, 2.000000):5 [2.000000, 4.000000):2 [4.000000, 5.000000):8" {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,149 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// Position of a deleted bin relative to the bin it was merged with
+type undoPosition int8
+
+const (
+  // deleted bin preceded the resulting bin
+  undoBefore undoPosition = iota
+  // deleted bin followed the resulting bin
+  undoAfter
+  // deleted bin was the first bin and merged across the end of a circular
+  // domain
+  undoFirst
+  // deleted bin was the last bin and merged across the end of a circular
+  // domain
+  undoLast
+)
+
+// Record of a single merge
+type mergeRecord struct {
+  // state of the deleted bin and the resulting bin before the merge
+  deleted  Bin
+  survivor Bin
+  // boundaries of the resulting bin after the merge
+  lower    float64
+  upper    float64
+  position undoPosition
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Record the last n merges, so that they can be reversed with Undo. The log
+// is cleared whenever bin values are modified other than by merging, i.e.
+// by Reset, AddSample, Modified or Split.
+func WithUndo(n int) Option {
+  return func(c *config) {
+    c.undo = n
+  }
+}
+
+// Neighbors a bin may be merged with, which are prev and next or the bin at
+// the opposite end of a circular domain
+func (binning *Binning) undoCandidates(prev, next *Bin) (*Bin, *Bin) {
+  if binning.period > 0 {
+    if prev == nil {
+      prev = binning.Last
+    }
+    if next == nil {
+      next = binning.First
+    }
+  }
+  return prev, next
+}
+
+// Append the merge of deleted into bin to the undo log, where left and
+// right are the candidates for the merge (see undoCandidates) and deleted,
+// l and r the states of the involved bins before the merge
+func (binning *Binning) recordMerge(deleted, bin, prev, next, left, right *Bin, l, r Bin) {
+  record := mergeRecord{deleted: *deleted, survivor: l, lower: bin.Lower, upper: bin.Upper}
+  record.deleted.Deleted = false
+  if bin == right {
+    record.survivor = r
+  }
+  switch {
+  case prev == nil && bin != next:
+    record.position = undoFirst
+  case next == nil && bin != prev:
+    record.position = undoLast
+  case bin == next:
+    record.position = undoBefore
+  default:
+    record.position = undoAfter
+  }
+  binning.undo = append(binning.undo, record)
+  if n := binning.config.undo; len(binning.undo) > n {
+    binning.undo = binning.undo[len(binning.undo)-n:]
+  }
+}
+
+// Number of merges that can be reversed with Undo
+func (binning *Binning) UndoLen() int {
+  return len(binning.undo)
+}
+
+// Reverse the last k merges, restoring boundaries and values of all
+// involved bins (see WithUndo). All pointers to bins become invalid.
+func (binning *Binning) Undo(k int) error {
+  if k < 0 || k > len(binning.undo) {
+    return fmt.Errorf("%w: cannot undo `%d' of %d merges", ErrInvalidArgument, k, len(binning.undo))
+  }
+  for ; k > 0; k-- {
+    r   := binning.undo[len(binning.undo)-1]
+    bin := binning.FindBin(r.lower)
+    if bin == nil || bin.Lower != r.lower || bin.Upper != r.upper {
+      return fmt.Errorf("%w: bin [%f, %f) not found", ErrInvalidArgument, r.lower, r.upper)
+    }
+    binning.undo = binning.undo[0:len(binning.undo)-1]
+    binning.traceUndo()
+    if !bin.dirty {
+      binning.deleteBinSorted(bin)
+    }
+    bin.Y      = r.survivor.Y
+    bin.Lower  = r.survivor.Lower
+    bin.Upper  = r.survivor.Upper
+    bin.merged = r.survivor.merged
+    bin.stale  = r.survivor.stale
+    bin.Data   = r.survivor.Data
+    i := bin.index
+    j := noBin
+    switch r.position {
+    case undoBefore:
+      j = bin.prev
+    case undoAfter:
+      j = bin.index
+    case undoLast:
+      j = binning.Last.index
+    }
+    restored := binning.insertBin(j, r.deleted)
+    if i > j {
+      i++
+    }
+    binning.insertSplit(&binning.Bins[i], restored)
+    binning.log("undo", "lower", r.deleted.Lower, "upper", r.deleted.Upper, "y", r.deleted.Y)
+    binning.emit(Event{Kind: EventUndo, Bin: binning.Bins[i], Created: *restored})
+  }
+  return binning.check("undo")
+}
@@ -0,0 +1,141 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math/rand"
+import   "reflect"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestUndo1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 101)
+  y := make([]float64, 100)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(r.Intn(10))
+  }
+  for _, options := range [][]Option{{WithUndo(1000)}, {WithUndo(1000), WithCircular()}} {
+    binning, _ := New(x, y, BinSum, BinLessY, options...)
+
+    binning.FilterBins(50)
+    s := binning.String()
+    binning.FilterBins(3)
+    if binning.UndoLen() != 97 {
+      t.Error("test failed"); return
+    }
+    if err := binning.Undo(47); err != nil {
+      t.Error(err); return
+    }
+    if binning.String() != s {
+      t.Error("test failed")
+    }
+    checkSkipList(t, binning)
+    if err := binning.Undo(50); err != nil {
+      t.Error(err); return
+    }
+    if !reflect.DeepEqual(binning.AppendBoundaries(nil), x) || !reflect.DeepEqual(binning.AppendValues(nil), y) {
+      t.Error("test failed")
+    }
+    checkSkipList(t, binning)
+    if err := binning.Undo(1); err == nil {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestUndo2(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 4, 5}, []float64{1, 5, 2, 8}, BinSum, BinLessY, WithUndo(1))
+
+  binning.FilterBins(2)
+  if binning.UndoLen() != 1 {
+    t.Error("test failed")
+  }
+  binning.Undo(1)
+  if binning.String() != "[0.000000, 2.000000):6 [2.000000, 4.000000):2 [4.000000, 5.000000):8" {
+    t.Error("test failed")
+  }
+  binning.AddSample(1, 1)
+  if binning.UndoLen() != 0 {
+    t.Error("test failed")
+  }
+}
+
+// Aggregator that modifies payloads in place
+type counterAggregator struct{}
+
+type counter struct {
+  n int
+}
+
+func (c *counter) Clone() interface{} {
+  r := *c
+  return &r
+}
+
+func (counterAggregator) Merge(a, b Bin) Bin {
+  c := a.Data.(*counter)
+  c.n += b.Data.(*counter).n
+  return Bin{Y: a.Y + b.Y, Data: c}
+}
+
+func TestUndo3(t *testing.T) {
+
+  x := []float64{0, 1, 2, 3}
+  y := []float64{1, 2, 3}
+
+  binning, _ := New(x, y, nil, BinLessY, WithAggregator(StatsAggregator{}), WithUndo(2))
+  binning.FilterBins(1)
+  if err := binning.Undo(2); err != nil {
+    t.Error(err); return
+  }
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if at.Data != nil {
+      t.Error("test failed")
+    }
+  }
+  binning.FilterBins(2)
+  binning.FilterBins(1)
+  if err := binning.Undo(1); err != nil {
+    t.Error(err); return
+  }
+  if s, ok := binning.First.Data.(BinStats); !ok || s != (BinStats{Count: 2, Sum: 3, Min: 1, Max: 2}) || binning.Last.Data != nil {
+    t.Error("test failed")
+  }
+  // payloads modified in place are cloned
+  binning, _ = New(x, y, nil, BinLessY, WithAggregator(counterAggregator{}), WithUndo(2))
+  for i := range binning.Bins {
+    binning.Bins[i].Data = &counter{1}
+  }
+  binning.FilterBins(1)
+  if binning.First.Data.(*counter).n != 3 {
+    t.Error("test failed")
+  }
+  if err := binning.Undo(2); err != nil {
+    t.Error(err); return
+  }
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if at.Data.(*counter).n != 1 {
+      t.Error("test failed")
+    }
+  }
+}
@@ -0,0 +1,189 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// Validate the binning after every mutation, which is expensive and meant
+// for debugging. Mutating methods return the first violated invariant as
+// error (see Validate).
+func WithSelfCheck() Option {
+  return func(c *config) {
+    c.selfCheck = true
+  }
+}
+
+// Validate the binning after operation op if self-checking is enabled
+func (binning *Binning) check(op string) error {
+  if !binning.config.selfCheck {
+    return nil
+  }
+  if err := binning.Validate(); err != nil {
+    return fmt.Errorf("after %s: %w", op, err)
+  }
+  return nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Check all invariants of the internal data structures, i.e. the linked
+// list, the sorted list with its skip list levels and the set of modified
+// bins. The first violation is returned as ErrCorrupted with a diagnostic.
+func (binning *Binning) Validate() error {
+  corrupted := func(format string, args ...interface{}) error {
+    return fmt.Errorf("%w: %s", ErrCorrupted, fmt.Sprintf(format, args...))
+  }
+  if len(binning.Bins) == 0 {
+    return nil
+  }
+  // backing slice
+  deleted := 0
+  for i := range binning.Bins {
+    if binning.Bins[i].index != int32(i) {
+      return corrupted("bin at position %d has index %d", i, binning.Bins[i].index)
+    }
+    if binning.Bins[i].Deleted {
+      deleted++
+    }
+  }
+  if deleted != binning.deleted {
+    return corrupted("%d bins are deleted, but %d are counted", deleted, binning.deleted)
+  }
+  n := len(binning.Bins) - deleted
+  // linked list
+  if !binning.owns(binning.First) || !binning.owns(binning.Last) {
+    return corrupted("First or Last is not an element of the backing slice")
+  }
+  if binning.First.prev != noBin {
+    return corrupted("First (bin %d) has previous bin %d", binning.First.index, binning.First.prev)
+  }
+  m := 0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    if m++; m > n {
+      return corrupted("linked list has more than %d bins", n)
+    }
+    if at.Deleted {
+      return corrupted("bin %d %v in the linked list is deleted", at.index, at)
+    }
+    if !(at.Lower < at.Upper) {
+      return corrupted("bin %d %v has invalid boundaries", at.index, at)
+    }
+    if next := binning.Next(at); next == nil {
+      if at != binning.Last {
+        return corrupted("linked list ends at bin %d, but Last is bin %d", at.index, binning.Last.index)
+      }
+    } else {
+      if next.prev != at.index {
+        return corrupted("bin %d follows bin %d, but its previous bin is %d", next.index, at.index, next.prev)
+      }
+      if at.Upper != next.Lower {
+        return corrupted("bins %d %v and %d %v are not contiguous", at.index, at, next.index, next)
+      }
+    }
+  }
+  if m != n {
+    return corrupted("linked list has %d bins, expected %d", m, n)
+  }
+  // sorted list
+  dirty := make(map[int32]bool)
+  for _, i := range binning.dirty {
+    dirty[i] = true
+  }
+  k := 0
+  for i := range binning.Bins {
+    if bin := &binning.Bins[i]; !bin.Deleted && bin.dirty {
+      if !dirty[bin.index] {
+        return corrupted("modified bin %d %v is not registered", bin.index, bin)
+      }
+      k++
+    }
+  }
+  if (binning.Smallest == nil) != (binning.Largest == nil) {
+    return corrupted("only one of Smallest and Largest is set")
+  }
+  if binning.Smallest != nil {
+    if !binning.owns(binning.Smallest) || !binning.owns(binning.Largest) {
+      return corrupted("Smallest or Largest is not an element of the backing slice")
+    }
+    if binning.Smallest.smaller != noBin {
+      return corrupted("Smallest (bin %d) has smaller bin %d", binning.Smallest.index, binning.Smallest.smaller)
+    }
+  }
+  position := make(map[int32]int)
+  for at := binning.Smallest; at != nil; at = binning.Larger(at) {
+    if len(position) == n {
+      return corrupted("sorted list has more than %d bins", n)
+    }
+    if at.Deleted || at.dirty {
+      return corrupted("bin %d %v in the sorted list is deleted or modified", at.index, at)
+    }
+    if _, ok := position[at.index]; ok {
+      return corrupted("bin %d occurs twice in the sorted list", at.index)
+    }
+    position[at.index] = len(position)
+    if larger := binning.Larger(at); larger == nil {
+      if at != binning.Largest {
+        return corrupted("sorted list ends at bin %d, but Largest is bin %d", at.index, binning.Largest.index)
+      }
+    } else {
+      if larger.smaller != at.index {
+        return corrupted("bin %d is larger than bin %d, but its smaller bin is %d", larger.index, at.index, larger.smaller)
+      }
+      if binning.lessFunc(*larger, *at) {
+        return corrupted("bin %d %v is sorted before the smaller bin %d %v", at.index, at, larger.index, larger)
+      }
+    }
+  }
+  if len(position) + k != n {
+    return corrupted("sorted list has %d bins and %d bins are modified, expected %d in total", len(position), k, n)
+  }
+  // skip list levels
+  for l := range binning.skipHead {
+    at := binning.skipNext(nil, l)
+    if at == nil {
+      return corrupted("skip list level %d is empty", l)
+    }
+    if binning.skipLink(at, l).prev != noBin {
+      return corrupted("first bin %d at skip list level %d has a predecessor", at.index, l)
+    }
+    for j := 0; at != nil; at = binning.skipNext(at, l) {
+      if j++; j > n {
+        return corrupted("skip list level %d has more than %d bins", l, n)
+      }
+      if int(at.height) <= l {
+        return corrupted("bin %d at skip list level %d has height %d", at.index, l, at.height)
+      }
+      p, ok := position[at.index]
+      if !ok {
+        return corrupted("bin %d at skip list level %d is not in the sorted list", at.index, l)
+      }
+      if next := binning.skipNext(at, l); next != nil {
+        if binning.skipLink(next, l).prev != at.index {
+          return corrupted("bin %d follows bin %d at skip list level %d, but its predecessor is %d", next.index, at.index, l, binning.skipLink(next, l).prev)
+        }
+        if position[next.index] <= p {
+          return corrupted("bins %d and %d are not sorted at skip list level %d", at.index, next.index, l)
+        }
+      }
+    }
+  }
+  return nil
+}
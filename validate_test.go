@@ -0,0 +1,93 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "errors"
+import   "math/rand"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestValidate1(t *testing.T) {
+
+  r := rand.New(rand.NewSource(1))
+  x := make([]float64, 201)
+  y := make([]float64, 200)
+  for i := 1; i < len(x); i++ {
+    x[i] = x[i-1] + r.Float64()
+    y[i-1] = float64(r.Intn(10))
+  }
+  for _, options := range [][]Option{{}, {WithCircular()}, {WithUndo(100)}, {WithTieBreaking(TieBreakRightmost)}} {
+    binning, err := New(x, y, BinSum, BinLessY, append(options, WithSelfCheck())...)
+    if err != nil {
+      t.Error(err); return
+    }
+    if err := binning.FilterBins(150); err != nil {
+      t.Error(err); return
+    }
+    if err := binning.Modified(binning.First); err != nil {
+      t.Error(err); return
+    }
+    if err := binning.AddSample(x[100], 3); err != nil {
+      t.Error(err); return
+    }
+    if _, _, err := binning.Split(binning.Last, binning.Last.Lower + binning.Last.Size()/2); err != nil {
+      t.Error(err); return
+    }
+    if err := binning.Update(); err != nil {
+      t.Error(err); return
+    }
+    if err := binning.FilterBinsBatched(50, 10); err != nil {
+      t.Error(err); return
+    }
+    if err := binning.FilterBinsHeap(20); err != nil {
+      t.Error(err); return
+    }
+  }
+}
+
+func TestValidate2(t *testing.T) {
+
+  binning, _ := New([]float64{0, 1, 2, 3, 4}, []float64{1, 2, 3, 4}, BinSum, BinLessY, WithSelfCheck())
+
+  if err := binning.Validate(); err != nil {
+    t.Error(err)
+  }
+  // corrupt the pointer to the smallest bin
+  binning.Smallest = binning.Larger(binning.Smallest)
+
+  if err := binning.Validate(); !errors.Is(err, ErrCorrupted) {
+    t.Error("test failed")
+  }
+  if _, err := binning.Delete(binning.Last); !errors.Is(err, ErrCorrupted) {
+    t.Error("test failed")
+  }
+  binning, _ = New([]float64{0, 1, 2, 3, 4}, []float64{1, 2, 3, 4}, BinSum, BinLessY, WithSelfCheck())
+  binning.Smallest = binning.Larger(binning.Smallest)
+
+  if err := binning.Modified(binning.Last); !errors.Is(err, ErrCorrupted) {
+    t.Error("test failed")
+  }
+  binning, _ = New([]float64{0, 1, 2, 3, 4}, []float64{1, 2, 3, 4}, BinSum, BinLessY, WithSelfCheck())
+  binning.Smallest = binning.Larger(binning.Smallest)
+
+  if err := binning.DeleteAll([]*Bin{binning.Last}); !errors.Is(err, ErrCorrupted) {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,193 @@
+//go:build js && wasm
+// +build js,wasm
+
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Command wasm exports smart binning to JavaScript. Build with
+//
+//   GOOS=js GOARCH=wasm go build -o smartbinning.wasm ./wasm
+//
+// and load the module with wasm_exec.js from the Go distribution. The
+// following functions are registered on the global object:
+//
+//   smartBinning.newBinning(boundaries, values, method) -> handle
+//   smartBinning.fromSamples(samples, method)           -> handle
+//   smartBinning.filterBins(handle, n)
+//   smartBinning.toJSON(handle)                         -> string
+//   smartBinning.release(handle)
+//
+// where method is either "count" or "width". Functions return an Error
+// object if the call fails.
+package main
+
+/* -------------------------------------------------------------------------- */
+
+import "encoding/json"
+import "fmt"
+import "syscall/js"
+
+import "github.com/pbenner/smartBinning"
+
+/* -------------------------------------------------------------------------- */
+
+var binnings = make(map[int]*smartBinning.Binning)
+var next     = 1
+
+type jsonBin struct {
+  Lower float64 `json:"lower"`
+  Upper float64 `json:"upper"`
+  Y     float64 `json:"y"`
+}
+
+/* -------------------------------------------------------------------------- */
+
+func toFloats(v js.Value) []float64 {
+  if v.IsUndefined() || v.IsNull() {
+    return nil
+  }
+  r := make([]float64, v.Length())
+  for i := range r {
+    r[i] = v.Index(i).Float()
+  }
+  return r
+}
+
+func lessFunction(method js.Value) (func(smartBinning.Bin, smartBinning.Bin) bool, error) {
+  if method.Type() != js.TypeString {
+    return smartBinning.BinLessY, nil
+  }
+  switch method.String() {
+  case "count":
+    return smartBinning.BinLessY, nil
+  case "width":
+    return smartBinning.BinLessSize, nil
+  }
+  return nil, fmt.Errorf("invalid method `%s'", method.String())
+}
+
+func lookup(handle js.Value) (*smartBinning.Binning, error) {
+  if binning, ok := binnings[handle.Int()]; ok {
+    return binning, nil
+  }
+  return nil, fmt.Errorf("invalid binning handle `%d'", handle.Int())
+}
+
+func register(binning *smartBinning.Binning) int {
+  handle := next
+  binnings[handle] = binning
+  next++
+  return handle
+}
+
+// Wrap f as a JavaScript function that checks the number of arguments and
+// returns errors as Error objects. Panics must not escape, since they
+// terminate the Go program, hence they are also returned as Error objects,
+// e.g. panics of syscall/js caused by arguments of the wrong type.
+func wrap(nargs int, f func(args []js.Value) (interface{}, error)) js.Func {
+  return js.FuncOf(func(this js.Value, args []js.Value) (r interface{}) {
+    defer func() {
+      if p := recover(); p != nil {
+        r = js.Global().Get("Error").New(fmt.Sprint(p))
+      }
+    }()
+    if len(args) < nargs {
+      return js.Global().Get("Error").New(fmt.Sprintf("expected %d arguments", nargs))
+    }
+    r, err := f(args)
+    if err != nil {
+      return js.Global().Get("Error").New(err.Error())
+    }
+    return r
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func newBinning(args []js.Value) (interface{}, error) {
+  method := js.Undefined()
+  if len(args) > 2 {
+    method = args[2]
+  }
+  less, err := lessFunction(method)
+  if err != nil {
+    return nil, err
+  }
+  binning, err := smartBinning.New(toFloats(args[0]), toFloats(args[1]), smartBinning.BinSum, less)
+  if err != nil {
+    return nil, err
+  }
+  return register(binning), nil
+}
+
+func fromSamples(args []js.Value) (interface{}, error) {
+  method := js.Undefined()
+  if len(args) > 1 {
+    method = args[1]
+  }
+  less, err := lessFunction(method)
+  if err != nil {
+    return nil, err
+  }
+  binning, err := smartBinning.FromSamples(toFloats(args[0]), less)
+  if err != nil {
+    return nil, err
+  }
+  return register(binning), nil
+}
+
+func filterBins(args []js.Value) (interface{}, error) {
+  binning, err := lookup(args[0])
+  if err != nil {
+    return nil, err
+  }
+  return nil, binning.FilterBins(args[1].Int())
+}
+
+func toJSON(args []js.Value) (interface{}, error) {
+  binning, err := lookup(args[0])
+  if err != nil {
+    return nil, err
+  }
+  bins := []jsonBin{}
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    bins = append(bins, jsonBin{at.Lower, at.Upper, at.Y})
+  }
+  b, err := json.Marshal(bins)
+  if err != nil {
+    return nil, err
+  }
+  return string(b), nil
+}
+
+func release(args []js.Value) (interface{}, error) {
+  delete(binnings, args[0].Int())
+  return nil, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+func main() {
+  obj := js.Global().Get("Object").New()
+  obj.Set("newBinning",  wrap(2, newBinning))
+  obj.Set("fromSamples", wrap(1, fromSamples))
+  obj.Set("filterBins",  wrap(2, filterBins))
+  obj.Set("toJSON",      wrap(1, toJSON))
+  obj.Set("release",     wrap(1, release))
+  js.Global().Set("smartBinning", obj)
+  // keep the module alive
+  select {}
+}
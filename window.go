@@ -0,0 +1,138 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+type windowSample struct {
+  x float64
+  t float64
+}
+
+// Window maintains the binning of the most recent samples of a stream,
+// i.e. of at most the last Size samples that are not older than Duration.
+// Samples entering and leaving the window update the counts of the current
+// binning, and boundaries are refitted to the samples in the window after
+// every Refit new samples. A sample outside the range of the current
+// binning triggers a refit at the next call of Binning.
+type Window struct {
+  Size     int
+  Duration float64
+  Refit    int
+  strategy Strategy
+  samples  []windowSample
+  binning *Binning
+  added    int
+}
+
+// Create a window of at most size samples not older than duration, where a
+// value of zero disables the respective limit. Boundaries are fitted with
+// strategy after every refit samples.
+func NewWindow(size int, duration float64, refit int, strategy Strategy) (*Window, error) {
+  if size < 0 || duration < 0 {
+    return nil, fmt.Errorf("%w: window size and duration must be non-negative", ErrInvalidArgument)
+  }
+  if refit < 1 {
+    return nil, fmt.Errorf("%w: refit interval must be positive", ErrInvalidArgument)
+  }
+  return &Window{Size: size, Duration: duration, Refit: refit, strategy: strategy}, nil
+}
+
+// Number of samples in the window
+func (obj *Window) Len() int {
+  return len(obj.samples)
+}
+
+// Add sample x observed at time t, where times must be non-decreasing.
+// Samples that leave the window are expired. Non-finite samples are
+// ignored.
+func (obj *Window) Add(x, t float64) error {
+  if n := len(obj.samples); n > 0 && t < obj.samples[n-1].t {
+    return fmt.Errorf("%w: time `%f' is before the last sample", ErrUnsorted, t)
+  }
+  if !isFinite(x) {
+    return nil
+  }
+  obj.samples = append(obj.samples, windowSample{x, t})
+  if obj.binning != nil {
+    if obj.binning.FindBin(x) == nil {
+      obj.binning = nil
+    } else
+    if err := obj.binning.AddSample(x, 1); err != nil {
+      return err
+    }
+  }
+  if err := obj.Expire(t); err != nil {
+    return err
+  }
+  if obj.added++; obj.added >= obj.Refit {
+    return obj.fit()
+  }
+  return nil
+}
+
+// Remove all samples that are older than Duration at time t or exceed the
+// size of the window
+func (obj *Window) Expire(t float64) error {
+  i := 0
+  for ; i < len(obj.samples); i++ {
+    s := obj.samples[i]
+    if !(obj.Size > 0 && len(obj.samples)-i > obj.Size) && !(obj.Duration > 0 && t - s.t > obj.Duration) {
+      break
+    }
+    if obj.binning != nil {
+      if err := obj.binning.AddSample(s.x, -1); err != nil {
+        return err
+      }
+    }
+  }
+  obj.samples = obj.samples[i:]
+  return nil
+}
+
+func (obj *Window) fit() error {
+  obj.added = 0
+  x := make([]float64, len(obj.samples))
+  for i := range obj.samples {
+    x[i] = obj.samples[i].x
+  }
+  binning, err := obj.strategy(x)
+  if err != nil {
+    return err
+  }
+  obj.binning = binning
+  return nil
+}
+
+// Returns the binning of all samples in the window. The binning is refitted
+// if no current fit is available. The returned binning is updated by
+// subsequent calls of Add and Expire until the next refit.
+func (obj *Window) Binning() (*Binning, error) {
+  if obj.binning == nil {
+    if len(obj.samples) == 0 {
+      return nil, fmt.Errorf("%w: window is empty", ErrTooFewBoundaries)
+    }
+    if err := obj.fit(); err != nil {
+      return nil, err
+    }
+  }
+  return obj.binning, nil
+}
@@ -0,0 +1,69 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestWindow1(t *testing.T) {
+
+  window, _ := NewWindow(100, 0, 1000, EqualCountStrategy(4))
+  for i := 0; i < 100; i++ {
+    window.Add(float64(i % 10), float64(i))
+  }
+  binning, err := window.Binning()
+  if err != nil {
+    t.Error(err); return
+  }
+  // samples leaving the window decrement counts
+  for i := 100; i < 150; i++ {
+    window.Add(float64(i % 5), float64(i))
+  }
+  if window.Len() != 100 {
+    t.Error("test failed")
+  }
+  sum := 0.0
+  for at := binning.First; at != nil; at = binning.Next(at) {
+    sum += at.Y
+  }
+  if sum != 100 || binning.Aggregate(0, 10) != 100 {
+    t.Error("test failed")
+  }
+  // sample outside the range of the binning triggers a refit
+  window.Add(20, 150)
+  if b, _ := window.Binning(); b == binning || b.Last.Upper <= 20 {
+    t.Error("test failed")
+  }
+}
+
+func TestWindow2(t *testing.T) {
+
+  window, _ := NewWindow(0, 10, 1000, EqualCountStrategy(4))
+  for i := 0; i < 100; i++ {
+    window.Add(float64(i), float64(i))
+  }
+  if window.Len() != 11 {
+    t.Error("test failed")
+  }
+  window.Expire(200)
+  if window.Len() != 0 {
+    t.Error("test failed")
+  }
+}
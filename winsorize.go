@@ -0,0 +1,108 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// Limits for capping outliers before fitting boundaries. Zero values
+// disable capping.
+type Capping struct {
+  // quantiles of the lower and upper cap, e.g. 0.01 and 0.99, where zero
+  // and one disable the respective cap
+  Lower float64 `json:"lower,omitempty"`
+  Upper float64 `json:"upper,omitempty"`
+  // if positive, caps are the median minus and plus MAD times the median
+  // absolute deviation scaled to the standard deviation of a normal
+  // distribution, which overrides quantiles
+  MAD   float64 `json:"mad,omitempty"`
+}
+
+// Returns true if no cap is set
+func (c Capping) disabled() bool {
+  return c.MAD <= 0 && c.Lower <= 0 && (c.Upper <= 0 || c.Upper >= 1)
+}
+
+// Quantile of sorted values with linear interpolation
+func sortedQuantile(v []float64, q float64) float64 {
+  h := q*float64(len(v)-1)
+  i := int(math.Floor(h))
+  if i+1 >= len(v) {
+    return v[len(v)-1]
+  }
+  return v[i] + (h - float64(i))*(v[i+1] - v[i])
+}
+
+// Compute caps lo and hi of finite values in data, values below lo and
+// above hi are replaced by lo and hi respectively (see Winsorize). Disabled
+// caps are the largest finite values.
+func (c Capping) Limits(data []float64) (float64, float64, error) {
+  if !(c.Lower >= 0 && c.Lower < 1) || !(c.Upper >= 0 && c.Upper <= 1) || (c.Upper > 0 && c.Upper <= c.Lower) || math.IsNaN(c.MAD) {
+    return 0, 0, fmt.Errorf("%w: invalid capping %+v", ErrInvalidArgument, c)
+  }
+  v := make([]float64, 0, len(data))
+  for _, x := range data {
+    if isFinite(x) {
+      v = append(v, x)
+    }
+  }
+  if len(v) == 0 {
+    return 0, 0, fmt.Errorf("%w: no finite samples", ErrInvalidArgument)
+  }
+  sort.Float64s(v)
+  lo, hi := -math.MaxFloat64, math.MaxFloat64
+  if c.MAD > 0 {
+    m := sortedQuantile(v, 0.5)
+    d := make([]float64, len(v))
+    for i := range v {
+      d[i] = math.Abs(v[i] - m)
+    }
+    sort.Float64s(d)
+    s := 1.4826*sortedQuantile(d, 0.5)
+    return m - c.MAD*s, m + c.MAD*s, nil
+  }
+  if c.Lower > 0 {
+    lo = sortedQuantile(v, c.Lower)
+  }
+  if c.Upper > 0 && c.Upper < 1 {
+    hi = sortedQuantile(v, c.Upper)
+  }
+  return lo, hi, nil
+}
+
+// Replace values below lo by lo and values above hi by hi, NaN values are
+// retained. A new slice is returned.
+func Winsorize(data []float64, lo, hi float64) []float64 {
+  r := make([]float64, len(data))
+  for i, x := range data {
+    r[i] = clamp(x, lo, hi)
+  }
+  return r
+}
+
+func clamp(x, lo, hi float64) float64 {
+  switch {
+  case x < lo: return lo
+  case x > hi: return hi
+  }
+  return x
+}
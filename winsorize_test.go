@@ -0,0 +1,66 @@
+/* Copyright (C) 2016 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package smartBinning
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestWinsorize1(t *testing.T) {
+
+  data := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 1000}
+
+  lo, hi, err := Capping{Lower: 0.1, Upper: 0.9}.Limits(data)
+  if err != nil {
+    t.Error(err); return
+  }
+  if math.Abs(lo - 1.9) > 1e-12 || math.Abs(hi - 108.1) > 1e-12 {
+    t.Error("test failed")
+  }
+  lo, hi, _ = Capping{MAD: 2}.Limits(data)
+  // median 5.5, median absolute deviation 2.5
+  if math.Abs(lo - (5.5 - 2*1.4826*2.5)) > 1e-12 || math.Abs(hi - (5.5 + 2*1.4826*2.5)) > 1e-12 {
+    t.Error("test failed")
+  }
+  if r := Winsorize([]float64{-10, 5, 20, math.NaN()}, lo, hi); r[0] != lo || r[1] != 5 || r[2] != hi || !math.IsNaN(r[3]) {
+    t.Error("test failed")
+  }
+  if _, _, err := (Capping{Lower: 0.9, Upper: 0.1}).Limits(data); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestWinsorize2(t *testing.T) {
+
+  data := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 1000}
+
+  d := NewDiscretizer(EqualWidthStrategy(3))
+  d.Capping = Capping{Upper: 0.9}
+  if err := d.Fit(data); err != nil {
+    t.Error(err); return
+  }
+  // the outlier does not create an edge bin
+  if len(d.Caps) != 2 || d.Boundaries[len(d.Boundaries)-1] > 200 {
+    t.Error("test failed")
+  }
+  if r := d.Transform([]float64{1e6, -5}); r[0] != d.Len()-1 || r[1] != -1 {
+    t.Error("test failed")
+  }
+}